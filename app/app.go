@@ -66,6 +66,7 @@ func NewPocketCoreApp(genState GenesisState, keybase keys.Keybase, tmClient clie
 	// The main pocket core
 	app.pocketKeeper = pocketKeeper.NewKeeper(
 		app.Keys[pocketTypes.StoreKey],
+		app.Tkeys[pocketTypes.TStoreKey],
 		app.cdc,
 		app.accountKeeper,
 		app.nodesKeeper,
@@ -629,6 +629,33 @@ func Chains(w http.ResponseWriter, r *http.Request, _ httprouter.Params) {
 	}
 }
 
+func EvidenceDiff(w http.ResponseWriter, r *http.Request, ps httprouter.Params) {
+	value := r.URL.Query().Get("authtoken")
+	if value != app.AuthToken.Value {
+		WriteErrorResponse(w, 401, "wrong authtoken "+value)
+		return
+	}
+	var params = HeightAndAddrParams{Height: 0}
+	if err := PopModel(w, r, ps, &params); err != nil {
+		WriteErrorResponse(w, 400, err.Error())
+		return
+	}
+	if params.Height == 0 {
+		params.Height = app.PCA.BaseApp.LastBlockHeight()
+	}
+	res, err := app.PCA.QueryEvidenceCacheDiff(params.Address, params.Height)
+	if err != nil {
+		WriteErrorResponse(w, 400, err.Error())
+		return
+	}
+	j, err := app.Codec().MarshalJSON(res)
+	if err != nil {
+		WriteErrorResponse(w, 400, err.Error())
+		return
+	}
+	WriteJSONResponse(w, string(j), r.URL.Path, r.Host)
+}
+
 func NodeParams(w http.ResponseWriter, r *http.Request, ps httprouter.Params) {
 	var params = HeightParams{Height: 0}
 	if err := PopModel(w, r, ps, &params); err != nil {
@@ -261,6 +261,22 @@ func (app PocketCoreApp) QueryHostedChains() (res map[string]pocketTypes.HostedB
 	return app.pocketKeeper.GetHostedBlockchains().M, nil
 }
 
+func (app PocketCoreApp) QueryEvidenceCacheDiff(address string, height int64) (res pocketTypes.EvidenceCacheDiff, err error) {
+	a, err := sdk.AddressFromHex(address)
+	if err != nil {
+		return res, err
+	}
+	node, ok := pocketTypes.GlobalPocketNodes[a.String()]
+	if !ok || node == nil {
+		return res, fmt.Errorf("%s is not a local pocket node", address)
+	}
+	ctx, err := app.NewContext(height)
+	if err != nil {
+		return res, err
+	}
+	return app.pocketKeeper.DiffEvidenceCache(ctx, node)
+}
+
 func (app PocketCoreApp) SetHostedChains(req map[string]pocketTypes.HostedBlockchain) (res map[string]pocketTypes.HostedBlockchain, err error) {
 	return app.pocketKeeper.SetHostedBlockchains(req).M, nil
 }
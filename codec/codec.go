@@ -50,6 +50,22 @@ const (
 	BlockSizeModifyKey           = "BLOCK"
 	RSCALKey                     = "RSCAL"
 	VEDITKey                     = "VEDIT"
+	ProofBatchKey                = "PBATCH"
+	ClaimMaturityIndexKey        = "CMIDX"
+	ProofErrorCodesKey           = "PERRC"
+	UniformProofIndexKey         = "UPIDX"
+	RotatingProofIndexKey        = "RPIDX"
+	DelegatedClaimerKey          = "DCLAIM"
+	VerifiedRelaysKey            = "VRELS"
+	ClaimCommitmentKey           = "CCOMMIT"
+	SortedPairMerkleKey          = "SPAIR"
+	PersistedProofIndexKey       = "PPIDX"
+	CanonicalHeaderKey           = "CHKEY"
+	ClientKeyBindingKey          = "CKBND"
+	EmptyBlockHashKey            = "EBHASH"
+	SessionProofContextKey       = "SPCTX"
+	EmptyMerkleRootKey           = "EMROOT"
+	FutureSessionHeightKey       = "FSESS"
 )
 
 func GetCodecUpgradeHeight() int64 {
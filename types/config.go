@@ -18,36 +18,50 @@ type SDKConfig struct {
 }
 
 type PocketConfig struct {
-	DataDir                   string `json:"data_dir"`
-	GenesisName               string `json:"genesis_file"`
-	ChainsName                string `json:"chains_name"`
-	EvidenceDBName            string `json:"evidence_db_name"`
-	TendermintURI             string `json:"tendermint_uri"`
-	KeybaseName               string `json:"keybase_name"`
-	RPCPort                   string `json:"rpc_port"`
-	ClientBlockSyncAllowance  int    `json:"client_block_sync_allowance"`
-	MaxEvidenceCacheEntires   int    `json:"max_evidence_cache_entries"`
-	MaxSessionCacheEntries    int    `json:"max_session_cache_entries"`
-	JSONSortRelayResponses    bool   `json:"json_sort_relay_responses"`
-	RemoteCLIURL              string `json:"remote_cli_url"`
-	UserAgent                 string `json:"user_agent"`
-	ValidatorCacheSize        int64  `json:"validator_cache_size"`
-	ApplicationCacheSize      int64  `json:"application_cache_size"`
-	RPCTimeout                int64  `json:"rpc_timeout"`
-	PrometheusAddr            string `json:"pocket_prometheus_port"`
-	PrometheusMaxOpenfiles    int    `json:"prometheus_max_open_files"`
-	MaxClaimAgeForProofRetry  int    `json:"max_claim_age_for_proof_retry"`
-	ProofPrevalidation        bool   `json:"proof_prevalidation"`
-	CtxCacheSize              int    `json:"ctx_cache_size"`
-	ABCILogging               bool   `json:"abci_logging"`
-	RelayErrors               bool   `json:"show_relay_errors"`
-	DisableTxEvents           bool   `json:"disable_tx_events"`
-	Cache                     bool   `json:"-"`
-	IavlCacheSize             int64  `json:"iavl_cache_size"`
-	ChainsHotReload           bool   `json:"chains_hot_reload"`
-	GenerateTokenOnStart      bool   `json:"generate_token_on_start"`
-	LeanPocket                bool   `json:"lean_pocket"`
-	LeanPocketUserKeyFileName string `json:"lean_pocket_user_key_file"`
+	DataDir                   string   `json:"data_dir"`
+	GenesisName               string   `json:"genesis_file"`
+	ChainsName                string   `json:"chains_name"`
+	EvidenceDBName            string   `json:"evidence_db_name"`
+	TendermintURI             string   `json:"tendermint_uri"`
+	KeybaseName               string   `json:"keybase_name"`
+	RPCPort                   string   `json:"rpc_port"`
+	ClientBlockSyncAllowance  int      `json:"client_block_sync_allowance"`
+	MaxEvidenceCacheEntires   int      `json:"max_evidence_cache_entries"`
+	MaxSessionCacheEntries    int      `json:"max_session_cache_entries"`
+	JSONSortRelayResponses    bool     `json:"json_sort_relay_responses"`
+	RemoteCLIURL              string   `json:"remote_cli_url"`
+	UserAgent                 string   `json:"user_agent"`
+	ValidatorCacheSize        int64    `json:"validator_cache_size"`
+	ApplicationCacheSize      int64    `json:"application_cache_size"`
+	RPCTimeout                int64    `json:"rpc_timeout"`
+	PrometheusAddr            string   `json:"pocket_prometheus_port"`
+	PrometheusMaxOpenfiles    int      `json:"prometheus_max_open_files"`
+	MaxClaimAgeForProofRetry  int      `json:"max_claim_age_for_proof_retry"`
+	ProofPrevalidation        bool     `json:"proof_prevalidation"`
+	ClaimResendTimeout        int64    `json:"claim_resend_timeout"`
+	ProofBatchSize            int      `json:"proof_batch_size"`
+	AutoTxBroadcastMode       string   `json:"auto_tx_broadcast_mode"`
+	MinProofsForClaim         int64    `json:"min_proofs_for_claim"`
+	CompressEvidence          bool     `json:"compress_evidence"`
+	ProofMaturityBuffer       int64    `json:"proof_maturity_buffer"`
+	ProofSubmissionWindow     int64    `json:"proof_submission_window"`
+	ClaimChainAllowlist       []string `json:"claim_chain_allowlist"`
+	MaxStoredEvidence         int      `json:"max_stored_evidence"`
+	MerkleMemoryBudget        int      `json:"merkle_memory_budget"`
+	ClaimFailureThreshold     int      `json:"claim_failure_threshold"`
+	ClaimFailureCooldown      int64    `json:"claim_failure_cooldown"`
+	OfflineTxQueue            string   `json:"offline_tx_queue"`
+	ClaimSweepConcurrency     int      `json:"claim_sweep_concurrency"`
+	CtxCacheSize              int      `json:"ctx_cache_size"`
+	ABCILogging               bool     `json:"abci_logging"`
+	RelayErrors               bool     `json:"show_relay_errors"`
+	DisableTxEvents           bool     `json:"disable_tx_events"`
+	Cache                     bool     `json:"-"`
+	IavlCacheSize             int64    `json:"iavl_cache_size"`
+	ChainsHotReload           bool     `json:"chains_hot_reload"`
+	GenerateTokenOnStart      bool     `json:"generate_token_on_start"`
+	LeanPocket                bool     `json:"lean_pocket"`
+	LeanPocketUserKeyFileName string   `json:"lean_pocket_user_key_file"`
 }
 
 func (c PocketConfig) GetLeanPocketUserKeyFilePath() string {
@@ -102,6 +116,19 @@ const (
 	DefaultRPCTimeout                  = 30000
 	DefaultMaxClaimProofRetryAge       = 32
 	DefaultProofPrevalidation          = false
+	DefaultClaimResendTimeout          = 4
+	DefaultProofBatchSize              = 0
+	DefaultAutoTxBroadcastMode         = "sync"
+	DefaultMinProofsForClaim           = 0
+	DefaultCompressEvidence            = false
+	DefaultProofMaturityBuffer         = 0
+	DefaultProofSubmissionWindow       = 0
+	DefaultMaxStoredEvidence           = 0
+	DefaultMerkleMemoryBudget          = 0
+	DefaultClaimFailureThreshold       = 0
+	DefaultClaimFailureCooldown        = 20
+	DefaultOfflineTxQueue              = ""
+	DefaultClaimSweepConcurrency       = 1
 	DefaultCtxCacheSize                = 20
 	DefaultABCILogging                 = false
 	DefaultRelayErrors                 = true
@@ -137,6 +164,20 @@ func DefaultConfig(dataDir string) Config {
 			PrometheusMaxOpenfiles:    DefaultPrometheusMaxOpenFile,
 			MaxClaimAgeForProofRetry:  DefaultMaxClaimProofRetryAge,
 			ProofPrevalidation:        DefaultProofPrevalidation,
+			ClaimResendTimeout:        DefaultClaimResendTimeout,
+			ProofBatchSize:            DefaultProofBatchSize,
+			AutoTxBroadcastMode:       DefaultAutoTxBroadcastMode,
+			MinProofsForClaim:         DefaultMinProofsForClaim,
+			CompressEvidence:          DefaultCompressEvidence,
+			ProofMaturityBuffer:       DefaultProofMaturityBuffer,
+			ProofSubmissionWindow:     DefaultProofSubmissionWindow,
+			ClaimChainAllowlist:       []string{},
+			MaxStoredEvidence:         DefaultMaxStoredEvidence,
+			MerkleMemoryBudget:        DefaultMerkleMemoryBudget,
+			ClaimFailureThreshold:     DefaultClaimFailureThreshold,
+			ClaimFailureCooldown:      DefaultClaimFailureCooldown,
+			OfflineTxQueue:            DefaultOfflineTxQueue,
+			ClaimSweepConcurrency:     DefaultClaimSweepConcurrency,
 			CtxCacheSize:              DefaultCtxCacheSize,
 			ABCILogging:               DefaultABCILogging,
 			RelayErrors:               DefaultRelayErrors,
@@ -20,7 +20,9 @@ var (
 	// AdditionalParametersKeys Tracks the keys for parameter added on the live network for RC-0.9.0 and future releases
 	AdditionalParametersKeys = []string{"BlockByteSize",
 		"ServicerStakeFloorMultiplier", "ServicerStakeWeightMultiplier",
-		"ServicerStakeWeightCeiling", "ServicerStakeFloorMultiplierExponent"}
+		"ServicerStakeWeightCeiling", "ServicerStakeFloorMultiplierExponent",
+		"MaxClaimsDeletedPerBlock", "MaxProofsPerAddressPerBlock", "MaxRelaysPerSession",
+		"RelayRewardMultipliers", "RejectSelfSignedProofs"}
 )
 
 // Individual parameter store for each keeper
@@ -163,6 +163,20 @@ const (
 	BroadcastBlock
 )
 
+// ParseBroadcastType returns the broadcast type of the mode name (sync, async or block)
+func ParseBroadcastType(mode string) (BroadcastType, error) {
+	switch mode {
+	case "sync":
+		return BroadcastSync, nil
+	case "async":
+		return BroadcastAsync, nil
+	case "block":
+		return BroadcastBlock, nil
+	default:
+		return 0, fmt.Errorf("unsupported broadcast mode %q; supported modes: sync, async, block", mode)
+	}
+}
+
 // ---------------------------------------------------------------------------------------------------------------------
 // Query performs a query to a Tendermint node with the provided path.
 // It returns the result and height of the query upon success or an error if
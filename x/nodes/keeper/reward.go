@@ -45,8 +45,31 @@ func (k Keeper) RewardForRelays(ctx sdk.Ctx, relays sdk.BigInt, address sdk.Addr
 		}
 	}
 
-	var coins sdk.BigInt
+	coins := k.relaysToCoins(ctx, relays, validator, isAfterRSCAL)
 
+	toNode, toFeeCollector := k.NodeReward(ctx, coins)
+	if toNode.IsPositive() {
+		k.mint(ctx, toNode, address)
+	}
+	if toFeeCollector.IsPositive() {
+		k.mint(ctx, toFeeCollector, k.getFeePool(ctx).GetAddress())
+	}
+	return toNode
+}
+
+// EstimateRewardForRelays - Returns the coins RewardForRelays would award the node for relays at the current height, without minting them
+func (k Keeper) EstimateRewardForRelays(ctx sdk.Ctx, relays sdk.BigInt, address sdk.Address) sdk.BigInt {
+	isAfterRSCAL := k.Cdc.IsAfterNamedFeatureActivationHeight(ctx.BlockHeight(), codec.RSCALKey)
+	validator, found := k.GetValidator(ctx, address)
+	if !found && (isAfterRSCAL || k.Cdc.IsAfterNonCustodialUpgrade(ctx.BlockHeight())) {
+		return sdk.ZeroInt()
+	}
+	toNode, _ := k.NodeReward(ctx, k.relaysToCoins(ctx, relays, validator, isAfterRSCAL))
+	return toNode
+}
+
+// relaysToCoins - Returns the total coins minted for relays, before the dao and proposer allocations
+func (k Keeper) relaysToCoins(ctx sdk.Ctx, relays sdk.BigInt, validator types.Validator, isAfterRSCAL bool) (coins sdk.BigInt) {
 	//check if PIP22 is enabled, if so scale the rewards
 	if isAfterRSCAL {
 		stake := validator.GetTokens()
@@ -58,19 +81,9 @@ func (k Keeper) RewardForRelays(ctx sdk.Ctx, relays sdk.BigInt, address sdk.Addr
 		weight := bin.ToDec().FracPow(k.ServicerStakeFloorMultiplierExponent(ctx), Pip22ExponentDenominator).Quo(k.ServicerStakeWeightMultiplier(ctx))
 		coinsDecimal := k.RelaysToTokensMultiplier(ctx).ToDec().Mul(relays.ToDec()).Mul(weight)
 		//truncate back to int
-		coins = coinsDecimal.TruncateInt()
-	} else {
-		coins = k.RelaysToTokensMultiplier(ctx).Mul(relays)
+		return coinsDecimal.TruncateInt()
 	}
-
-	toNode, toFeeCollector := k.NodeReward(ctx, coins)
-	if toNode.IsPositive() {
-		k.mint(ctx, toNode, address)
-	}
-	if toFeeCollector.IsPositive() {
-		k.mint(ctx, toFeeCollector, k.getFeePool(ctx).GetAddress())
-	}
-	return toNode
+	return k.RelaysToTokensMultiplier(ctx).Mul(relays)
 }
 
 // blockReward - Handles distribution of the collected fees
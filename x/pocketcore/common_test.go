@@ -65,6 +65,7 @@ func createTestInput(t *testing.T, isCheckTx bool) (sdk.Ctx, nodesKeeper.Keeper,
 	nodesKey := sdk.NewKVStoreKey(nodesTypes.StoreKey)
 	appsKey := sdk.NewKVStoreKey(appsTypes.StoreKey)
 	pocketKey := sdk.NewKVStoreKey(types.StoreKey)
+	pocketTKey := sdk.NewTransientStoreKey(types.TStoreKey)
 
 	db := dbm.NewMemDB()
 	ms := store.NewCommitMultiStore(db, false, 5000000)
@@ -74,6 +75,7 @@ func createTestInput(t *testing.T, isCheckTx bool) (sdk.Ctx, nodesKeeper.Keeper,
 	ms.MountStoreWithDB(appsKey, sdk.StoreTypeIAVL, db)
 	ms.MountStoreWithDB(pocketKey, sdk.StoreTypeIAVL, db)
 	ms.MountStoreWithDB(tkeyParams, sdk.StoreTypeTransient, db)
+	ms.MountStoreWithDB(pocketTKey, sdk.StoreTypeTransient, db)
 	err := ms.LoadLatestVersion()
 	require.Nil(t, err)
 
@@ -123,7 +125,7 @@ func createTestInput(t *testing.T, isCheckTx bool) (sdk.Ctx, nodesKeeper.Keeper,
 	ak := auth.NewKeeper(cdc, keyAcc, accSubspace, maccPerms)
 	nk := nodesKeeper.NewKeeper(cdc, nodesKey, ak, nodesSubspace, "pos")
 	appk := appsKeeper.NewKeeper(cdc, appsKey, nk, ak, nil, appSubspace, appsTypes.ModuleName)
-	keeper := keep.NewKeeper(pocketKey, cdc, ak, nk, appk, &hb, pocketSubspace)
+	keeper := keep.NewKeeper(pocketKey, pocketTKey, cdc, ak, nk, appk, &hb, pocketSubspace)
 	kb := NewTestKeybase()
 	appk.PocketKeeper = keeper
 	_, err = kb.Create("test")
@@ -267,3 +269,12 @@ func createTestApps(ctx sdk.Ctx, numAccs int, valCoins sdk.BigInt, ak appsKeeper
 	}
 	return
 }
+
+func getRandomPubKey() crypto.Ed25519PublicKey {
+	pk := crypto.Ed25519PrivateKey{}.GenPrivateKey()
+	return pk.PublicKey().(crypto.Ed25519PublicKey)
+}
+
+func getRandomValidatorAddress() sdk.Address {
+	return sdk.Address(getRandomPubKey().Address())
+}
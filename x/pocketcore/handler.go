@@ -2,6 +2,7 @@ package pocketcore
 
 import (
 	"fmt"
+	"github.com/pokt-network/pocket-core/codec"
 	"github.com/pokt-network/pocket-core/crypto"
 	sdk "github.com/pokt-network/pocket-core/types"
 	"github.com/pokt-network/pocket-core/x/pocketcore/keeper"
@@ -27,6 +28,30 @@ func NewHandler(keeper keeper.Keeper) sdk.Handler {
 		// handle legacy proof message
 		case types.MsgProof:
 			return handleProofMsg(ctx, keeper, msg)
+		// handle proof batch message
+		case types.MsgProofBatch:
+			if !keeper.Cdc.IsAfterNamedFeatureActivationHeight(ctx.BlockHeight(), codec.ProofBatchKey) {
+				return sdk.ErrUnknownRequest(fmt.Sprintf("Unrecognized pocketcore ProtoMsg type: %v", msg.Type())).Result()
+			}
+			return handleProofBatchMsg(ctx, keeper, msg)
+		// handle the claimer delegation message
+		case types.MsgDelegateClaimer:
+			if !keeper.Cdc.IsAfterNamedFeatureActivationHeight(ctx.BlockHeight(), codec.DelegatedClaimerKey) {
+				return sdk.ErrUnknownRequest(fmt.Sprintf("Unrecognized pocketcore ProtoMsg type: %v", msg.Type())).Result()
+			}
+			return handleDelegateClaimerMsg(ctx, keeper, msg)
+		// handle the claim message sent by a delegated claimer
+		case types.MsgDelegatedClaim:
+			if !keeper.Cdc.IsAfterNamedFeatureActivationHeight(ctx.BlockHeight(), codec.DelegatedClaimerKey) {
+				return sdk.ErrUnknownRequest(fmt.Sprintf("Unrecognized pocketcore ProtoMsg type: %v", msg.Type())).Result()
+			}
+			return handleDelegatedClaimMsg(ctx, keeper, msg)
+		// handle the proof message sent by a delegated claimer
+		case types.MsgDelegatedProof:
+			if !keeper.Cdc.IsAfterNamedFeatureActivationHeight(ctx.BlockHeight(), codec.DelegatedClaimerKey) {
+				return sdk.ErrUnknownRequest(fmt.Sprintf("Unrecognized pocketcore ProtoMsg type: %v", msg.Type())).Result()
+			}
+			return handleDelegatedProofMsg(ctx, keeper, msg)
 		default:
 			errMsg := fmt.Sprintf("Unrecognized pocketcore ProtoMsg type: %v", msg.Type())
 			return sdk.ErrUnknownRequest(errMsg).Result()
@@ -42,7 +67,7 @@ func handleClaimMsg(ctx sdk.Ctx, k keeper.Keeper, msg types.MsgClaim) sdk.Result
 		return err.Result()
 	}
 	// set the claim in the world state
-	err := k.SetClaim(ctx, msg)
+	_, err := k.SetClaim(ctx, msg)
 	if err != nil {
 		return sdk.ErrInternal(err.Error()).Result()
 	}
@@ -59,7 +84,10 @@ func handleClaimMsg(ctx sdk.Ctx, k keeper.Keeper, msg types.MsgClaim) sdk.Result
 // "handleProofMsg" - General handler for the proof message
 func handleProofMsg(ctx sdk.Ctx, k keeper.Keeper, proof types.MsgProof) sdk.Result {
 	defer sdk.TimeTrack(time.Now())
-
+	// limit the number of proofs a single address can submit each block
+	if err := k.ValidateProofCount(ctx, proof.GetSigners()[0]); err != nil {
+		return err.Result()
+	}
 	// validate the claim claim
 	addr, claim, err := k.ValidateProof(ctx, proof)
 	if err != nil {
@@ -85,6 +113,8 @@ func handleProofMsg(ctx sdk.Ctx, k keeper.Keeper, proof types.MsgProof) sdk.Resu
 	if err != nil {
 		return err.Result()
 	}
+	// only a proof that succeeds is counted against the limit
+	k.IncrementProofCount(ctx, proof.GetSigners()[0])
 	// delete local evidence
 	processSelf(ctx, proof.GetSigners()[0], claim.SessionHeader, claim.EvidenceType, tokens)
 	// create the event
@@ -97,6 +127,81 @@ func handleProofMsg(ctx sdk.Ctx, k keeper.Keeper, proof types.MsgProof) sdk.Resu
 	return sdk.Result{Events: ctx.EventManager().Events()}
 }
 
+// "handleProofBatchMsg" - General handler for the proof batch message
+func handleProofBatchMsg(ctx sdk.Ctx, k keeper.Keeper, batch types.MsgProofBatch) sdk.Result {
+	return handleProofBatch(ctx, batch, func(ctx sdk.Ctx, proof types.MsgProof) sdk.Result {
+		return handleProofMsg(ctx, k, proof)
+	})
+}
+
+// "handleProofBatch" - Handles each proof of the batch with handleProof; an invalid proof rejects the batch if AllOrNothing, else only itself
+func handleProofBatch(ctx sdk.Ctx, batch types.MsgProofBatch, handleProof func(ctx sdk.Ctx, proof types.MsgProof) sdk.Result) sdk.Result {
+	defer sdk.TimeTrack(time.Now())
+	var events sdk.Events
+	var proven int
+	for _, proof := range batch.Proofs {
+		// handle each proof in its own cache so a rejected proof leaves the state as if it was sent alone
+		cacheCtx, writeCache := ctx.CacheContext()
+		res := handleProof(cacheCtx, proof)
+		if !res.IsOK() {
+			if batch.AllOrNothing {
+				return res
+			}
+			ctx.Logger().Info(fmt.Sprintf("rejected proof %d in the proof batch: %s", proof.MerkleProof.TargetIndex, res.Log))
+			continue
+		}
+		writeCache()
+		events = append(events, res.Events...)
+		proven++
+	}
+	if proven == 0 {
+		return types.NewNoValidProofsInBatchError(types.ModuleName).Result()
+	}
+	ctx.EventManager().EmitEvents(events)
+	return sdk.Result{Events: ctx.EventManager().Events()}
+}
+
+// "handleDelegateClaimerMsg" - General handler for the delegate claimer message
+func handleDelegateClaimerMsg(ctx sdk.Ctx, k keeper.Keeper, msg types.MsgDelegateClaimer) sdk.Result {
+	defer sdk.TimeTrack(time.Now())
+	k.SetClaimer(ctx, msg.ServicerAddress, msg.ClaimerAddress)
+	// create the event
+	ctx.EventManager().EmitEvents(sdk.Events{
+		sdk.NewEvent(
+			types.EventTypeDelegateClaimer,
+			sdk.NewAttribute(types.AttributeKeyValidator, msg.ServicerAddress.String()),
+			sdk.NewAttribute(types.AttributeKeyClaimer, msg.ClaimerAddress.String()),
+		),
+	})
+	return sdk.Result{Events: ctx.EventManager().Events()}
+}
+
+// "handleDelegatedClaimMsg" - Handles the claim sent by a claimer, if the servicer delegated to it
+func handleDelegatedClaimMsg(ctx sdk.Ctx, k keeper.Keeper, msg types.MsgDelegatedClaim) sdk.Result {
+	if !k.IsAuthorizedClaimer(ctx, msg.Claim.FromAddress, msg.ClaimerAddress) {
+		return types.NewUnauthorizedClaimerError(types.ModuleName, msg.Claim.FromAddress, msg.ClaimerAddress).Result()
+	}
+	// the commitment is optional, but once present it must be the servicer's
+	if len(msg.Commitment) != 0 {
+		if !k.Cdc.IsAfterNamedFeatureActivationHeight(ctx.BlockHeight(), codec.ClaimCommitmentKey) {
+			return sdk.ErrUnknownRequest("claim commitments are not active").Result()
+		}
+		if err := k.ValidateClaimCommitment(ctx, msg.Claim, msg.Commitment); err != nil {
+			return err.Result()
+		}
+	}
+	return handleClaimMsg(ctx, k, msg.Claim)
+}
+
+// "handleDelegatedProofMsg" - Handles the proof sent by a claimer, if the servicer delegated to it
+func handleDelegatedProofMsg(ctx sdk.Ctx, k keeper.Keeper, msg types.MsgDelegatedProof) sdk.Result {
+	servicer := msg.Proof.GetSigners()[0]
+	if !k.IsAuthorizedClaimer(ctx, servicer, msg.ClaimerAddress) {
+		return types.NewUnauthorizedClaimerError(types.ModuleName, servicer, msg.ClaimerAddress).Result()
+	}
+	return handleProofMsg(ctx, k, msg.Proof)
+}
+
 func processSelf(ctx sdk.Ctx, signer sdk.Address, header types.SessionHeader, evidenceType types.EvidenceType, tokens sdk.BigInt) {
 	node, ok := types.GlobalPocketNodes[signer.String()]
 	if !ok {
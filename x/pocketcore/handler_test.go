@@ -0,0 +1,206 @@
+package pocketcore
+
+import (
+	"testing"
+
+	"github.com/pokt-network/pocket-core/codec"
+	"github.com/pokt-network/pocket-core/crypto"
+	sdk "github.com/pokt-network/pocket-core/types"
+	nodesTypes "github.com/pokt-network/pocket-core/x/nodes/types"
+	"github.com/pokt-network/pocket-core/x/pocketcore/types"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHandler_ProofBatchBeforeActivation(t *testing.T) {
+	ctx, _, _, k, _ := createTestInput(t, false)
+	codec.UpgradeFeatureMap[codec.ProofBatchKey] = 0
+	res := NewHandler(k)(ctx, types.MsgProofBatch{}, nil)
+	assert.Equal(t, sdk.CodeUnknownRequest, res.Code)
+}
+
+func TestHandleProofBatch(t *testing.T) {
+	// each test proof records a claim in state, then passes if its target index is even
+	proofs := make([]types.MsgProof, 4)
+	claims := make([]types.MsgClaim, 4)
+	for i := range proofs {
+		proofs[i] = types.MsgProof{MerkleProof: types.MerkleProof{TargetIndex: int64(i)}}
+		claims[i] = types.MsgClaim{
+			SessionHeader: types.SessionHeader{
+				ApplicationPubKey:  getRandomPubKey().RawString(),
+				Chain:              "0001",
+				SessionBlockHeight: 1,
+			},
+			MerkleRoot:       types.HashRange{Hash: types.Hash([]byte("fake")), Range: types.Range{Upper: 10}},
+			TotalProofs:      10,
+			FromAddress:      getRandomValidatorAddress(),
+			EvidenceType:     types.RelayEvidence,
+			ExpirationHeight: 1000,
+		}
+	}
+	tests := []struct {
+		name         string
+		indexes      []int
+		allOrNothing bool
+		isOK         bool
+		stored       []int
+	}{
+		{name: "all valid", indexes: []int{0, 2}, isOK: true, stored: []int{0, 2}},
+		{name: "all valid, all or nothing", indexes: []int{0, 2}, allOrNothing: true, isOK: true, stored: []int{0, 2}},
+		{name: "mixed, isolates the invalid proofs", indexes: []int{0, 1, 2, 3}, isOK: true, stored: []int{0, 2}},
+		{name: "mixed, all or nothing rejects the batch", indexes: []int{0, 1, 2, 3}, allOrNothing: true, isOK: false},
+		{name: "none valid", indexes: []int{1, 3}, isOK: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx, _, _, k, _ := createTestInput(t, false)
+			batch := types.MsgProofBatch{AllOrNothing: tt.allOrNothing}
+			for _, i := range tt.indexes {
+				batch.Proofs = append(batch.Proofs, proofs[i])
+			}
+			// run the batch in a cache, like the tx does, and only commit it if the result is ok
+			txCtx, write := ctx.CacheContext()
+			res := handleProofBatch(txCtx, batch, func(ctx sdk.Ctx, proof types.MsgProof) sdk.Result {
+				i := proof.MerkleProof.TargetIndex
+				_, err := k.SetClaim(ctx, claims[i])
+				assert.Nil(t, err)
+				if i%2 != 0 {
+					return types.NewInvalidProofsError(types.ModuleName).Result()
+				}
+				ctx.EventManager().EmitEvent(sdk.NewEvent(types.EventTypeProof))
+				return sdk.Result{Events: ctx.EventManager().Events()}
+			})
+			assert.Equal(t, tt.isOK, res.IsOK())
+			if res.IsOK() {
+				write()
+				assert.Len(t, res.Events, len(tt.stored))
+			}
+			var expected []types.MsgClaim
+			for _, i := range tt.stored {
+				expected = append(expected, claims[i])
+			}
+			assert.ElementsMatch(t, expected, k.GetAllClaims(ctx))
+		})
+	}
+}
+
+func TestHandleProofMsgLimit(t *testing.T) {
+	ctx, _, _, k, _ := createTestInput(t, false)
+	// additional params are not set at genesis
+	ctx = ctx.WithBlockHeight(10)
+	p := k.GetParams(ctx)
+	p.MaxProofsPerAddressPerBlock = 1
+	k.SetParams(ctx, p)
+	proof := types.MsgProof{
+		MerkleProof:  types.MerkleProof{HashRanges: []types.HashRange{{Range: types.Range{Upper: 1}}}},
+		Leaf:         types.RelayProof{ServicerPubKey: getRandomPubKey().RawString(), SessionBlockHeight: 1, Blockchain: "0001"},
+		EvidenceType: types.RelayEvidence,
+	}
+	// a proof that fails validation (no claim exists) is not counted
+	for i := 0; i < 2; i++ {
+		res := handleProofMsg(ctx, k, proof)
+		assert.Equal(t, sdk.CodeType(types.CodeClaimNotFoundError), res.Code)
+	}
+	// once the address has a proof counted this block, the next is rejected before validation
+	k.IncrementProofCount(ctx, proof.GetSigners()[0])
+	res := handleProofMsg(ctx, k, proof)
+	assert.Equal(t, sdk.CodeType(types.CodeProofLimitExceededError), res.Code)
+}
+
+func TestHandleDelegatedClaiming(t *testing.T) {
+	ctx, _, _, k, _ := createTestInput(t, false)
+	codec.UpgradeFeatureMap[codec.DelegatedClaimerKey] = 1
+	t.Cleanup(func() { delete(codec.UpgradeFeatureMap, codec.DelegatedClaimerKey) })
+	handler := NewHandler(k)
+	servicerPubKey := getRandomPubKey()
+	servicer := sdk.Address(servicerPubKey.Address())
+	claimer, other := getRandomValidatorAddress(), getRandomValidatorAddress()
+	claim := types.MsgClaim{
+		SessionHeader: types.SessionHeader{ApplicationPubKey: getRandomPubKey().RawString(), Chain: "0001", SessionBlockHeight: 1},
+		MerkleRoot:    types.HashRange{Hash: types.Hash([]byte("fake")), Range: types.Range{Upper: 10}},
+		TotalProofs:   10,
+		FromAddress:   servicer,
+		EvidenceType:  types.RelayEvidence,
+	}
+	proof := types.MsgProof{
+		MerkleProof:  types.MerkleProof{HashRanges: []types.HashRange{{Range: types.Range{Upper: 1}}}},
+		Leaf:         types.RelayProof{ServicerPubKey: servicerPubKey.RawString(), SessionBlockHeight: 1, Blockchain: "0001"},
+		EvidenceType: types.RelayEvidence,
+	}
+	// before the delegation, no claimer may send the claims and proofs of the servicer
+	res := handler(ctx, types.MsgDelegatedClaim{Claim: claim, ClaimerAddress: claimer}, nil)
+	assert.Equal(t, sdk.CodeType(types.CodeUnauthorizedClaimerError), res.Code)
+	res = handler(ctx, types.MsgDelegatedProof{Proof: proof, ClaimerAddress: claimer}, nil)
+	assert.Equal(t, sdk.CodeType(types.CodeUnauthorizedClaimerError), res.Code)
+	// delegate to the claimer
+	res = handler(ctx, types.MsgDelegateClaimer{ServicerAddress: servicer, ClaimerAddress: claimer}, nil)
+	assert.True(t, res.IsOK(), res.Log)
+	stored, found := k.GetClaimer(ctx, servicer)
+	assert.True(t, found)
+	assert.Equal(t, claimer, stored)
+	// the authorized claimer gets through to the claim and proof handlers (which reject these for lack of a session and claim)
+	res = handler(ctx, types.MsgDelegatedClaim{Claim: claim, ClaimerAddress: claimer}, nil)
+	assert.NotEqual(t, sdk.CodeType(types.CodeUnauthorizedClaimerError), res.Code)
+	assert.Equal(t, handleClaimMsg(ctx, k, claim).Code, res.Code)
+	res = handler(ctx, types.MsgDelegatedProof{Proof: proof, ClaimerAddress: claimer}, nil)
+	assert.Equal(t, sdk.CodeType(types.CodeClaimNotFoundError), res.Code)
+	// any other signer is still rejected
+	res = handler(ctx, types.MsgDelegatedClaim{Claim: claim, ClaimerAddress: other}, nil)
+	assert.Equal(t, sdk.CodeType(types.CodeUnauthorizedClaimerError), res.Code)
+	// revoking the delegation rejects the claimer again
+	res = handler(ctx, types.MsgDelegateClaimer{ServicerAddress: servicer}, nil)
+	assert.True(t, res.IsOK(), res.Log)
+	_, found = k.GetClaimer(ctx, servicer)
+	assert.False(t, found)
+	res = handler(ctx, types.MsgDelegatedProof{Proof: proof, ClaimerAddress: claimer}, nil)
+	assert.Equal(t, sdk.CodeType(types.CodeUnauthorizedClaimerError), res.Code)
+}
+
+func TestHandler_DelegatedClaimingBeforeActivation(t *testing.T) {
+	ctx, _, _, k, _ := createTestInput(t, false)
+	for _, msg := range []sdk.Msg{types.MsgDelegateClaimer{}, types.MsgDelegatedClaim{}, types.MsgDelegatedProof{}} {
+		res := NewHandler(k)(ctx, msg, nil)
+		assert.Equal(t, sdk.CodeUnknownRequest, res.Code)
+	}
+}
+
+func TestHandleDelegatedClaimCommitment(t *testing.T) {
+	ctx, nk, _, k, _ := createTestInput(t, false)
+	codec.UpgradeFeatureMap[codec.DelegatedClaimerKey] = 1
+	t.Cleanup(func() { delete(codec.UpgradeFeatureMap, codec.DelegatedClaimerKey) })
+	// a staked servicer, whose key signs the commitment
+	servicerKey := crypto.GenerateEd25519PrivKey()
+	servicer := sdk.Address(servicerKey.PublicKey().Address())
+	nk.SetValidator(ctx, nodesTypes.NewValidator(servicer, servicerKey.PublicKey(), []string{"0001"}, "https://www.google.com:443", sdk.NewInt(10000000), servicer))
+	claimer := getRandomValidatorAddress()
+	k.SetClaimer(ctx, servicer, claimer)
+	claim := types.MsgClaim{
+		SessionHeader: types.SessionHeader{ApplicationPubKey: getRandomPubKey().RawString(), Chain: "0001", SessionBlockHeight: 1},
+		MerkleRoot:    types.HashRange{Hash: types.Hash([]byte("fake")), Range: types.Range{Upper: 10}},
+		TotalProofs:   10,
+		FromAddress:   servicer,
+		EvidenceType:  types.RelayEvidence,
+	}
+	commitment, err := servicerKey.Sign(claim.CommitmentBytes())
+	assert.Nil(t, err)
+	handler := NewHandler(k)
+	// before activation, a commitment is not accepted
+	res := handler(ctx, types.MsgDelegatedClaim{Claim: claim, ClaimerAddress: claimer, Commitment: commitment}, nil)
+	assert.Equal(t, sdk.CodeUnknownRequest, res.Code)
+	codec.UpgradeFeatureMap[codec.ClaimCommitmentKey] = 1
+	t.Cleanup(func() { delete(codec.UpgradeFeatureMap, codec.ClaimCommitmentKey) })
+	// a valid commitment gets through to the claim handler (which rejects the claim for lack of a session)
+	assert.Nil(t, k.ValidateClaimCommitment(ctx, claim, commitment))
+	res = handler(ctx, types.MsgDelegatedClaim{Claim: claim, ClaimerAddress: claimer, Commitment: commitment}, nil)
+	assert.NotEqual(t, sdk.CodeType(types.CodeInvalidClaimCommitmentError), res.Code)
+	assert.Equal(t, handleClaimMsg(ctx, k, claim).Code, res.Code)
+	// the claimer can't pair the servicer's root with another relay count
+	tampered := claim
+	tampered.TotalProofs = 1000
+	res = handler(ctx, types.MsgDelegatedClaim{Claim: tampered, ClaimerAddress: claimer, Commitment: commitment}, nil)
+	assert.Equal(t, sdk.CodeType(types.CodeInvalidClaimCommitmentError), res.Code)
+	// nor sign the commitment itself
+	forged, err := crypto.GenerateEd25519PrivKey().Sign(claim.CommitmentBytes())
+	assert.Nil(t, err)
+	res = handler(ctx, types.MsgDelegatedClaim{Claim: claim, ClaimerAddress: claimer, Commitment: forged}, nil)
+	assert.Equal(t, sdk.CodeType(types.CodeInvalidClaimCommitmentError), res.Code)
+}
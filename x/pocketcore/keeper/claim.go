@@ -1,9 +1,13 @@
 package keeper
 
 import (
+	"encoding/binary"
 	"encoding/hex"
 	"fmt"
 	"github.com/pokt-network/pocket-core/codec"
+	"math"
+	"sort"
+	"sync"
 	"time"
 
 	"github.com/pokt-network/pocket-core/crypto"
@@ -16,6 +20,15 @@ import (
 
 // "SendClaimTx" - Automatically sends a claim of work/challenge based on relays or challenges stored.
 func (k Keeper) SendClaimTx(ctx sdk.Ctx, keeper Keeper, n client.Client, node *pc.PocketNode, claimTx func(pk crypto.PrivateKey, cliCtx util.CLIContext, txBuilder auth.TxBuilder, header pc.SessionHeader, totalProofs int64, root pc.HashRange, evidenceType pc.EvidenceType) (*sdk.TxResponse, error)) {
+	if node.IsLocked() {
+		ctx.Logger().Error("skipping the claim-txs of a pocket node without a loaded private key")
+		return
+	}
+	// the auto claims are paused after consecutive failures, until the cooldown is over
+	if node.ClaimBreaker.Paused(ctx.BlockHeight()) {
+		ctx.Logger().Info("skipping the claim-txs of a pocket node whose claims are failing, until the claim_failure_cooldown is over")
+		return
+	}
 	// get the private val key (main) account from the keybase
 	address := node.GetAddress()
 	// retrieve the iterator to go through each piece of evidence in storage
@@ -32,63 +45,281 @@ func (k Keeper) SendClaimTx(ctx sdk.Ctx, keeper Keeper, n client.Client, node *p
 		}
 		// get the type of the first piece of evidence to know if we are dealing with challenge or relays
 		evidenceType := evidence.EvidenceType
+		// log with the session context so operators can trace why a session was (or was not) claimed
+		logger := ctx.Logger().With(sessionLogKeyVals(evidence.SessionHeader, evidence.NumOfProofs)...)
+		// leave the evidence of chains the node does not auto claim for the operator to handle
+		if !claimChainAllowed(evidence.SessionHeader.Chain) {
+			logger.Debug("the chain is not in the claim_chain_allowlist, so will not send the claim-tx")
+			continue
+		}
 		// get the session context
 		sessionCtx, er := ctx.PrevCtx(evidence.SessionHeader.SessionBlockHeight)
 		if er != nil {
-			ctx.Logger().Info("could not get sessionCtx in auto send claim tx, could be due to relay timing before commit is in store: " + er.Error())
+			logger.Info("could not get sessionCtx in auto send claim tx, could be due to relay timing before commit is in store: " + er.Error())
 			continue
 		}
 		// if the evidence length is less than minimum, it would not satisfy our merkle tree needs
 		if evidence.NumOfProofs < keeper.MinimumNumberOfProofs(sessionCtx) {
+			logger.Info("the evidence has less than the minimum number of proofs, so will not send the claim-tx. Deleting evidence")
 			if err := pc.DeleteEvidence(evidence.SessionHeader, evidenceType, node.EvidenceStore); err != nil {
-				ctx.Logger().Debug(err.Error())
+				logger.Debug(err.Error())
 			}
 			continue
 		}
 		if ctx.BlockHeight() <= evidence.SessionBlockHeight+k.BlocksPerSession(sessionCtx)-1 { // ensure session is over
-			ctx.Logger().Info("the session is ongoing, so will not send the claim-tx yet")
+			logger.Info("the session is ongoing, so will not send the claim-tx yet")
 			continue
 		}
 		// if the blockchain in the evidence is not supported then delete it because nodes don't get paid/challenged for unsupported blockchains
 		if !k.IsPocketSupportedBlockchain(sessionCtx.WithBlockHeight(evidence.SessionHeader.SessionBlockHeight), evidence.SessionHeader.Chain) {
-			ctx.Logger().Info(fmt.Sprintf("claim for %s blockchain isn't pocket supported, so will not send. Deleting evidence\n", evidence.SessionHeader.Chain))
+			logger.Info(fmt.Sprintf("claim for %s blockchain isn't pocket supported, so will not send. Deleting evidence\n", evidence.SessionHeader.Chain))
 			if err := pc.DeleteEvidence(evidence.SessionHeader, evidenceType, node.EvidenceStore); err != nil {
-				ctx.Logger().Debug(err.Error())
+				logger.Debug(err.Error())
 			}
 			continue
 		}
 		// check the current state to see if the unverified evidence has already been sent and processed (if so, then skip this evidence)
 		if _, found := k.GetClaim(ctx, address, evidence.SessionHeader, evidenceType); found {
+			logger.Debug("the claim is already in the world state, so will not send the claim-tx")
+			node.InFlightClaims.Delete(evidence.SessionHeader, evidenceType)
 			continue
 		}
 		// if the claim is mature, delete it because we cannot submit a mature claim
 		if k.ClaimIsMature(ctx, evidence.SessionBlockHeight) {
+			logger.Info("the claim is mature, so will not send the claim-tx. Deleting evidence")
+			node.InFlightClaims.Delete(evidence.SessionHeader, evidenceType)
 			if err := pc.DeleteEvidence(evidence.SessionHeader, evidenceType, node.EvidenceStore); err != nil {
-				ctx.Logger().Debug(err.Error())
+				logger.Debug(err.Error())
 			}
 			continue
 		}
+		// defer small sessions in case more relays are still added, unless this is the last chance to claim them
+		if evidence.NumOfProofs < pc.GlobalPocketConfig.MinProofsForClaim && !k.ClaimWindowClosing(ctx, evidence.SessionBlockHeight) {
+			logger.Info("the evidence has less than min_proofs_for_claim proofs, so will not send the claim-tx yet")
+			continue
+		}
+		// if the claim-tx was recently sent but isn't in the world state yet, give it time to land before re-sending
+		if node.InFlightClaims.Pending(evidence.SessionHeader, evidenceType, ctx.BlockHeight(), pc.GlobalPocketConfig.ClaimResendTimeout) {
+			logger.Info("the claim-tx is in flight, so will not re-send it yet")
+			continue
+		}
 		app, found := k.GetAppFromPublicKey(sessionCtx, evidence.ApplicationPubKey)
 		if !found {
-			ctx.Logger().Error(fmt.Sprintf("an error occurred creating the claim transaction with app %s not found with evidence %v", evidence.ApplicationPubKey, evidence))
+			logger.Error(fmt.Sprintf("an error occurred creating the claim transaction with app %s not found with evidence %v", evidence.ApplicationPubKey, evidence))
+		}
+		maxRelays := pc.MaxPossibleRelays(app, k.SessionNodeCount(sessionCtx)).Int64()
+		// the merkle root only covers the first maxRelays proofs, so claim for the capped amount
+		totalProofs := cappedNumOfProofs(evidence, maxRelays)
+		if totalProofs < evidence.NumOfProofs {
+			logger.Info(fmt.Sprintf("the evidence has more proofs than the max possible relays, so will claim %d of %d proofs", totalProofs, evidence.NumOfProofs))
+		}
+		// leave the sessions whose merkle tree would not fit the node's memory budget for the operator to handle
+		if budget := pc.GlobalPocketConfig.MerkleMemoryBudget; budget > 0 {
+			if estimate := pc.EstimateMerkleMemory(totalProofs); estimate > budget {
+				logger.Error(fmt.Sprintf("the merkle tree of the evidence is estimated at %d bytes, over the merkle_memory_budget of %d, so will not send the claim-tx", estimate, budget))
+				continue
+			}
 		}
 		// generate the merkle root for this evidence
-		root := evidence.GenerateMerkleRoot(evidence.SessionHeader.SessionBlockHeight, pc.MaxPossibleRelays(app, k.SessionNodeCount(sessionCtx)).Int64(), node.EvidenceStore)
+		root := evidence.GenerateMerkleRoot(evidence.SessionHeader.SessionBlockHeight, maxRelays, node.EvidenceStore)
 		claimTxTotalTime := float64(time.Since(now).Milliseconds())
 		go func() {
 			pc.GlobalServiceMetric().AddClaimTiming(evidence.SessionHeader.Chain, claimTxTotalTime, &address)
 		}()
-		// generate the auto txbuilder and clictx
-		txBuilder, cliCtx, err := newTxBuilderAndCliCtx(ctx, &pc.MsgClaim{}, n, node.PrivateKey, k)
+		// generate the auto txbuilder and clictx, signed by the delegated claimer if any (claimTx then wraps the claim)
+		var msg sdk.ProtoMsg = &pc.MsgClaim{}
+		key, delegated := k.autoTxKey(ctx, node)
+		if delegated {
+			msg = &pc.MsgDelegatedClaim{}
+		}
+		txBuilder, cliCtx, err := newTxBuilderAndCliCtx(ctx, msg, n, key, k)
 		if err != nil {
-			ctx.Logger().Error(fmt.Sprintf("an error occured creating the tx builder for the claim tx:\n%s", err.Error()))
+			logger.Error(fmt.Sprintf("an error occured creating the tx builder for the claim tx:\n%s", err.Error()))
+			claimFailed(ctx, node)
 			return
 		}
+		logger.Info("sending the claim-tx")
 		// send in the evidence header, the total relays completed, and the merkle root (ensures data integrity)
-		if _, err := claimTx(node.PrivateKey, cliCtx, txBuilder, evidence.SessionHeader, evidence.NumOfProofs, root, evidenceType); err != nil {
-			ctx.Logger().Error(fmt.Sprintf("an error occured executing the claim transaciton: \n%s", err.Error()))
+		res, err := claimTx(node.PrivateKey, cliCtx, txBuilder, evidence.SessionHeader, totalProofs, root, evidenceType)
+		if err != nil {
+			logger.Error(fmt.Sprintf("an error occured executing the claim transaciton: \n%s", err.Error()))
+			if claimFailed(ctx, node) {
+				return
+			}
+			continue
+		}
+		node.ClaimBreaker.Success()
+		addFeesSpent(node, txBuilder, res)
+		// track the claim-tx until it is confirmed; if it's dropped it will be re-sent after the timeout
+		node.InFlightClaims.Set(evidence.SessionHeader, evidenceType, ctx.BlockHeight())
+		if res != nil {
+			logger.Info("the claim-tx was sent", "txhash", res.TxHash)
+		}
+	}
+}
+
+// "claimFailed" - Counts a failed claim-tx of the node and returns true if its auto claims are now paused
+func claimFailed(ctx sdk.Ctx, node *pc.PocketNode) bool {
+	threshold, cooldown := pc.GlobalPocketConfig.ClaimFailureThreshold, pc.GlobalPocketConfig.ClaimFailureCooldown
+	if !node.ClaimBreaker.Failure(ctx.BlockHeight(), threshold, cooldown) {
+		return false
+	}
+	ctx.Logger().Error(fmt.Sprintf("%d or more consecutive claim-txs of the pocket node failed, so its claims are paused for %d blocks; check the node's balance and connectivity", threshold, cooldown))
+	return true
+}
+
+// "CompactEvidenceCache" - Deletes, in one pass, the evidence that can no longer be paid for: the claim window for the session has passed
+// and no claim for it is in the world state (the proof was already executed, the claim expired, or the claim was never sent).
+// Proven evidence is normally deleted by the proof handler, but a node that restarted or missed the block keeps it around
+func (k Keeper) CompactEvidenceCache(ctx sdk.Ctx, node *pc.PocketNode) (deleted int) {
+	address := node.GetAddress()
+	iter := pc.EvidenceIterator(node.EvidenceStore)
+	defer iter.Close()
+	for ; iter.Valid(); iter.Next() {
+		evidence := iter.Value()
+		if !k.ClaimIsMature(ctx, evidence.SessionHeader.SessionBlockHeight) {
+			continue
+		}
+		if _, found := k.GetClaim(ctx, address, evidence.SessionHeader, evidence.EvidenceType); found {
+			continue
+		}
+		if err := pc.DeleteEvidence(evidence.SessionHeader, evidence.EvidenceType, node.EvidenceStore); err != nil {
+			ctx.Logger().Debug(err.Error())
+			continue
+		}
+		node.InFlightClaims.Delete(evidence.SessionHeader, evidence.EvidenceType)
+		deleted++
+	}
+	if deleted > 0 {
+		ctx.Logger().Info(fmt.Sprintf("compacted the evidence cache of %s: deleted %d evidence(s) that can no longer be claimed or proven", address, deleted))
+	}
+	return
+}
+
+// "GetOrphanedEvidence" - Returns the session headers of the node's evidence whose session has expired: even a claim
+// sent at the end of the claim submission window would have expired by now (ClaimExpiration), so the evidence can never
+// be claimed or proven again and can be purged regardless of the world state
+func (k Keeper) GetOrphanedEvidence(ctx sdk.Ctx, node *pc.PocketNode) (headers []pc.SessionHeader) {
+	blocksPerSession := k.BlocksPerSession(ctx)
+	expiredAfter := (k.ClaimSubmissionWindow(ctx) + k.ClaimExpiration(ctx)) * blocksPerSession
+	iter := pc.EvidenceIterator(node.EvidenceStore)
+	defer iter.Close()
+	for ; iter.Valid(); iter.Next() {
+		evidence := iter.Value()
+		if ctx.BlockHeight() >= evidence.SessionBlockHeight+expiredAfter {
+			headers = append(headers, evidence.SessionHeader)
 		}
 	}
+	return
+}
+
+// "DiffEvidenceCache" - Compares the node's evidence with its claims in the world state, to diagnose relays that were
+// served but not paid: the evidence without a claim (not yet claimed, or the claim-tx failed or expired) and the claims
+// without evidence (they can't be proven). Verified claims are deleted from the world state, so they are in neither list
+func (k Keeper) DiffEvidenceCache(ctx sdk.Ctx, node *pc.PocketNode) (diff pc.EvidenceCacheDiff, err error) {
+	address := node.GetAddress()
+	claims, err := k.GetClaims(ctx, address)
+	if err != nil {
+		return diff, err
+	}
+	key := func(header pc.SessionHeader, evidenceType pc.EvidenceType) string {
+		return fmt.Sprintf("%s/%d", header.HashString(), evidenceType)
+	}
+	claimed := make(map[string]bool, len(claims))
+	for _, claim := range claims {
+		claimed[key(claim.SessionHeader, claim.EvidenceType)] = true
+	}
+	local := make(map[string]bool)
+	iter := pc.EvidenceIterator(node.EvidenceStore)
+	defer iter.Close()
+	for ; iter.Valid(); iter.Next() {
+		evidence := iter.Value()
+		local[key(evidence.SessionHeader, evidence.EvidenceType)] = true
+		if !claimed[key(evidence.SessionHeader, evidence.EvidenceType)] {
+			diff.OnlyLocal = append(diff.OnlyLocal, evidence.SessionHeader)
+		}
+	}
+	for _, claim := range claims {
+		if !local[key(claim.SessionHeader, claim.EvidenceType)] {
+			diff.OnlyChain = append(diff.OnlyChain, claim.SessionHeader)
+		}
+	}
+	return diff, nil
+}
+
+// "CapEvidenceCache" - Evicts evidence once the node holds more than MaxStoredEvidence sessions (0 is unlimited).
+// The evidence that can no longer be claimed or proven goes first, then the sessions with the fewest relays, so the
+// high-relay sessions that can still be paid for are kept; evicting one of those loses its reward, so it is logged as an
+// error. Every eviction is logged as a cache_evicted event with its session and relays, and passed to the evicted hook
+func (k Keeper) CapEvidenceCache(ctx sdk.Ctx, node *pc.PocketNode) (evicted int) {
+	maxStored := pc.GlobalPocketConfig.MaxStoredEvidence
+	if maxStored <= 0 {
+		return
+	}
+	address := node.GetAddress()
+	type storedEvidence struct {
+		evidence pc.Evidence
+		done     bool // the claim window passed and no claim is in the world state
+	}
+	var stored []storedEvidence
+	iter := pc.EvidenceIterator(node.EvidenceStore)
+	for ; iter.Valid(); iter.Next() {
+		evidence := iter.Value()
+		_, claimed := k.GetClaim(ctx, address, evidence.SessionHeader, evidence.EvidenceType)
+		stored = append(stored, storedEvidence{evidence: evidence, done: !claimed && k.ClaimIsMature(ctx, evidence.SessionBlockHeight)})
+	}
+	iter.Close()
+	excess := len(stored) - maxStored
+	if excess <= 0 {
+		return
+	}
+	sort.SliceStable(stored, func(i, j int) bool {
+		if stored[i].done != stored[j].done {
+			return stored[i].done
+		}
+		return stored[i].evidence.NumOfProofs < stored[j].evidence.NumOfProofs
+	})
+	for _, s := range stored[:excess] {
+		if err := pc.DeleteEvidence(s.evidence.SessionHeader, s.evidence.EvidenceType, node.EvidenceStore); err != nil {
+			ctx.Logger().Debug(err.Error())
+			continue
+		}
+		node.InFlightClaims.Delete(s.evidence.SessionHeader, s.evidence.EvidenceType)
+		logger := ctx.Logger().With(append([]interface{}{"event", pc.EventTypeEvidenceEvicted, "app_public_key", s.evidence.ApplicationPubKey},
+			sessionLogKeyVals(s.evidence.SessionHeader, s.evidence.NumOfProofs)...)...)
+		if s.done {
+			logger.Info("evicted the evidence of a session that can no longer be claimed or proven, as max_stored_evidence was exceeded")
+		} else {
+			logger.Error("evicted the evidence of a session that could still be claimed or proven, as max_stored_evidence was exceeded")
+		}
+		if k.evictedHook != nil {
+			k.evictedHook(ctx, node, s.evidence, !s.done)
+		}
+		evicted++
+	}
+	return
+}
+
+// "claimChainAllowed" - Returns if claims for the chain are sent automatically (an empty allowlist allows every chain)
+func claimChainAllowed(chain string) bool {
+	allowlist := pc.GlobalPocketConfig.ClaimChainAllowlist
+	if len(allowlist) == 0 {
+		return true
+	}
+	for _, c := range allowlist {
+		if c == chain {
+			return true
+		}
+	}
+	return false
+}
+
+// "cappedNumOfProofs" - Returns the number of proofs in the evidence, capped at the max possible relays for the session
+func cappedNumOfProofs(evidence pc.Evidence, maxRelays int64) int64 {
+	if maxRelays > 0 && evidence.NumOfProofs > maxRelays {
+		return maxRelays
+	}
+	return evidence.NumOfProofs
 }
 
 // "ValidateClaim" - Validates a claim message and returns an sdk error if invalid
@@ -97,10 +328,20 @@ func (k Keeper) ValidateClaim(ctx sdk.Ctx, claim pc.MsgClaim) (err sdk.Error) {
 	if claim.EvidenceType == 0 {
 		return pc.NewNoEvidenceTypeErr(pc.ModuleName)
 	}
+	// a session that has not started has no session context; once FSESS is active it is rejected with its own error
+	// (before, it fails on the session context below; the error code is part of the tx result)
+	if claim.SessionHeader.SessionBlockHeight > ctx.BlockHeight() && k.Cdc.IsAfterNamedFeatureActivationHeight(ctx.BlockHeight(), codec.FutureSessionHeightKey) {
+		return pc.NewFutureSessionHeightError(pc.ModuleName, claim.SessionHeader.SessionBlockHeight, ctx.BlockHeight())
+	}
+	// an empty or all zero root is not the root of any evidence, so the claim could never be proven; once EMROOT is active
+	// it is rejected up front (ValidateBasic only checks the hash length)
+	if claim.MerkleRoot.IsZero() && k.Cdc.IsAfterNamedFeatureActivationHeight(ctx.BlockHeight(), codec.EmptyMerkleRootKey) {
+		return pc.NewEmptyMerkleRootError(pc.ModuleName)
+	}
 	// get the session context (state info at the beginning of the session)
-	sessionContext, er := ctx.PrevCtx(claim.SessionHeader.SessionBlockHeight)
-	if er != nil {
-		return sdk.ErrInternal(er.Error())
+	_, sessionContext, err := k.GetSessionForClaim(ctx, claim)
+	if err != nil {
+		return err
 	}
 	// ensure that session ended
 	sessionEndHeight := claim.SessionHeader.SessionBlockHeight + k.BlocksPerSession(sessionContext) - 1
@@ -164,22 +405,71 @@ func (k Keeper) ValidateClaim(ctx sdk.Ctx, claim pc.MsgClaim) (err sdk.Error) {
 	return nil
 }
 
-// "SetClaim" - Sets the claim message in the state storage
-func (k Keeper) SetClaim(ctx sdk.Ctx, msg pc.MsgClaim) error {
-	// retrieve the store
-	store := ctx.KVStore(k.storeKey)
+// "ValidateClaims" - Runs the lightweight structural validation of ValidateClaim over a batch of claims, to pre-screen
+// them before the expensive session and proof verification. The result is aligned with the claims: nil for a claim
+// that passed, else the reason it failed. Stored claims carry their expiration height, so it is not checked here;
+// the chain and the minimum relays are checked against the params at the current height
+func (k Keeper) ValidateClaims(ctx sdk.Ctx, claims []pc.MsgClaim) []error {
+	errs := make([]error, len(claims))
+	minimumProofs := k.MinimumNumberOfProofs(ctx)
+	for i, claim := range claims {
+		claim.ExpirationHeight = 0
+		if err := claim.ValidateBasic(); err != nil {
+			errs[i] = err
+			continue
+		}
+		if claim.TotalProofs < minimumProofs {
+			errs[i] = pc.NewInvalidProofsError(pc.ModuleName)
+			continue
+		}
+		if !k.IsPocketSupportedBlockchain(ctx, claim.SessionHeader.Chain) {
+			errs[i] = pc.NewChainNotSupportedErr(pc.ModuleName)
+		}
+	}
+	return errs
+}
+
+// "SetClaim" - Sets the claim message in the state storage; isNew is false if the claim overwrote a stored claim of the
+// same session, so callers keying off the write (e.g. rewards) can tell a duplicate apart
+func (k Keeper) SetClaim(ctx sdk.Ctx, msg pc.MsgClaim) (isNew bool, err error) {
+	indexed := k.Cdc.IsAfterNamedFeatureActivationHeight(ctx.BlockHeight(), codec.ClaimMaturityIndexKey)
+	return k.setClaim(ctx, ctx.KVStore(k.storeKey), msg, indexed, nil)
+}
+
+// "setClaim" - Sets the claim message in the store; expirationPeriods caches the expiration period (in blocks) by session
+// block height across calls, as loading the session context is the most expensive part of setting a claim (nil disables it)
+func (k Keeper) setClaim(ctx sdk.Ctx, store sdk.KVStore, msg pc.MsgClaim, indexed bool, expirationPeriods map[int64]int64) (isNew bool, err error) {
 	// generate the store key
 	key, err := pc.KeyForClaim(ctx, msg.FromAddress, msg.SessionHeader, msg.EvidenceType)
 	if err != nil {
-		return err
+		return false, err
 	}
-	// generate the expiration height upon setting
+	// never overwrite a claim for a different session (guards against claim key derivation collisions)
+	res, _ := store.Get(key)
+	if res != nil {
+		var stored pc.MsgClaim
+		if err := k.Cdc.UnmarshalBinaryBare(res, &stored, ctx.BlockHeight()); err != nil {
+			panic(err)
+		}
+		if stored.SessionHeader.Canonical() != msg.SessionHeader.Canonical() {
+			return false, pc.NewClaimHeaderCollisionError(pc.ModuleName)
+		}
+	}
+	// generate the expiration height upon setting; it is counted from the submission height using the session-time
+	// ClaimExpiration and BlocksPerSession, and is then fixed, so a later param change never shortens or extends a pending claim
 	if msg.ExpirationHeight == 0 {
-		sessionCtx, err := ctx.PrevCtx(msg.SessionHeader.SessionBlockHeight)
-		if err != nil {
-			return err
+		period, ok := expirationPeriods[msg.SessionHeader.SessionBlockHeight]
+		if !ok {
+			sessionCtx, err := ctx.PrevCtx(msg.SessionHeader.SessionBlockHeight)
+			if err != nil {
+				return false, err
+			}
+			period = k.ClaimExpiration(sessionCtx) * k.BlocksPerSession(sessionCtx)
+			if expirationPeriods != nil {
+				expirationPeriods[msg.SessionHeader.SessionBlockHeight] = period
+			}
 		}
-		msg.ExpirationHeight = ctx.BlockHeight() + k.ClaimExpiration(sessionCtx)*k.BlocksPerSession(sessionCtx)
+		msg.ExpirationHeight = ctx.BlockHeight() + period
 	}
 	// marshal the message into amino
 	bz, err := k.Cdc.MarshalBinaryBare(&msg, ctx.BlockHeight())
@@ -188,26 +478,35 @@ func (k Keeper) SetClaim(ctx sdk.Ctx, msg pc.MsgClaim) error {
 	}
 	// set in the store
 	_ = store.Set(key, bz)
-	return nil
+	// index the claim by its session height so the mature claims are found without a scan
+	if indexed {
+		indexKey, err := pc.KeyForClaimMaturityIndex(ctx, msg.FromAddress, msg.SessionHeader, msg.EvidenceType)
+		if err != nil {
+			return false, err
+		}
+		_ = store.Set(indexKey, key)
+	}
+	return res == nil, nil
+}
+
+// "GetSessionForClaim" - Returns the session header of the claim and the context at the session block height (the world state the session was generated with)
+func (k Keeper) GetSessionForClaim(ctx sdk.Ctx, claim pc.MsgClaim) (header pc.SessionHeader, sessionCtx sdk.Ctx, err sdk.Error) {
+	sessionCtx, er := ctx.PrevCtx(claim.SessionHeader.SessionBlockHeight)
+	if er != nil {
+		return header, sessionCtx, sdk.ErrInternal(er.Error())
+	}
+	return claim.SessionHeader, sessionCtx, nil
 }
 
 // "GetClaim" - Retrieves the claim message from the store, requires the evidence type and header to return the proper claim message
 func (k Keeper) GetClaim(ctx sdk.Ctx, address sdk.Address, header pc.SessionHeader, evidenceType pc.EvidenceType) (msg pc.MsgClaim, found bool) {
-	// retrieve the store
-	store := ctx.KVStore(k.storeKey)
-	// generate the store key
-	key, err := pc.KeyForClaim(ctx, address, header, evidenceType)
-	if err != nil {
-		ctx.Logger().Error("an error occured getting the claim:\n", msg)
-		return pc.MsgClaim{}, false
-	}
-	// get the claim msg from the store
-	res, _ := store.Get(key)
-	if res == nil {
+	// get the claim msg bytes from the store
+	res, found := k.GetClaimBytes(ctx, address, header, evidenceType)
+	if !found {
 		return pc.MsgClaim{}, false
 	}
 	// unmarshal into message object
-	err = k.Cdc.UnmarshalBinaryBare(res, &msg, ctx.BlockHeight())
+	err := k.Cdc.UnmarshalBinaryBare(res, &msg, ctx.BlockHeight())
 	if err != nil {
 		panic(err)
 	}
@@ -215,12 +514,39 @@ func (k Keeper) GetClaim(ctx sdk.Ctx, address sdk.Address, header pc.SessionHead
 	return msg, true
 }
 
+// "IsClaimant" - Returns whether the address has a relay claim of the session in the world state; a claim is only stored
+// if the address was one of the servicers of the session (see ValidateClaim). A verified proof rewards and deletes the
+// claim, so the servicer of a proven session is a claimant at the heights from its claim up to its proof
+func (k Keeper) IsClaimant(ctx sdk.Ctx, address sdk.Address, header pc.SessionHeader) bool {
+	_, found := k.GetClaimBytes(ctx, address, header, pc.RelayEvidence)
+	return found
+}
+
+// "GetClaimBytes" - Retrieves the claim message bytes as stored, for callers that forward them without unmarshalling
+func (k Keeper) GetClaimBytes(ctx sdk.Ctx, address sdk.Address, header pc.SessionHeader, evidenceType pc.EvidenceType) (bz []byte, found bool) {
+	// retrieve the store
+	store := ctx.KVStore(k.storeKey)
+	// generate the store key
+	key, err := pc.KeyForClaim(ctx, address, header, evidenceType)
+	if err != nil {
+		ctx.Logger().Error("an error occured getting the claim:\n", err.Error())
+		return nil, false
+	}
+	// get the claim msg from the store
+	bz, _ = store.Get(key)
+	return bz, bz != nil
+}
+
 // "SetClaims" - Sets all the claim messages in the state storage.
-// (Needed for genesis initializing)
+// (Needed for genesis initializing) The store and the index activation are resolved once, and the session context
+// of the claims without an expiration height is loaded once per session block height instead of once per claim
 func (k Keeper) SetClaims(ctx sdk.Ctx, claims []pc.MsgClaim) {
+	store := ctx.KVStore(k.storeKey)
+	indexed := k.Cdc.IsAfterNamedFeatureActivationHeight(ctx.BlockHeight(), codec.ClaimMaturityIndexKey)
+	expirationPeriods := make(map[int64]int64)
 	// loop through all of the claim messages one by one and set them
 	for _, msg := range claims {
-		err := k.SetClaim(ctx, msg)
+		_, err := k.setClaim(ctx, store, msg, indexed, expirationPeriods)
 		if err != nil {
 			ctx.Logger().Error("an error occurred setting the claim:\n", msg)
 		}
@@ -252,6 +578,16 @@ func (k Keeper) GetClaims(ctx sdk.Ctx, address sdk.Address) (claims []pc.MsgClai
 
 // "GetAllClaims" - Gets all of the claim messages held in the state storage.
 func (k Keeper) GetAllClaims(ctx sdk.Ctx) (claims []pc.MsgClaim) {
+	k.iterateAndExecuteOverClaims(ctx, func(claim pc.MsgClaim) (stop bool) {
+		claims = append(claims, claim)
+		return false
+	})
+	return
+}
+
+// "iterateAndExecuteOverClaims" - Calls the handler with every claim held in the state storage, in store key order,
+// until it returns stop
+func (k Keeper) iterateAndExecuteOverClaims(ctx sdk.Ctx, handler func(claim pc.MsgClaim) (stop bool)) {
 	// retrieve the store
 	store := ctx.KVStore(k.storeKey)
 	// iterate through the kv in the state and unmarshal into claim objects
@@ -263,11 +599,96 @@ func (k Keeper) GetAllClaims(ctx sdk.Ctx) (claims []pc.MsgClaim) {
 		if err != nil {
 			panic(err)
 		}
-		claims = append(claims, claim)
+		if handler(claim) {
+			return
+		}
+	}
+}
+
+// "ExportProofGenesis" - Exports the outstanding claims as a versioned genesis fragment
+// The claims are read in store key order, so the same state always exports the same fragment
+func (k Keeper) ExportProofGenesis(ctx sdk.Ctx) pc.ProofGenesisState {
+	return pc.ProofGenesisState{
+		Version: pc.ProofGenesisVersion,
+		Claims:  k.GetAllClaims(ctx),
+	}
+}
+
+// "InitProofGenesis" - Sets the claims of a genesis fragment written by ExportProofGenesis
+func (k Keeper) InitProofGenesis(ctx sdk.Ctx, gs pc.ProofGenesisState) error {
+	if gs.Version != pc.ProofGenesisVersion {
+		return fmt.Errorf("unsupported proof genesis version %d, expected %d", gs.Version, pc.ProofGenesisVersion)
 	}
+	k.SetClaims(ctx, gs.Claims)
+	return nil
+}
+
+// "GetRelaysByChainAll" - Returns the total relays per chain across all relay claims held in the state storage
+// Verified proofs are not persisted (the claim is deleted once proven), so this reports the relays currently claimed.
+func (k Keeper) GetRelaysByChainAll(ctx sdk.Ctx) (relaysByChain map[string]int64) {
+	relaysByChain = make(map[string]int64)
+	k.iterateAndExecuteOverClaims(ctx, func(claim pc.MsgClaim) (stop bool) {
+		// challenge claims are not relays
+		if claim.EvidenceType == pc.RelayEvidence {
+			chain := claim.SessionHeader.Chain
+			relaysByChain[chain] = saturatingAdd(relaysByChain[chain], claim.TotalProofs)
+		}
+		return false
+	})
 	return
 }
 
+// "GetClaimsByChainAll" - Returns the claims for the chain across all addresses held in the state storage, in store key
+// order (an unknown chain returns none)
+func (k Keeper) GetClaimsByChainAll(ctx sdk.Ctx, chain string) (claims []pc.MsgClaim) {
+	claims = make([]pc.MsgClaim, 0)
+	k.iterateAndExecuteOverClaims(ctx, func(claim pc.MsgClaim) (stop bool) {
+		if claim.SessionHeader.Chain == chain {
+			claims = append(claims, claim)
+		}
+		return false
+	})
+	return
+}
+
+// "GetProofModuleStats" - Returns the aggregate statistics of the proof module in a single pass over the claims and the
+// verified relays held in the state storage. Verified claims are deleted once rewarded, so verified work is reported as
+// the relays verified since VRELS was activated; a servicer is counted once whether it holds claims, verified relays or
+// both
+func (k Keeper) GetProofModuleStats(ctx sdk.Ctx) (stats pc.ProofModuleStats) {
+	stats.Height = ctx.BlockHeight()
+	servicers, chains := make(map[string]struct{}), make(map[string]struct{})
+	k.iterateAndExecuteOverClaims(ctx, func(claim pc.MsgClaim) (stop bool) {
+		stats.TotalClaims++
+		servicers[claim.FromAddress.String()] = struct{}{}
+		chains[claim.SessionHeader.Chain] = struct{}{}
+		// challenge claims are not relays
+		if claim.EvidenceType == pc.RelayEvidence {
+			stats.TotalRelays = saturatingAdd(stats.TotalRelays, claim.TotalProofs)
+		}
+		return false
+	})
+	// the verified relays are keyed by the servicer address
+	store := ctx.KVStore(k.storeKey)
+	iterator, _ := sdk.KVStorePrefixIterator(store, pc.VerifiedRelaysKey)
+	defer iterator.Close()
+	for ; iterator.Valid(); iterator.Next() {
+		servicers[sdk.Address(iterator.Key()[len(pc.VerifiedRelaysKey):]).String()] = struct{}{}
+		stats.TotalVerifiedRelays = saturatingAdd(stats.TotalVerifiedRelays, int64(binary.BigEndian.Uint64(iterator.Value())))
+	}
+	stats.DistinctServicers = int64(len(servicers))
+	stats.DistinctChains = int64(len(chains))
+	return
+}
+
+// "saturatingAdd" - Adds two non-negative totals, saturating at math.MaxInt64 rather than overflowing
+func saturatingAdd(total, n int64) int64 {
+	if total > math.MaxInt64-n {
+		return math.MaxInt64
+	}
+	return total + n
+}
+
 // "DeleteClaim" - Removes a claim object for a certain key
 func (k Keeper) DeleteClaim(ctx sdk.Ctx, address sdk.Address, header pc.SessionHeader, evidenceType pc.EvidenceType) error {
 	// retrieve the store
@@ -279,13 +700,45 @@ func (k Keeper) DeleteClaim(ctx sdk.Ctx, address sdk.Address, header pc.SessionH
 	}
 	// delete it from the state storage
 	_ = store.Delete(key)
+	_ = store.Delete(pc.KeyForProofIndex(key))
+	// delete its maturity index entry
+	if k.Cdc.IsAfterNamedFeatureActivationHeight(ctx.BlockHeight(), codec.ClaimMaturityIndexKey) {
+		indexKey, err := pc.KeyForClaimMaturityIndex(ctx, address, header, evidenceType)
+		if err != nil {
+			return err
+		}
+		_ = store.Delete(indexKey)
+	}
 	return nil
 }
 
-// "GetMatureClaims" - Returns the mature (ready to be proved, past its security waiting period)
+// "SetClaimMaturityIndex" - Indexes all of the claims held in the state storage by address and session height;
+// called on the activation height of the index, after which SetClaim and DeleteClaim keep it up to date
+func (k Keeper) SetClaimMaturityIndex(ctx sdk.Ctx) {
+	store := ctx.KVStore(k.storeKey)
+	for _, claim := range k.GetAllClaims(ctx) {
+		key, err := pc.KeyForClaim(ctx, claim.FromAddress, claim.SessionHeader, claim.EvidenceType)
+		if err != nil {
+			ctx.Logger().Error(fmt.Sprintf("could not index the claim for app %s at session height %d: %s", claim.SessionHeader.ApplicationPubKey, claim.SessionHeader.SessionBlockHeight, err.Error()))
+			continue
+		}
+		indexKey, err := pc.KeyForClaimMaturityIndex(ctx, claim.FromAddress, claim.SessionHeader, claim.EvidenceType)
+		if err != nil {
+			ctx.Logger().Error(fmt.Sprintf("could not index the claim for app %s at session height %d: %s", claim.SessionHeader.ApplicationPubKey, claim.SessionHeader.SessionBlockHeight, err.Error()))
+			continue
+		}
+		_ = store.Set(indexKey, key)
+	}
+}
+
+// "GetMatureClaims" - Returns the mature (ready to be proved, past its security waiting period) claims,
+// oldest session first; claims of the same session height keep the store key order (session header hash, evidence type)
 func (k Keeper) GetMatureClaims(ctx sdk.Ctx, address sdk.Address) (matureProofs []pc.MsgClaim, err error) {
 	// retrieve the store
 	store := ctx.KVStore(k.storeKey)
+	if k.Cdc.IsAfterNamedFeatureActivationHeight(ctx.BlockHeight(), codec.ClaimMaturityIndexKey) {
+		return k.getMatureClaimsFromIndex(ctx, address)
+	}
 	// generate the key for the claim
 	key, err := pc.KeyForClaims(address)
 	if err != nil {
@@ -305,29 +758,455 @@ func (k Keeper) GetMatureClaims(ctx sdk.Ctx, address sdk.Address) (matureProofs
 			matureProofs = append(matureProofs, msg)
 		}
 	}
+	// the oldest claims are the closest to expiring, so they are proven first
+	sort.SliceStable(matureProofs, func(i, j int) bool {
+		return matureProofs[i].SessionHeader.SessionBlockHeight < matureProofs[j].SessionHeader.SessionBlockHeight
+	})
 	return
 }
 
+// "getMatureClaimsFromIndex" - Returns the mature claims of the address using the maturity index; only the index entries
+// up to the latest mature session height are read, already ordered by session height (then session header hash, evidence type)
+func (k Keeper) getMatureClaimsFromIndex(ctx sdk.Ctx, address sdk.Address) (matureProofs []pc.MsgClaim, err error) {
+	store := ctx.KVStore(k.storeKey)
+	// a claim is mature once the current height is past its session height plus the waiting period (see ClaimIsMature);
+	// after SPCTX the waiting period is read at each claim's session height, so every entry up to the current height is
+	// read and checked on its own
+	perSession := k.Cdc.IsAfterNamedFeatureActivationHeight(ctx.BlockHeight(), codec.SessionProofContextKey)
+	endHeight := ctx.BlockHeight() - k.ClaimSubmissionWindow(ctx)*k.BlocksPerSession(ctx)
+	if perSession {
+		endHeight = ctx.BlockHeight()
+	}
+	if endHeight <= 0 {
+		return nil, nil
+	}
+	start, err := pc.KeyForClaimMaturityIndexes(address, 0)
+	if err != nil {
+		return nil, err
+	}
+	end, err := pc.KeyForClaimMaturityIndexes(address, endHeight)
+	if err != nil {
+		return nil, err
+	}
+	iterator, _ := store.Iterator(start, end)
+	defer iterator.Close()
+	for ; iterator.Valid(); iterator.Next() {
+		bz, _ := store.Get(iterator.Value())
+		if bz == nil {
+			continue
+		}
+		var msg pc.MsgClaim
+		err = k.Cdc.UnmarshalBinaryBare(bz, &msg, ctx.BlockHeight())
+		if err != nil {
+			panic(err)
+		}
+		if perSession && !k.ClaimIsMature(ctx, msg.SessionHeader.SessionBlockHeight) {
+			continue
+		}
+		matureProofs = append(matureProofs, msg)
+	}
+	return
+}
+
+// "GetProofBacklog" - Returns how much of the address's work is waiting on a claim or proof: the evidence not claimed yet
+// (only if the address is hosted by this process, as evidence is held locally), the claims still in their waiting period
+// and the mature claims awaiting their proof
+func (k Keeper) GetProofBacklog(ctx sdk.Ctx, address sdk.Address) (backlog pc.ProofBacklog, err error) {
+	claims, err := k.GetClaims(ctx, address)
+	if err != nil {
+		return backlog, err
+	}
+	for _, claim := range claims {
+		if k.ClaimIsMature(ctx, claim.SessionHeader.SessionBlockHeight) {
+			backlog.MatureUnproven++
+		} else {
+			backlog.ImmatureClaims++
+		}
+	}
+	node, er := pc.GetPocketNodeByAddress(&address)
+	if er != nil || node.EvidenceStore == nil {
+		return backlog, nil
+	}
+	iter := pc.EvidenceIterator(node.EvidenceStore)
+	defer iter.Close()
+	for ; iter.Valid(); iter.Next() {
+		evidence := iter.Value()
+		if _, found := k.GetClaim(ctx, address, evidence.SessionHeader, evidence.EvidenceType); !found {
+			backlog.PendingClaims++
+		}
+	}
+	return backlog, nil
+}
+
+// "GetExpiringClaims" - Returns the claims of the address that expire within the number of sessions from the current height
+// (a claim expiring exactly on the boundary block is included); claims are deleted at the beginning of their expiration height
+func (k Keeper) GetExpiringClaims(ctx sdk.Ctx, address sdk.Address, withinSessions int64) (expiring []pc.MsgClaim, err error) {
+	claims, err := k.GetClaims(ctx, address)
+	if err != nil {
+		return nil, err
+	}
+	window := withinSessions * k.BlocksPerSession(ctx)
+	for _, claim := range claims {
+		if claim.ExpirationHeight-ctx.BlockHeight() <= window {
+			expiring = append(expiring, claim)
+		}
+	}
+	return
+}
+
+// "GetImmatureClaims" - Returns the claims of the address still in their waiting period with the number of blocks until
+// each is mature (see ClaimMaturityHeight), soonest first
+func (k Keeper) GetImmatureClaims(ctx sdk.Ctx, address sdk.Address) ([]pc.ImmatureClaim, error) {
+	claims, err := k.GetClaims(ctx, address)
+	if err != nil {
+		return nil, err
+	}
+	immature := make([]pc.ImmatureClaim, 0)
+	for _, claim := range claims {
+		maturityHeight, err := k.ClaimMaturityHeight(ctx, claim.SessionHeader.SessionBlockHeight)
+		if err != nil {
+			return nil, err
+		}
+		if blocks := maturityHeight - ctx.BlockHeight(); blocks > 0 {
+			immature = append(immature, pc.ImmatureClaim{Claim: claim, BlocksToMaturity: blocks})
+		}
+	}
+	sort.SliceStable(immature, func(i, j int) bool {
+		return immature[i].BlocksToMaturity < immature[j].BlocksToMaturity
+	})
+	return immature, nil
+}
+
+// "GetClaimsInHeightRange" - Returns the claims of the address with a session block height in [startHeight, endHeight]
+// (verified claims are deleted once they are rewarded, so only the pending claims are returned)
+func (k Keeper) GetClaimsInHeightRange(ctx sdk.Ctx, address sdk.Address, startHeight, endHeight int64) (inRange []pc.MsgClaim, err error) {
+	if startHeight > endHeight {
+		return nil, fmt.Errorf("the start height %d is after the end height %d", startHeight, endHeight)
+	}
+	claims, err := k.GetClaims(ctx, address)
+	if err != nil {
+		return nil, err
+	}
+	for _, claim := range claims {
+		if sbh := claim.SessionHeader.SessionBlockHeight; sbh >= startHeight && sbh <= endHeight {
+			inRange = append(inRange, claim)
+		}
+	}
+	return
+}
+
+// "GetServedChains" - Returns the sorted, distinct chains the address holds claims for (an empty slice without claims)
+func (k Keeper) GetServedChains(ctx sdk.Ctx, address sdk.Address) ([]string, error) {
+	claims, err := k.GetClaims(ctx, address)
+	if err != nil {
+		return nil, err
+	}
+	served := make(map[string]struct{})
+	chains := make([]string, 0)
+	for _, claim := range claims {
+		if _, ok := served[claim.SessionHeader.Chain]; !ok {
+			served[claim.SessionHeader.Chain] = struct{}{}
+			chains = append(chains, claim.SessionHeader.Chain)
+		}
+	}
+	sort.Strings(chains)
+	return chains, nil
+}
+
+// "GetClaimsBySession" - Returns the claims held by the address bucketed by their session block height, e.g. to see
+// which sessions produced claims for several chains; an address without claims returns an empty map
+func (k Keeper) GetClaimsBySession(ctx sdk.Ctx, address sdk.Address) (map[int64][]pc.MsgClaim, error) {
+	claims, err := k.GetClaims(ctx, address)
+	if err != nil {
+		return nil, err
+	}
+	bySession := make(map[int64][]pc.MsgClaim)
+	for _, claim := range claims {
+		height := claim.SessionHeader.SessionBlockHeight
+		bySession[height] = append(bySession[height], claim)
+	}
+	return bySession, nil
+}
+
+// "ClaimServicerAddress" - Derives the servicer address of a claim from the public keys signing the proofs of its evidence
+// and returns an error if it is not the claim's FromAddress (a spoofed FromAddress). A claim carries no token data itself,
+// so the evidence is looked up in the stores of the nodes hosted by this process, starting with the FromAddress's own
+func (k Keeper) ClaimServicerAddress(claim pc.MsgClaim) (sdk.Address, error) {
+	nodes := make([]*pc.PocketNode, 0, len(pc.GlobalPocketNodes))
+	if node, err := pc.GetPocketNodeByAddress(&claim.FromAddress); err == nil {
+		nodes = append(nodes, node)
+	}
+	for _, node := range pc.GlobalPocketNodes {
+		nodes = append(nodes, node)
+	}
+	for _, node := range nodes {
+		if node == nil || node.EvidenceStore == nil {
+			continue
+		}
+		evidence, err := pc.GetEvidence(claim.SessionHeader, claim.EvidenceType, sdk.ZeroInt(), node.EvidenceStore)
+		if err != nil || len(evidence.Proofs) == 0 {
+			continue
+		}
+		servicer := evidence.Proofs[0].GetSigner()
+		if servicer == nil {
+			return nil, pc.NewInvalidNodePubKeyError(pc.ModuleName)
+		}
+		if !servicer.Equals(claim.FromAddress) {
+			return servicer, pc.NewClaimServicerMismatchError(pc.ModuleName, claim.FromAddress, servicer)
+		}
+		return servicer, nil
+	}
+	return nil, fmt.Errorf("no evidence found for the claim of %s at session height %d", claim.FromAddress.String(), claim.SessionHeader.SessionBlockHeight)
+}
+
+// "GetClaimTimeline" - Returns whether a claim for the session can be submitted now, the first height it can be proven at
+// and the height it expires at: the stored claim's expiration height, or the projection for a claim submitted at the
+// earliest height it can be (now, or the end of the session if it is ongoing)
+func (k Keeper) GetClaimTimeline(ctx sdk.Ctx, address sdk.Address, header pc.SessionHeader, evidenceType pc.EvidenceType) (pc.ClaimTimeline, error) {
+	blocksPerSession := k.BlocksPerSession(ctx)
+	sessionEnded := ctx.BlockHeight() > header.SessionBlockHeight+blocksPerSession-1
+	provableAtHeight, err := k.ClaimMaturityHeight(ctx, header.SessionBlockHeight)
+	if err != nil {
+		return pc.ClaimTimeline{}, err
+	}
+	timeline := pc.ClaimTimeline{
+		ClaimableNow:     sessionEnded && ctx.BlockHeight() < provableAtHeight,
+		ProvableAtHeight: provableAtHeight,
+	}
+	if claim, found := k.GetClaim(ctx, address, header, evidenceType); found {
+		timeline.ClaimableNow = false
+		timeline.ExpiresAtHeight = claim.ExpirationHeight
+		return timeline, nil
+	}
+	claimHeight := ctx.BlockHeight()
+	if !sessionEnded {
+		claimHeight = header.SessionBlockHeight + blocksPerSession
+	}
+	timeline.ExpiresAtHeight = claimHeight + k.ClaimExpiration(ctx)*blocksPerSession
+	return timeline, nil
+}
+
+// "ClaimExpirationHeight" - Returns the height the claim expires at under the current ClaimExpiration and BlocksPerSession,
+// counted from the height it was submitted at (its stored expiration less the session-time period, see SetClaim). The
+// stored expiration height is not changed by a param change, so comparing the two shows the effect of one
+func (k Keeper) ClaimExpirationHeight(ctx sdk.Ctx, claim pc.MsgClaim) int64 {
+	sessionCtx, err := ctx.PrevCtx(claim.SessionHeader.SessionBlockHeight)
+	if err != nil {
+		return claim.ExpirationHeight
+	}
+	submittedHeight := claim.ExpirationHeight - k.ClaimExpiration(sessionCtx)*k.BlocksPerSession(sessionCtx)
+	return submittedHeight + k.ClaimExpiration(ctx)*k.BlocksPerSession(ctx)
+}
+
+// "ClaimMaturityHeight" - Returns the first height the claims of the session are mature (provable) at
+func (k Keeper) ClaimMaturityHeight(ctx sdk.Ctx, sessionBlockHeight int64) (int64, error) {
+	proofHeight, err := k.proofContextHeight(ctx, sessionBlockHeight)
+	if err != nil {
+		return 0, err
+	}
+	return proofHeight + 1, nil
+}
+
 // "ClaimIsMature" - Returns if the claim is past its security waiting period
 func (k Keeper) ClaimIsMature(ctx sdk.Ctx, sessionBlockHeight int64) bool {
-	waitingPeriodInBlocks := k.ClaimSubmissionWindow(ctx) * k.BlocksPerSession(ctx)
-	return ctx.BlockHeight() > waitingPeriodInBlocks+sessionBlockHeight
+	return k.claimIsMatureAfter(ctx, sessionBlockHeight, 0)
 }
 
-// "DeleteExpiredClaims" - Deletes the expired (claim expiration > # of session passed since claim genesis) claims
+// "ClaimIsProvable" - Returns if this node should prove the claim: it is mature and the node's ProofMaturityBuffer blocks
+// have passed since (the buffer is node local, so it never changes which claims the network accepts)
+func (k Keeper) ClaimIsProvable(ctx sdk.Ctx, sessionBlockHeight int64) bool {
+	return k.claimIsMatureAfter(ctx, sessionBlockHeight, pc.GlobalPocketConfig.ProofMaturityBuffer)
+}
+
+// "proofWindowOpen" - Returns whether the proof of a provable claim may be sent at the current height: within the
+// configured submission window from the height it became provable, or, once the window is missed, in the last session
+// before the claim expires (so its reward is not lost)
+func (k Keeper) proofWindowOpen(ctx sdk.Ctx, claim pc.MsgClaim) bool {
+	window := pc.GlobalPocketConfig.ProofSubmissionWindow
+	if window <= 0 {
+		return true
+	}
+	proofHeight, err := k.proofContextHeight(ctx, claim.SessionHeader.SessionBlockHeight)
+	if err != nil {
+		ctx.Logger().Error(fmt.Sprintf("unable to get the proof window of the claim at session height %d: %s", claim.SessionHeader.SessionBlockHeight, err.Error()))
+		return false
+	}
+	provableHeight := proofHeight + pc.GlobalPocketConfig.ProofMaturityBuffer + 1
+	if ctx.BlockHeight() < provableHeight+window {
+		return true
+	}
+	return ctx.BlockHeight() >= claim.ExpirationHeight-k.BlocksPerSession(ctx)
+}
+
+// "claimIsMatureAfter" - Returns if the claim is past its security waiting period plus a number of buffer blocks
+// (a claim whose session block is unavailable is not mature)
+func (k Keeper) claimIsMatureAfter(ctx sdk.Ctx, sessionBlockHeight, bufferBlocks int64) bool {
+	proofHeight, err := k.proofContextHeight(ctx, sessionBlockHeight)
+	if err != nil {
+		ctx.Logger().Error(fmt.Sprintf("unable to get the maturity of the claim at session height %d: %s", sessionBlockHeight, err.Error()))
+		return false
+	}
+	return ctx.BlockHeight() > proofHeight+bufferBlocks
+}
+
+// "proofContextHeight" - Returns the height whose block hash selects the proof index of the session: the end of its
+// security waiting period, with the params read from ctx, or after SPCTX at the session height (so a param change
+// after the session began moves neither). A claim is mature (provable) from the next height on, so the maturity check,
+// the maturity index and the proof index must use this same height
+func (k Keeper) proofContextHeight(ctx sdk.Ctx, sessionBlockHeight int64) (int64, error) {
+	if k.Cdc.IsAfterNamedFeatureActivationHeight(ctx.BlockHeight(), codec.SessionProofContextKey) {
+		sessionCtx, err := ctx.PrevCtx(sessionBlockHeight)
+		if err != nil {
+			return 0, err
+		}
+		ctx = sessionCtx
+	}
+	return sessionBlockHeight + k.ClaimSubmissionWindow(ctx)*k.BlocksPerSession(ctx), nil
+}
+
+// "ClaimWindowClosing" - Returns whether the claim submission window of the session is in its last session
+// (the last session a claim for it can be sent in before it would be mature)
+func (k Keeper) ClaimWindowClosing(ctx sdk.Ctx, sessionBlockHeight int64) bool {
+	blocksPerSession := k.BlocksPerSession(ctx)
+	return ctx.BlockHeight() > (k.ClaimSubmissionWindow(ctx)-1)*blocksPerSession+sessionBlockHeight
+}
+
+// "DeleteExpiredClaims" - Deletes the expired (claim expiration > # of session passed since claim genesis) claims.
+// Only the expiration height stored with the claim (see SetClaim) is compared to the current height; the current params are not read
 func (k Keeper) DeleteExpiredClaims(ctx sdk.Ctx) {
-	var msg = pc.MsgClaim{}
+	k.deleteExpiredClaims(ctx, pc.GlobalPocketConfig.ClaimSweepConcurrency)
+}
+
+// "deleteExpiredClaims" - Deletes the expired claims, decoding them with the given number of workers (see sweepClaimsSharded)
+func (k Keeper) deleteExpiredClaims(ctx sdk.Ctx, concurrency int) {
 	store := ctx.KVStore(k.storeKey)
-	iterator, _ := sdk.KVStorePrefixIterator(store, pc.ClaimKey)
-	defer iterator.Close()
-	for ; iterator.Valid(); iterator.Next() {
-		err := k.Cdc.UnmarshalBinaryBare(iterator.Value(), &msg, ctx.BlockHeight())
-		if err != nil {
-			panic(err)
+	maxDeleted := k.MaxClaimsDeletedPerBlock(ctx)
+	// if the sweep is capped, resume where the previous block left off
+	start := pc.ClaimKey
+	if maxDeleted > 0 {
+		if cursor, _ := store.Get(pc.ExpiredClaimsCursorKey); cursor != nil {
+			start = cursor
 		}
+	}
+	var deleted int64
+	var next []byte
+	// the mature claims whose proof index is to be persisted, written once the sweep is done (not under its iterator)
+	var toPersist [][]byte
+	var toPersistClaims []pc.MsgClaim
+	// sweep the claim stored at the key, in key order; returns false once the cap is reached
+	sweep := func(key []byte, msg pc.MsgClaim) bool {
 		// if more sessions has passed than the expiration of the claim's genesis, delete it from the set
 		if msg.ExpirationHeight <= ctx.BlockHeight() {
-			_ = store.Delete(iterator.Key())
+			// if the cap is reached, the next block resumes at this claim
+			if maxDeleted > 0 && deleted >= maxDeleted {
+				next = key
+				return false
+			}
+			_ = store.Delete(key)
+			_ = store.Delete(pc.KeyForProofIndex(key))
+			if k.Cdc.IsAfterNamedFeatureActivationHeight(ctx.BlockHeight(), codec.ClaimMaturityIndexKey) {
+				if indexKey, err := pc.KeyForClaimMaturityIndex(ctx, msg.FromAddress, msg.SessionHeader, msg.EvidenceType); err == nil {
+					_ = store.Delete(indexKey)
+				}
+			}
+			deleted++
+			return true
+		}
+		// a claim's proof index is persisted the first block the sweep visits it mature: the block it matures, unless
+		// MaxClaimsDeletedPerBlock caps the sweep, in which case it may be some blocks later (until then, and for a
+		// claim that expires first, the proof index is computed afresh, from the same proof context block hash)
+		if k.Cdc.IsAfterNamedFeatureActivationHeight(ctx.BlockHeight(), codec.PersistedProofIndexKey) && k.ClaimIsMature(ctx, msg.SessionHeader.SessionBlockHeight) {
+			if found, _ := store.Has(pc.KeyForProofIndex(key)); !found {
+				toPersist = append(toPersist, append([]byte{}, key...))
+				toPersistClaims = append(toPersistClaims, msg)
+			}
+		}
+		return true
+	}
+	if concurrency > 1 {
+		k.sweepClaimsSharded(ctx, store, start, concurrency, concurrency*claimSweepBatchPerWorker, sweep)
+	} else {
+		iterator, _ := store.Iterator(start, sdk.PrefixEndBytes(pc.ClaimKey))
+		for ; iterator.Valid(); iterator.Next() {
+			// a fresh claim each time, as the swept claim may be kept until the sweep is done
+			var msg = pc.MsgClaim{}
+			err := k.Cdc.UnmarshalBinaryBare(iterator.Value(), &msg, ctx.BlockHeight())
+			if err != nil {
+				panic(err)
+			}
+			if !sweep(iterator.Key(), msg) {
+				break
+			}
+		}
+		iterator.Close()
+	}
+	for i, key := range toPersist {
+		k.persistProofIndex(ctx, store, key, toPersistClaims[i])
+	}
+	if maxDeleted <= 0 {
+		return
+	}
+	// if the sweep reached the end of the claims, the next block starts from the beginning
+	if next == nil {
+		_ = store.Delete(pc.ExpiredClaimsCursorKey)
+		return
+	}
+	_ = store.Set(pc.ExpiredClaimsCursorKey, next)
+}
+
+// "claimSweepBatchPerWorker" - The claims each worker of the sharded sweep decodes per batch
+const claimSweepBatchPerWorker = 64
+
+// "sweepClaimsSharded" - Reads the claims from start to the end of the claims in batches of batchSize, decodes each batch
+// with a bounded pool of workers, each over a contiguous range of the keys (so a range of addresses), and hands them to
+// sweep in key order, stopping (without reading further) once sweep returns false. The store is not safe for concurrent
+// use, so it is only iterated and written from this goroutine, and never while a batch is being read: the claims are
+// swept one by one exactly as the serial sweep does, and a claim that fails to decode panics once it is reached
+func (k Keeper) sweepClaimsSharded(ctx sdk.Ctx, store sdk.KVStore, start []byte, concurrency, batchSize int, sweep func(key []byte, msg pc.MsgClaim) bool) {
+	height := ctx.BlockHeight()
+	end := sdk.PrefixEndBytes(pc.ClaimKey)
+	keys, values := make([][]byte, 0, batchSize), make([][]byte, 0, batchSize)
+	claims, errs := make([]pc.MsgClaim, batchSize), make([]error, batchSize)
+	for {
+		keys, values = keys[:0], values[:0]
+		iterator, _ := store.Iterator(start, end)
+		for ; iterator.Valid() && len(keys) < batchSize; iterator.Next() {
+			keys = append(keys, append([]byte{}, iterator.Key()...))
+			values = append(values, append([]byte{}, iterator.Value()...))
+		}
+		iterator.Close()
+		if len(keys) == 0 {
+			return
+		}
+		shardSize := (len(keys) + concurrency - 1) / concurrency
+		var wg sync.WaitGroup
+		for lo := 0; lo < len(keys); lo += shardSize {
+			hi := lo + shardSize
+			if hi > len(keys) {
+				hi = len(keys)
+			}
+			wg.Add(1)
+			go func(lo, hi int) {
+				defer wg.Done()
+				for i := lo; i < hi; i++ {
+					claims[i] = pc.MsgClaim{}
+					errs[i] = k.Cdc.UnmarshalBinaryBare(values[i], &claims[i], height)
+				}
+			}(lo, hi)
+		}
+		wg.Wait()
+		for i := range keys {
+			if errs[i] != nil {
+				panic(errs[i])
+			}
+			if !sweep(keys[i], claims[i]) {
+				return
+			}
+		}
+		if len(keys) < batchSize {
+			return
 		}
+		// the next batch starts right after the last key of this one
+		start = append(append([]byte{}, keys[len(keys)-1]...), 0x00)
 	}
 }
@@ -1,12 +1,23 @@
 package keeper
 
 import (
+	"bytes"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"math"
+	"strings"
 	"testing"
 
+	"github.com/pokt-network/pocket-core/codec"
 	"github.com/pokt-network/pocket-core/crypto"
+	storeTypes "github.com/pokt-network/pocket-core/store/types"
 	sdk "github.com/pokt-network/pocket-core/types"
+	"github.com/pokt-network/pocket-core/x/auth"
+	"github.com/pokt-network/pocket-core/x/auth/util"
 	"github.com/pokt-network/pocket-core/x/pocketcore/types"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
 )
 
 func TestKeeper_GetSetClaim(t *testing.T) {
@@ -26,7 +37,7 @@ func TestKeeper_GetSetClaim(t *testing.T) {
 	mockCtx.On("BlockHeight").Return(int64(1))
 	mockCtx.On("PrevCtx", header.SessionBlockHeight).Return(ctx, nil)
 	mockCtx.On("BlockHash", header.SessionBlockHeight).Return(types.Hash([]byte("fake")), nil)
-	err = keeper.SetClaim(mockCtx, claim)
+	_, err = keeper.SetClaim(mockCtx, claim)
 	assert.Nil(t, err)
 	c, found := keeper.GetClaim(mockCtx, sdk.Address(npk.Address()), header, types.RelayEvidence)
 	assert.True(t, found)
@@ -112,6 +123,231 @@ func TestKeeper_GetMatureClaims(t *testing.T) {
 	assert.Nil(t, c2)
 }
 
+func TestKeeper_SetClaimHeaderCollision(t *testing.T) {
+	ctx, _, _, _, keeper, _, _ := createTestInput(t, false)
+	claim := createTestClaim(getRandomValidatorAddress(), "0001", 1, 10)
+	// simulate a claim for another session stored under this claim's key
+	other := claim
+	other.SessionHeader.Chain = "0002"
+	key, err := types.KeyForClaim(ctx, claim.FromAddress, claim.SessionHeader, claim.EvidenceType)
+	assert.Nil(t, err)
+	bz, err := keeper.Cdc.MarshalBinaryBare(&other, ctx.BlockHeight())
+	assert.Nil(t, err)
+	_ = ctx.KVStore(keeper.storeKey).Set(key, bz)
+	// the write is rejected and the stored claim is untouched
+	_, err = keeper.SetClaim(ctx, claim)
+	assert.NotNil(t, err)
+	assert.Equal(t, sdk.CodeType(types.CodeClaimHeaderCollisionError), err.(sdk.Error).Code())
+	stored, found := keeper.GetClaim(ctx, claim.FromAddress, claim.SessionHeader, claim.EvidenceType)
+	assert.True(t, found)
+	assert.Equal(t, other, stored)
+	// overwriting a claim for the same session is still allowed
+	_, err = keeper.SetClaim(ctx, other)
+	assert.Nil(t, err)
+	other.TotalProofs = 20
+	_, err = keeper.SetClaim(ctx, other)
+	assert.Nil(t, err)
+	stored, _ = keeper.GetClaim(ctx, other.FromAddress, other.SessionHeader, other.EvidenceType)
+	assert.Equal(t, int64(20), stored.TotalProofs)
+}
+
+func TestKeeper_SetClaimMixedCaseHeader(t *testing.T) {
+	codec.UpgradeFeatureMap[codec.CanonicalHeaderKey] = 1
+	t.Cleanup(func() { delete(codec.UpgradeFeatureMap, codec.CanonicalHeaderKey) })
+	ctx, _, _, _, keeper, _, _ := createTestInput(t, false)
+	claim := createTestClaim(getRandomValidatorAddress(), "0001", 1, 10)
+	upper := claim
+	upper.SessionHeader.ApplicationPubKey = strings.ToUpper(claim.SessionHeader.ApplicationPubKey)
+	_, err := keeper.SetClaim(ctx, upper)
+	assert.Nil(t, err)
+	// the same session in another case is not a collision, and overwrites the claim
+	claim.TotalProofs = 20
+	_, err = keeper.SetClaim(ctx, claim)
+	assert.Nil(t, err)
+	for _, header := range []types.SessionHeader{claim.SessionHeader, upper.SessionHeader} {
+		stored, found := keeper.GetClaim(ctx, claim.FromAddress, header, claim.EvidenceType)
+		assert.True(t, found)
+		assert.Equal(t, int64(20), stored.TotalProofs)
+	}
+}
+
+func TestKeeper_SetClaimIsNew(t *testing.T) {
+	ctx, _, _, _, keeper, _, _ := createTestInput(t, false)
+	claim := createTestClaim(getRandomValidatorAddress(), "0001", 1, 10)
+	isNew, err := keeper.SetClaim(ctx, claim)
+	assert.Nil(t, err)
+	assert.True(t, isNew)
+	// the same claim set again (e.g. a duplicate processing) reports an overwrite
+	isNew, err = keeper.SetClaim(ctx, claim)
+	assert.Nil(t, err)
+	assert.False(t, isNew)
+	// another evidence type of the session is a new claim
+	challenge := claim
+	challenge.EvidenceType = types.ChallengeEvidence
+	isNew, err = keeper.SetClaim(ctx, challenge)
+	assert.Nil(t, err)
+	assert.True(t, isNew)
+	// once deleted, the claim is new again
+	assert.Nil(t, keeper.DeleteClaim(ctx, claim.FromAddress, claim.SessionHeader, claim.EvidenceType))
+	isNew, err = keeper.SetClaim(ctx, claim)
+	assert.Nil(t, err)
+	assert.True(t, isNew)
+}
+
+func TestKeeper_GetClaimBytes(t *testing.T) {
+	ctx, _, _, _, keeper, _, _ := createTestInput(t, false)
+	claim := createTestClaim(getRandomValidatorAddress(), "0001", 1, 10)
+	_, err := keeper.SetClaim(ctx, claim)
+	assert.Nil(t, err)
+	bz, found := keeper.GetClaimBytes(ctx, claim.FromAddress, claim.SessionHeader, claim.EvidenceType)
+	assert.True(t, found)
+	// the bytes round trip to the same claim
+	var decoded types.MsgClaim
+	assert.Nil(t, keeper.Cdc.UnmarshalBinaryBare(bz, &decoded, ctx.BlockHeight()))
+	assert.Equal(t, claim, decoded)
+	c, _ := keeper.GetClaim(ctx, claim.FromAddress, claim.SessionHeader, claim.EvidenceType)
+	assert.Equal(t, c, decoded)
+	// not found
+	bz, found = keeper.GetClaimBytes(ctx, claim.FromAddress, claim.SessionHeader, types.ChallengeEvidence)
+	assert.False(t, found)
+	assert.Nil(t, bz)
+}
+
+func TestKeeper_GetMatureClaimsOrder(t *testing.T) {
+	ctx, _, _, _, keeper, _, _ := createTestInput(t, false)
+	addr := getRandomValidatorAddress()
+	var claims []types.MsgClaim
+	for _, sessionBlockHeight := range []int64{9, 1, 5, 1, 9, 5} {
+		claims = append(claims, createTestClaim(addr, "0001", sessionBlockHeight, 10))
+	}
+	keeper.SetClaims(ctx, claims)
+	mature, err := keeper.GetMatureClaims(ctx, addr)
+	assert.Nil(t, err)
+	assert.Len(t, mature, len(claims))
+	// oldest session first, then by claim key within the same session height
+	for i := 1; i < len(mature); i++ {
+		prev, cur := mature[i-1], mature[i]
+		assert.True(t, prev.SessionHeader.SessionBlockHeight <= cur.SessionHeader.SessionBlockHeight)
+		if prev.SessionHeader.SessionBlockHeight == cur.SessionHeader.SessionBlockHeight {
+			prevKey, _ := types.KeyForClaim(ctx, addr, prev.SessionHeader, prev.EvidenceType)
+			curKey, _ := types.KeyForClaim(ctx, addr, cur.SessionHeader, cur.EvidenceType)
+			assert.Equal(t, -1, bytes.Compare(prevKey, curKey))
+		}
+	}
+	// the same on every call
+	again, err := keeper.GetMatureClaims(ctx, addr)
+	assert.Nil(t, err)
+	assert.Equal(t, mature, again)
+}
+
+func TestKeeper_ClaimMaturityIndex(t *testing.T) {
+	ctx, _, _, _, keeper, _, _ := createTestInput(t, false)
+	codec.UpgradeFeatureMap[codec.ClaimMaturityIndexKey] = 1
+	t.Cleanup(func() { delete(codec.UpgradeFeatureMap, codec.ClaimMaturityIndexKey) })
+	addr := getRandomValidatorAddress()
+	// the claims of another address are never returned
+	_, err := keeper.SetClaim(ctx, createTestClaim(getRandomValidatorAddress(), "0001", 1, 10))
+	assert.Nil(t, err)
+	var claims []types.MsgClaim
+	for _, sessionBlockHeight := range []int64{9, 1, 5, 1, 9, 5} {
+		claim := createTestClaim(addr, "0001", sessionBlockHeight, 10)
+		_, err := keeper.SetClaim(ctx, claim)
+		assert.Nil(t, err)
+		claims = append(claims, claim)
+	}
+	waitingPeriod := keeper.ClaimSubmissionWindow(ctx) * keeper.BlocksPerSession(ctx)
+	assertMatureAt := func(height int64, sessionBlockHeights ...int64) {
+		mature, err := keeper.GetMatureClaims(ctx.WithBlockHeight(height), addr)
+		assert.Nil(t, err)
+		var got []int64
+		for _, claim := range mature {
+			got = append(got, claim.SessionHeader.SessionBlockHeight)
+		}
+		assert.Equal(t, sessionBlockHeights, got, "height %d", height)
+	}
+	// each claim is returned from the first height past its waiting period, oldest session first
+	assertMatureAt(waitingPeriod + 1)
+	assertMatureAt(waitingPeriod+2, 1, 1)
+	assertMatureAt(waitingPeriod+6, 1, 1, 5, 5)
+	assertMatureAt(waitingPeriod+10, 1, 1, 5, 5, 9, 9)
+	// the same claims as the scan of all of the address's claims
+	mature, err := keeper.GetMatureClaims(ctx, addr)
+	assert.Nil(t, err)
+	codec.UpgradeFeatureMap[codec.ClaimMaturityIndexKey] = 0
+	scanned, err := keeper.GetMatureClaims(ctx, addr)
+	assert.Nil(t, err)
+	assert.Equal(t, scanned, mature)
+	codec.UpgradeFeatureMap[codec.ClaimMaturityIndexKey] = 1
+	// deleted claims are removed from the index
+	assert.Nil(t, keeper.DeleteClaim(ctx, addr, claims[1].SessionHeader, claims[1].EvidenceType))
+	assertMatureAt(waitingPeriod+10, 1, 5, 5, 9, 9)
+	// so are expired claims
+	keeper.DeleteExpiredClaims(ctx.WithBlockHeight(claims[0].ExpirationHeight))
+	assertMatureAt(waitingPeriod + 10)
+	store := ctx.KVStore(keeper.storeKey)
+	iter, _ := sdk.KVStorePrefixIterator(store, types.ClaimMaturityIndexKey)
+	defer iter.Close()
+	assert.False(t, iter.Valid())
+}
+
+func TestKeeper_SetClaimMaturityIndex(t *testing.T) {
+	ctx, _, _, _, keeper, _, _ := createTestInput(t, false)
+	addr := getRandomValidatorAddress()
+	// claims set before the index is activated are not indexed
+	for _, sessionBlockHeight := range []int64{5, 1} {
+		_, err := keeper.SetClaim(ctx, createTestClaim(addr, "0001", sessionBlockHeight, 10))
+		assert.Nil(t, err)
+	}
+	codec.UpgradeFeatureMap[codec.ClaimMaturityIndexKey] = 1
+	t.Cleanup(func() { delete(codec.UpgradeFeatureMap, codec.ClaimMaturityIndexKey) })
+	mature, err := keeper.GetMatureClaims(ctx, addr)
+	assert.Nil(t, err)
+	assert.Empty(t, mature)
+	// until the activation height indexes them
+	keeper.SetClaimMaturityIndex(ctx)
+	mature, err = keeper.GetMatureClaims(ctx, addr)
+	assert.Nil(t, err)
+	assert.Len(t, mature, 2)
+	assert.Equal(t, int64(1), mature[0].SessionHeader.SessionBlockHeight)
+	assert.Equal(t, int64(5), mature[1].SessionHeader.SessionBlockHeight)
+}
+
+func TestKeeper_SetClaimsMatchesSetClaim(t *testing.T) {
+	codec.UpgradeFeatureMap[codec.ClaimMaturityIndexKey] = 1
+	t.Cleanup(func() { delete(codec.UpgradeFeatureMap, codec.ClaimMaturityIndexKey) })
+	var claims []types.MsgClaim
+	for i := 0; i < 50; i++ {
+		addr := getRandomValidatorAddress()
+		claims = append(claims, createTestClaim(addr, "0001", int64(i%5*4+1), int64(i+1)))
+		// without an expiration height, so it is derived from the session context
+		claim := createTestClaim(addr, "0002", 976, int64(i+1))
+		claim.ExpirationHeight = 0
+		claims = append(claims, claim)
+	}
+	// the module state of the claims (claims and their maturity index)
+	claimState := func(ctx sdk.Ctx, k Keeper) map[string][]byte {
+		state := make(map[string][]byte)
+		for _, prefix := range [][]byte{types.ClaimKey, types.ClaimMaturityIndexKey} {
+			iterator, _ := sdk.KVStorePrefixIterator(ctx.KVStore(k.storeKey), prefix)
+			for ; iterator.Valid(); iterator.Next() {
+				state[hex.EncodeToString(iterator.Key())] = iterator.Value()
+			}
+			iterator.Close()
+		}
+		return state
+	}
+	ctx, _, _, _, keeper, _, _ := createTestInput(t, false)
+	for _, claim := range claims {
+		_, err := keeper.SetClaim(ctx, claim)
+		assert.Nil(t, err)
+	}
+	batchCtx, _, _, _, batchKeeper, _, _ := createTestInput(t, false)
+	batchKeeper.SetClaims(batchCtx, claims)
+	expected := claimState(ctx, keeper)
+	assert.Len(t, expected, 2*len(claims))
+	assert.Equal(t, expected, claimState(batchCtx, batchKeeper))
+}
+
 func TestKeeper_DeleteExpiredClaims(t *testing.T) {
 	ctx, _, _, _, keeper, keys, _ := createTestInput(t, false)
 	npk, header, _ := simulateRelays(t, keeper, &ctx, 5)
@@ -152,3 +388,912 @@ func TestKeeper_DeleteExpiredClaims(t *testing.T) {
 	assert.Contains(t, c1, notExpired, "does not contain notExpired claim")
 	assert.NotContains(t, c1, expiredClaim, "contains expired claim")
 }
+
+func TestKeeper_ClaimExpirationUsesSessionParams(t *testing.T) {
+	ctx, _, _, _, keeper, _, _ := createTestInput(t, false)
+	claim := createTestClaim(getRandomValidatorAddress(), "0001", 1, 10)
+	claim.ExpirationHeight = 0
+	// ClaimExpiration is changed between the session and the submission height
+	sessionCtx, _ := ctx.CacheContext()
+	p := keeper.GetParams(ctx)
+	p.ClaimExpiration = 10
+	keeper.SetParams(sessionCtx, p)
+	p.ClaimExpiration = 100
+	keeper.SetParams(ctx, p)
+	assert.Equal(t, int64(10), keeper.ClaimExpiration(sessionCtx))
+	assert.Equal(t, int64(100), keeper.ClaimExpiration(ctx))
+	mockCtx := &Ctx{}
+	mockCtx.On("KVStore", mock.Anything).Return(func(key storeTypes.StoreKey) storeTypes.KVStore { return ctx.KVStore(key) })
+	mockCtx.On("BlockHeight").Return(ctx.BlockHeight())
+	mockCtx.On("PrevCtx", claim.SessionHeader.SessionBlockHeight).Return(sessionCtx, nil)
+	_, err := keeper.SetClaim(mockCtx, claim)
+	assert.Nil(t, err)
+	// the expiration is counted from the submission height with the session-time params
+	expirationHeight := ctx.BlockHeight() + 10*keeper.BlocksPerSession(sessionCtx)
+	stored, found := keeper.GetClaim(ctx, claim.FromAddress, claim.SessionHeader, claim.EvidenceType)
+	assert.True(t, found)
+	assert.Equal(t, expirationHeight, stored.ExpirationHeight)
+	// and stays fixed: the current params are not consulted when expiring
+	keeper.DeleteExpiredClaims(ctx.WithBlockHeight(expirationHeight - 1))
+	assert.Len(t, keeper.GetAllClaims(ctx), 1)
+	keeper.DeleteExpiredClaims(ctx.WithBlockHeight(expirationHeight))
+	assert.Len(t, keeper.GetAllClaims(ctx), 0)
+}
+
+func TestKeeper_DeleteExpiredClaimsCapped(t *testing.T) {
+	ctx, _, _, _, keeper, _, _ := createTestInput(t, false)
+	p := keeper.GetParams(ctx)
+	p.MaxClaimsDeletedPerBlock = 2
+	keeper.SetParams(ctx, p)
+	var claims []types.MsgClaim
+	for i := 0; i < 5; i++ {
+		expired := createTestClaim(getRandomValidatorAddress(), "0001", 1, 10)
+		expired.ExpirationHeight = ctx.BlockHeight()
+		claims = append(claims, expired)
+	}
+	notExpired := createTestClaim(getRandomValidatorAddress(), "0001", 1, 10)
+	keeper.SetClaims(ctx, append(claims, notExpired))
+	// each block deletes at most 2 expired claims, resuming where the last block stopped
+	for _, remaining := range []int{4, 2, 1} {
+		keeper.DeleteExpiredClaims(ctx)
+		assert.Len(t, keeper.GetAllClaims(ctx), remaining)
+	}
+	assert.Equal(t, []types.MsgClaim{notExpired}, keeper.GetAllClaims(ctx))
+	// the sweep reached the end, so the cursor is cleared
+	cursor, _ := ctx.KVStore(keeper.storeKey).Get(types.ExpiredClaimsCursorKey)
+	assert.Nil(t, cursor)
+}
+
+func TestKeeper_DeleteExpiredClaimsSharded(t *testing.T) {
+	ctx, _, _, _, keeper, _, _ := createTestInput(t, false)
+	var claims []types.MsgClaim
+	for i := 0; i < 40; i++ {
+		claim := createTestClaim(getRandomValidatorAddress(), "0001", 1, 10)
+		// every third claim is live
+		if i%3 != 0 {
+			claim.ExpirationHeight = ctx.BlockHeight()
+		}
+		claims = append(claims, claim)
+	}
+	keeper.SetClaims(ctx, claims)
+	for _, maxDeleted := range []int64{0, 7} {
+		p := keeper.GetParams(ctx)
+		p.MaxClaimsDeletedPerBlock = maxDeleted
+		keeper.SetParams(ctx, p)
+		serialCtx, _ := ctx.CacheContext()
+		for _, concurrency := range []int{2, 3, 16, 64} {
+			shardedCtx, _ := ctx.CacheContext()
+			// each block the sharded sweep leaves exactly the claims and cursor the serial one does
+			for block := 0; block < 5; block++ {
+				keeper.deleteExpiredClaims(serialCtx, 1)
+				keeper.deleteExpiredClaims(shardedCtx, concurrency)
+				assert.Equal(t, keeper.GetAllClaims(serialCtx), keeper.GetAllClaims(shardedCtx))
+				serialCursor, _ := serialCtx.KVStore(keeper.storeKey).Get(types.ExpiredClaimsCursorKey)
+				shardedCursor, _ := shardedCtx.KVStore(keeper.storeKey).Get(types.ExpiredClaimsCursorKey)
+				assert.Equal(t, serialCursor, shardedCursor)
+			}
+			assert.Len(t, keeper.GetAllClaims(shardedCtx), 14)
+			serialCtx, _ = ctx.CacheContext()
+		}
+	}
+}
+
+func TestKeeper_SweepClaimsShardedBatches(t *testing.T) {
+	ctx, _, _, _, keeper, _, _ := createTestInput(t, false)
+	var claims []types.MsgClaim
+	for i := 0; i < 23; i++ {
+		claims = append(claims, createTestClaim(getRandomValidatorAddress(), "0001", 1, int64(i+1)))
+	}
+	keeper.SetClaims(ctx, claims)
+	store := ctx.KVStore(keeper.storeKey)
+	var serial [][]byte
+	iterator, _ := store.Iterator(types.ClaimKey, sdk.PrefixEndBytes(types.ClaimKey))
+	for ; iterator.Valid(); iterator.Next() {
+		serial = append(serial, iterator.Key())
+	}
+	iterator.Close()
+	for _, batchSize := range []int{1, 4, 23, 100} {
+		// every claim is swept once, in key order, across the batches
+		var swept [][]byte
+		keeper.sweepClaimsSharded(ctx, store, types.ClaimKey, 3, batchSize, func(key []byte, msg types.MsgClaim) bool {
+			swept = append(swept, key)
+			// decoded into a fresh claim, whatever the batch
+			stored, found := keeper.GetClaim(ctx, msg.FromAddress, msg.SessionHeader, msg.EvidenceType)
+			assert.True(t, found)
+			assert.Equal(t, stored, msg)
+			return true
+		})
+		assert.Equal(t, serial, swept)
+		// and the sweep stops once told to
+		swept = nil
+		keeper.sweepClaimsSharded(ctx, store, types.ClaimKey, 3, batchSize, func(key []byte, msg types.MsgClaim) bool {
+			swept = append(swept, key)
+			return len(swept) < 5
+		})
+		assert.Equal(t, serial[:5], swept)
+	}
+}
+
+func TestKeeper_IterateAndExecuteOverClaims(t *testing.T) {
+	ctx, _, _, _, keeper, _, _ := createTestInput(t, false)
+	var claims []types.MsgClaim
+	for i := 0; i < 5; i++ {
+		claims = append(claims, createTestClaim(getRandomValidatorAddress(), "0001", 1, int64(i+1)))
+	}
+	keeper.SetClaims(ctx, claims)
+	all := keeper.GetAllClaims(ctx)
+	assert.Len(t, all, 5)
+	// the handler stops the iteration
+	var visited []types.MsgClaim
+	keeper.iterateAndExecuteOverClaims(ctx, func(claim types.MsgClaim) (stop bool) {
+		visited = append(visited, claim)
+		return len(visited) == 2
+	})
+	assert.Equal(t, all[:2], visited)
+}
+
+func TestKeeper_GetRelaysByChainAll(t *testing.T) {
+	ctx, _, _, _, keeper, _, _ := createTestInput(t, false)
+	addr, addr2 := getRandomValidatorAddress(), getRandomValidatorAddress()
+	claims := []types.MsgClaim{
+		createTestClaim(addr, "0001", 1, 10),
+		createTestClaim(addr2, "0001", 1, 15),
+		createTestClaim(addr, "0002", 1, 7),
+		createTestClaim(addr2, "0003", 5, 20),
+	}
+	challenge := createTestClaim(addr, "0003", 5, 100)
+	challenge.EvidenceType = types.ChallengeEvidence
+	keeper.SetClaims(ctx, append(claims, challenge))
+	relays := keeper.GetRelaysByChainAll(ctx)
+	assert.Equal(t, map[string]int64{"0001": 25, "0002": 7, "0003": 20}, relays)
+	// saturates instead of overflowing
+	_, err := keeper.SetClaim(ctx, createTestClaim(addr, "0002", 1, math.MaxInt64))
+	assert.Nil(t, err)
+	assert.Equal(t, int64(math.MaxInt64), keeper.GetRelaysByChainAll(ctx)["0002"])
+}
+
+func TestKeeper_ValidateClaims(t *testing.T) {
+	ctx, _, _, _, keeper, _, _ := createTestInput(t, false)
+	params := keeper.GetParams(ctx)
+	params.MinimumNumberOfProofs = 10
+	keeper.SetParams(ctx, params)
+	addr := getRandomValidatorAddress()
+	chain := getTestSupportedBlockchain()
+	valid := createTestClaim(addr, chain, 1, 10)
+	noRelays := createTestClaim(addr, chain, 1, 0)
+	belowMinimum := createTestClaim(addr, chain, 1, 9)
+	unsupported := createTestClaim(addr, "9999", 1, 10)
+	emptyChain := createTestClaim(addr, "", 1, 10)
+	noHeight := createTestClaim(addr, chain, 0, 10)
+	badAppKey := createTestClaim(addr, chain, 1, 10)
+	badAppKey.SessionHeader.ApplicationPubKey = "bad"
+	noEvidenceType := createTestClaim(addr, chain, 1, 10)
+	noEvidenceType.EvidenceType = 0
+	claims := []types.MsgClaim{valid, noRelays, belowMinimum, unsupported, emptyChain, noHeight, badAppKey, noEvidenceType, valid}
+	errs := keeper.ValidateClaims(ctx, claims)
+	assert.Len(t, errs, len(claims))
+	// the stored expiration height of a valid claim is not an error
+	assert.Nil(t, errs[0])
+	assert.Nil(t, errs[8])
+	for i, code := range []sdk.CodeType{
+		types.CodeEmptyProofsError,
+		types.CodeInvalidProofsError,
+		types.CodeChainNotSupportedErr,
+		types.CodeEmptyChainError,
+		types.CodeEmptyBlockIDError,
+		types.CodePubKeyError,
+		types.CodeNoEvidenceTypeErr,
+	} {
+		err, ok := errs[i+1].(sdk.Error)
+		if assert.True(t, ok, "claim %d should fail", i+1) {
+			assert.Equal(t, code, err.Code(), "claim %d", i+1)
+		}
+	}
+	assert.Empty(t, keeper.ValidateClaims(ctx, nil))
+}
+
+func TestKeeper_ValidateClaimFutureSession(t *testing.T) {
+	ctx, _, _, _, keeper, _, _ := createTestInput(t, false)
+	claim := createTestClaim(getRandomValidatorAddress(), getTestSupportedBlockchain(), ctx.BlockHeight()+keeper.BlocksPerSession(ctx), 10)
+	claim.ExpirationHeight = 0
+	// rejected before FSESS too, on the session context
+	err := keeper.ValidateClaim(ctx, claim)
+	assert.NotNil(t, err)
+	assert.NotEqual(t, sdk.CodeType(types.CodeFutureSessionHeightError), err.Code())
+	codec.UpgradeFeatureMap[codec.FutureSessionHeightKey] = 1
+	t.Cleanup(func() { delete(codec.UpgradeFeatureMap, codec.FutureSessionHeightKey) })
+	err = keeper.ValidateClaim(ctx, claim)
+	assert.NotNil(t, err)
+	assert.Equal(t, sdk.CodeType(types.CodeFutureSessionHeightError), err.Code())
+}
+
+func TestKeeper_ValidateClaimZeroRoot(t *testing.T) {
+	ctx, _, _, _, keeper, _, _ := createTestInput(t, false)
+	claim := createTestClaim(getRandomValidatorAddress(), getTestSupportedBlockchain(), 1, 10)
+	claim.ExpirationHeight = 0
+	// not rejected as empty before EMROOT
+	claim.MerkleRoot.Hash = make([]byte, types.MerkleHashLength)
+	if err := keeper.ValidateClaim(ctx, claim); err != nil {
+		assert.NotEqual(t, sdk.CodeType(types.CodeEmptyMerkleRootError), err.Code())
+	}
+	codec.UpgradeFeatureMap[codec.EmptyMerkleRootKey] = 1
+	t.Cleanup(func() { delete(codec.UpgradeFeatureMap, codec.EmptyMerkleRootKey) })
+	for _, hash := range [][]byte{make([]byte, types.MerkleHashLength), nil} {
+		claim.MerkleRoot.Hash = hash
+		err := keeper.ValidateClaim(ctx, claim)
+		assert.NotNil(t, err)
+		assert.Equal(t, sdk.CodeType(types.CodeEmptyMerkleRootError), err.Code())
+	}
+	// a root with any non zero byte is not rejected as empty
+	claim.MerkleRoot.Hash = make([]byte, types.MerkleHashLength)
+	claim.MerkleRoot.Hash[types.MerkleHashLength-1] = 1
+	if err := keeper.ValidateClaim(ctx, claim); err != nil {
+		assert.NotEqual(t, sdk.CodeType(types.CodeEmptyMerkleRootError), err.Code())
+	}
+}
+
+func TestKeeper_GetSessionForClaim(t *testing.T) {
+	ctx, _, _, _, keeper, _, _ := createTestInput(t, false)
+	claim := createTestClaim(getRandomValidatorAddress(), "0001", 5, 10)
+	mockCtx := &Ctx{}
+	mockCtx.On("PrevCtx", claim.SessionHeader.SessionBlockHeight).Return(ctx.WithBlockHeight(claim.SessionHeader.SessionBlockHeight), nil)
+	header, sessionCtx, err := keeper.GetSessionForClaim(mockCtx, claim)
+	assert.Nil(t, err)
+	assert.Equal(t, claim.SessionHeader, header)
+	assert.Equal(t, claim.SessionHeader.SessionBlockHeight, sessionCtx.BlockHeight())
+	// the session block is not available
+	missing := createTestClaim(getRandomValidatorAddress(), "0001", 9, 10)
+	mockCtx.On("PrevCtx", missing.SessionHeader.SessionBlockHeight).Return(sdk.Context{}, fmt.Errorf("block at height not found"))
+	_, _, err = keeper.GetSessionForClaim(mockCtx, missing)
+	assert.NotNil(t, err)
+}
+
+func TestKeeper_SendClaimTxMultipleNodes(t *testing.T) {
+	ctx, _, _, _, keeper, _, _ := createTestInput(t, false)
+	ethereum := hex.EncodeToString([]byte{01})
+	header := types.SessionHeader{
+		ApplicationPubKey:  getTestApplication().PublicKey.RawString(),
+		Chain:              ethereum,
+		SessionBlockHeight: 1,
+	}
+	// two signing identities hosted by one process, each with its own evidence store and relay count
+	relaysByNode := map[string]int64{}
+	var nodes []*types.PocketNode
+	for _, numOfRelays := range []int64{5, 6} {
+		node := newTestPocketNode(t)
+		clientKey := getRandomPrivateKey()
+		for j := 0; j < int(numOfRelays); j++ {
+			proof := createProof(getTestApplicationPrivateKey(), clientKey, node.PrivateKey.PublicKey(), ethereum, j)
+			types.SetProof(header, types.RelayEvidence, proof, sdk.NewInt(100000), node.EvidenceStore)
+		}
+		acc := auth.NewBaseAccountWithAddress(node.GetAddress())
+		acc.Coins = sdk.NewCoins(sdk.NewCoin(sdk.DefaultStakeDenom, sdk.NewInt(100000000)))
+		keeper.authKeeper.(auth.Keeper).SetAccount(ctx, &acc)
+		relaysByNode[node.GetAddress().String()] = numOfRelays
+		nodes = append(nodes, node)
+	}
+	mockCtx := &Ctx{}
+	mockCtx.On("KVStore", mock.Anything).Return(func(key storeTypes.StoreKey) storeTypes.KVStore { return ctx.KVStore(key) })
+	mockCtx.On("Logger").Return(ctx.Logger())
+	mockCtx.On("BlockHeight").Return(header.SessionBlockHeight + keeper.BlocksPerSession(ctx))
+	mockCtx.On("ChainID").Return(ctx.ChainID())
+	mockCtx.On("PrevCtx", header.SessionBlockHeight).Return(ctx, nil)
+	claimedBy := map[string]int64{}
+	claimTx := func(pk crypto.PrivateKey, _ util.CLIContext, _ auth.TxBuilder, h types.SessionHeader, totalProofs int64, _ types.HashRange, _ types.EvidenceType) (*sdk.TxResponse, error) {
+		assert.Equal(t, header, h)
+		claimedBy[sdk.Address(pk.PublicKey().Address()).String()] = totalProofs
+		return nil, nil
+	}
+	for _, node := range nodes {
+		keeper.SendClaimTx(mockCtx, keeper, nil, node, claimTx)
+	}
+	assert.Equal(t, relaysByNode, claimedBy)
+}
+
+func TestKeeper_SendClaimTxMinProofsForClaim(t *testing.T) {
+	ctx, _, _, _, keeper, _, _ := createTestInput(t, false)
+	ethereum := hex.EncodeToString([]byte{01})
+	header := types.SessionHeader{
+		ApplicationPubKey:  getTestApplication().PublicKey.RawString(),
+		Chain:              ethereum,
+		SessionBlockHeight: 1,
+	}
+	node := newTestPocketNode(t)
+	clientKey := getRandomPrivateKey()
+	for j := 0; j < 5; j++ {
+		proof := createProof(getTestApplicationPrivateKey(), clientKey, node.PrivateKey.PublicKey(), ethereum, j)
+		types.SetProof(header, types.RelayEvidence, proof, sdk.NewInt(100000), node.EvidenceStore)
+	}
+	acc := auth.NewBaseAccountWithAddress(node.GetAddress())
+	acc.Coins = sdk.NewCoins(sdk.NewCoin(sdk.DefaultStakeDenom, sdk.NewInt(100000000)))
+	keeper.authKeeper.(auth.Keeper).SetAccount(ctx, &acc)
+	minProofs := types.GlobalPocketConfig.MinProofsForClaim
+	types.GlobalPocketConfig.MinProofsForClaim = 10
+	t.Cleanup(func() { types.GlobalPocketConfig.MinProofsForClaim = minProofs })
+	var claimed int64
+	claimTx := func(_ crypto.PrivateKey, _ util.CLIContext, _ auth.TxBuilder, _ types.SessionHeader, totalProofs int64, _ types.HashRange, _ types.EvidenceType) (*sdk.TxResponse, error) {
+		claimed = totalProofs
+		return nil, nil
+	}
+	sendAt := func(height int64) {
+		mockCtx := &Ctx{}
+		mockCtx.On("KVStore", mock.Anything).Return(func(key storeTypes.StoreKey) storeTypes.KVStore { return ctx.KVStore(key) })
+		mockCtx.On("Logger").Return(ctx.Logger())
+		mockCtx.On("BlockHeight").Return(height)
+		mockCtx.On("ChainID").Return(ctx.ChainID())
+		mockCtx.On("PrevCtx", header.SessionBlockHeight).Return(ctx, nil)
+		keeper.SendClaimTx(mockCtx, keeper, nil, node, claimTx)
+	}
+	blocksPerSession := keeper.BlocksPerSession(ctx)
+	// below the minimum, so the claim is deferred right after the session
+	sendAt(header.SessionBlockHeight + blocksPerSession)
+	assert.Zero(t, claimed)
+	// until the last session of the claim submission window
+	lastSession := header.SessionBlockHeight + (keeper.ClaimSubmissionWindow(ctx)-1)*blocksPerSession + 1
+	assert.False(t, keeper.ClaimWindowClosing(ctx.WithBlockHeight(lastSession-1), header.SessionBlockHeight))
+	assert.True(t, keeper.ClaimWindowClosing(ctx.WithBlockHeight(lastSession), header.SessionBlockHeight))
+	sendAt(lastSession)
+	assert.Equal(t, int64(5), claimed)
+}
+
+func TestKeeper_SendClaimTxChainAllowlist(t *testing.T) {
+	ctx, _, _, _, keeper, _, _ := createTestInput(t, false)
+	ethereum := hex.EncodeToString([]byte{01})
+	other := "0002"
+	node := newTestPocketNode(t)
+	clientKey := getRandomPrivateKey()
+	headers := make(map[string]types.SessionHeader)
+	for _, chain := range []string{ethereum, other} {
+		header := types.SessionHeader{
+			ApplicationPubKey:  getTestApplication().PublicKey.RawString(),
+			Chain:              chain,
+			SessionBlockHeight: 1,
+		}
+		for j := 0; j < 5; j++ {
+			proof := createProof(getTestApplicationPrivateKey(), clientKey, node.PrivateKey.PublicKey(), chain, j)
+			types.SetProof(header, types.RelayEvidence, proof, sdk.NewInt(100000), node.EvidenceStore)
+		}
+		headers[chain] = header
+	}
+	acc := auth.NewBaseAccountWithAddress(node.GetAddress())
+	acc.Coins = sdk.NewCoins(sdk.NewCoin(sdk.DefaultStakeDenom, sdk.NewInt(100000000)))
+	keeper.authKeeper.(auth.Keeper).SetAccount(ctx, &acc)
+	allowlist := types.GlobalPocketConfig.ClaimChainAllowlist
+	types.GlobalPocketConfig.ClaimChainAllowlist = []string{ethereum}
+	t.Cleanup(func() { types.GlobalPocketConfig.ClaimChainAllowlist = allowlist })
+	var claimed []string
+	claimTx := func(_ crypto.PrivateKey, _ util.CLIContext, _ auth.TxBuilder, header types.SessionHeader, _ int64, _ types.HashRange, _ types.EvidenceType) (*sdk.TxResponse, error) {
+		claimed = append(claimed, header.Chain)
+		return nil, nil
+	}
+	mockCtx := &Ctx{}
+	mockCtx.On("KVStore", mock.Anything).Return(func(key storeTypes.StoreKey) storeTypes.KVStore { return ctx.KVStore(key) })
+	mockCtx.On("Logger").Return(ctx.Logger())
+	mockCtx.On("BlockHeight").Return(1 + keeper.BlocksPerSession(ctx))
+	mockCtx.On("ChainID").Return(ctx.ChainID())
+	mockCtx.On("PrevCtx", int64(1)).Return(ctx, nil)
+	keeper.SendClaimTx(mockCtx, keeper, nil, node, claimTx)
+	// only the allowlisted chain is claimed
+	assert.Equal(t, []string{ethereum}, claimed)
+	// the other chain is unsupported, but its evidence is left in the cache untouched
+	evidence, err := types.GetEvidence(headers[other], types.RelayEvidence, sdk.NewInt(100000), node.EvidenceStore)
+	assert.Nil(t, err)
+	assert.Equal(t, int64(5), evidence.NumOfProofs)
+	// an empty allowlist claims every chain (the unsupported one is deleted as before)
+	types.GlobalPocketConfig.ClaimChainAllowlist = nil
+	assert.True(t, claimChainAllowed(other))
+	keeper.SendClaimTx(mockCtx, keeper, nil, node, claimTx)
+	_, found := types.GetProof(headers[other], types.RelayEvidence, 0, node.EvidenceStore)
+	assert.False(t, found)
+}
+
+func TestKeeper_ExportInitProofGenesis(t *testing.T) {
+	ctx, _, _, _, keeper, _, _ := createTestInput(t, false)
+	var claims []types.MsgClaim
+	for i := 0; i < 5; i++ {
+		claims = append(claims, createTestClaim(getRandomValidatorAddress(), "0001", 1, 10))
+	}
+	keeper.SetClaims(ctx, claims)
+	exported := keeper.ExportProofGenesis(ctx)
+	assert.Equal(t, types.ProofGenesisVersion, exported.Version)
+	assert.Len(t, exported.Claims, len(claims))
+	// the same state exports the same bytes
+	bz, err := types.ModuleCdc.MarshalJSON(exported)
+	assert.Nil(t, err)
+	again, err := types.ModuleCdc.MarshalJSON(keeper.ExportProofGenesis(ctx))
+	assert.Nil(t, err)
+	assert.Equal(t, bz, again)
+	// init into a fresh state reproduces it
+	freshCtx, _, _, _, fresh, _, _ := createTestInput(t, false)
+	var imported types.ProofGenesisState
+	assert.Nil(t, types.ModuleCdc.UnmarshalJSON(bz, &imported))
+	assert.Nil(t, fresh.InitProofGenesis(freshCtx, imported))
+	assert.Equal(t, exported, fresh.ExportProofGenesis(freshCtx))
+	// an unknown version is rejected
+	imported.Version++
+	assert.NotNil(t, fresh.InitProofGenesis(freshCtx, imported))
+}
+
+func TestCappedNumOfProofs(t *testing.T) {
+	evidence := types.Evidence{NumOfProofs: 10}
+	assert.Equal(t, int64(10), cappedNumOfProofs(evidence, 20))
+	assert.Equal(t, int64(10), cappedNumOfProofs(evidence, 10))
+	assert.Equal(t, int64(4), cappedNumOfProofs(evidence, 4))
+}
+
+func TestKeeper_CompactEvidenceCache(t *testing.T) {
+	ctx, _, _, _, keeper, _, _ := createTestInput(t, false)
+	node := newTestPocketNode(t)
+	ethereum := hex.EncodeToString([]byte{01})
+	clientKey := getRandomPrivateKey()
+	newHeader := func(sessionBlockHeight int64) types.SessionHeader {
+		header := types.SessionHeader{
+			ApplicationPubKey:  getRandomPubKey().RawString(),
+			Chain:              ethereum,
+			SessionBlockHeight: sessionBlockHeight,
+		}
+		proof := createProof(getTestApplicationPrivateKey(), clientKey, node.PrivateKey.PublicKey(), ethereum, 0)
+		types.SetProof(header, types.RelayEvidence, proof, sdk.NewInt(100000), node.EvidenceStore)
+		return header
+	}
+	// past the claim window with no claim in state (already proven, expired or never claimed)
+	done := newHeader(1)
+	// past the claim window but still claimed, so it's awaiting the proof
+	claimed := newHeader(1)
+	claim := createTestClaim(node.GetAddress(), ethereum, 1, 10)
+	claim.SessionHeader = claimed
+	_, err := keeper.SetClaim(ctx, claim)
+	assert.Nil(t, err)
+	// still claimable
+	open := newHeader(ctx.BlockHeight())
+	assert.Equal(t, 1, keeper.CompactEvidenceCache(ctx, node))
+	remaining := map[string]bool{}
+	iter := types.EvidenceIterator(node.EvidenceStore)
+	for ; iter.Valid(); iter.Next() {
+		remaining[iter.Value().SessionHeader.HashString()] = true
+	}
+	iter.Close()
+	assert.False(t, remaining[done.HashString()])
+	assert.Equal(t, map[string]bool{claimed.HashString(): true, open.HashString(): true}, remaining)
+	// nothing left to compact
+	assert.Equal(t, 0, keeper.CompactEvidenceCache(ctx, node))
+}
+
+func TestKeeper_GetOrphanedEvidence(t *testing.T) {
+	ctx, _, _, _, keeper, _, _ := createTestInput(t, false)
+	node := newTestPocketNode(t)
+	ethereum := hex.EncodeToString([]byte{01})
+	clientKey := getRandomPrivateKey()
+	newHeader := func(sessionBlockHeight int64) types.SessionHeader {
+		header := types.SessionHeader{
+			ApplicationPubKey:  getRandomPubKey().RawString(),
+			Chain:              ethereum,
+			SessionBlockHeight: sessionBlockHeight,
+		}
+		proof := createProof(getTestApplicationPrivateKey(), clientKey, node.PrivateKey.PublicKey(), ethereum, 0)
+		types.SetProof(header, types.RelayEvidence, proof, sdk.NewInt(100000), node.EvidenceStore)
+		return header
+	}
+	blocksPerSession := keeper.BlocksPerSession(ctx)
+	expiredAfter := (keeper.ClaimSubmissionWindow(ctx) + keeper.ClaimExpiration(ctx)) * blocksPerSession
+	height := 1 + expiredAfter + 2*blocksPerSession
+	// a claim sent at the end of its window would have expired
+	expired := newHeader(1)
+	// the last session whose claim would still be held
+	held := newHeader(height - expiredAfter + 1)
+	// still claimable
+	newHeader(height - blocksPerSession)
+	assert.Equal(t, []types.SessionHeader{expired}, keeper.GetOrphanedEvidence(ctx.WithBlockHeight(height), node))
+	// a session later, the held one expires as well
+	assert.ElementsMatch(t, []types.SessionHeader{expired, held}, keeper.GetOrphanedEvidence(ctx.WithBlockHeight(height+blocksPerSession), node))
+}
+
+func TestKeeper_DiffEvidenceCache(t *testing.T) {
+	ctx, _, _, _, keeper, _, _ := createTestInput(t, false)
+	node := newTestPocketNode(t)
+	ethereum := hex.EncodeToString([]byte{01})
+	clientKey := getRandomPrivateKey()
+	newHeader := func() types.SessionHeader {
+		return types.SessionHeader{ApplicationPubKey: getRandomPubKey().RawString(), Chain: ethereum, SessionBlockHeight: 1}
+	}
+	addEvidence := func(header types.SessionHeader) {
+		proof := createProof(getTestApplicationPrivateKey(), clientKey, node.PrivateKey.PublicKey(), ethereum, 0)
+		types.SetProof(header, types.RelayEvidence, proof, sdk.NewInt(100000), node.EvidenceStore)
+	}
+	addClaim := func(header types.SessionHeader) {
+		claim := createTestClaim(node.GetAddress(), ethereum, 1, 10)
+		claim.SessionHeader = header
+		_, err := keeper.SetClaim(ctx, claim)
+		assert.Nil(t, err)
+	}
+	// claimed and held locally: in neither list
+	both := newHeader()
+	addEvidence(both)
+	addClaim(both)
+	// held locally, never claimed
+	onlyLocal := newHeader()
+	addEvidence(onlyLocal)
+	// claimed, but the evidence is gone
+	onlyChain := newHeader()
+	addClaim(onlyChain)
+	// the claim of another servicer is not compared
+	other := createTestClaim(getRandomValidatorAddress(), ethereum, 1, 10)
+	_, err := keeper.SetClaim(ctx, other)
+	assert.Nil(t, err)
+	diff, err := keeper.DiffEvidenceCache(ctx, node)
+	assert.Nil(t, err)
+	assert.Equal(t, []types.SessionHeader{onlyLocal}, diff.OnlyLocal)
+	assert.Equal(t, []types.SessionHeader{onlyChain}, diff.OnlyChain)
+}
+
+func TestKeeper_CapEvidenceCache(t *testing.T) {
+	ctx, _, _, _, keeper, _, _ := createTestInput(t, false)
+	node := newTestPocketNode(t)
+	ethereum := hex.EncodeToString([]byte{01})
+	clientKey := getRandomPrivateKey()
+	newHeader := func(sessionBlockHeight int64, relays int) types.SessionHeader {
+		header := types.SessionHeader{
+			ApplicationPubKey:  getRandomPubKey().RawString(),
+			Chain:              ethereum,
+			SessionBlockHeight: sessionBlockHeight,
+		}
+		for j := 0; j < relays; j++ {
+			proof := createProof(getTestApplicationPrivateKey(), clientKey, node.PrivateKey.PublicKey(), ethereum, j)
+			types.SetProof(header, types.RelayEvidence, proof, sdk.NewInt(100000), node.EvidenceStore)
+		}
+		return header
+	}
+	remaining := func() map[string]bool {
+		r := map[string]bool{}
+		iter := types.EvidenceIterator(node.EvidenceStore)
+		defer iter.Close()
+		for ; iter.Valid(); iter.Next() {
+			r[iter.Value().SessionHeader.HashString()] = true
+		}
+		return r
+	}
+	// can no longer be claimed or proven, so it goes first despite its relays
+	done := newHeader(1, 6)
+	// awaiting the proof
+	claimed := newHeader(1, 3)
+	claim := createTestClaim(node.GetAddress(), ethereum, 1, 10)
+	claim.SessionHeader = claimed
+	_, err := keeper.SetClaim(ctx, claim)
+	assert.Nil(t, err)
+	// still claimable, with many and few relays
+	large := newHeader(ctx.BlockHeight(), 5)
+	small := newHeader(ctx.BlockHeight(), 2)
+	maxStored := types.GlobalPocketConfig.MaxStoredEvidence
+	t.Cleanup(func() { types.GlobalPocketConfig.MaxStoredEvidence = maxStored })
+	// unlimited by default
+	types.GlobalPocketConfig.MaxStoredEvidence = 0
+	assert.Zero(t, keeper.CapEvidenceCache(ctx, node))
+	assert.Len(t, remaining(), 4)
+	// within the cap
+	types.GlobalPocketConfig.MaxStoredEvidence = 4
+	assert.Zero(t, keeper.CapEvidenceCache(ctx, node))
+	// over the cap, the done evidence and then the fewest relays are evicted
+	types.GlobalPocketConfig.MaxStoredEvidence = 2
+	assert.Equal(t, 2, keeper.CapEvidenceCache(ctx, node))
+	r := remaining()
+	assert.False(t, r[done.HashString()])
+	assert.False(t, r[small.HashString()])
+	assert.Equal(t, map[string]bool{claimed.HashString(): true, large.HashString(): true}, r)
+}
+
+func TestKeeper_OnEvidenceEvicted(t *testing.T) {
+	ctx, _, _, _, keeper, _, _ := createTestInput(t, false)
+	node := newTestPocketNode(t)
+	ethereum := hex.EncodeToString([]byte{01})
+	clientKey := getRandomPrivateKey()
+	newHeader := func(sessionBlockHeight int64, relays int) types.SessionHeader {
+		header := types.SessionHeader{ApplicationPubKey: getRandomPubKey().RawString(), Chain: ethereum, SessionBlockHeight: sessionBlockHeight}
+		for j := 0; j < relays; j++ {
+			proof := createProof(getTestApplicationPrivateKey(), clientKey, node.PrivateKey.PublicKey(), ethereum, j)
+			types.SetProof(header, types.RelayEvidence, proof, sdk.NewInt(100000), node.EvidenceStore)
+		}
+		return header
+	}
+	done := newHeader(1, 6)
+	small := newHeader(ctx.BlockHeight(), 2)
+	large := newHeader(ctx.BlockHeight(), 5)
+	type eviction struct {
+		header    types.SessionHeader
+		relays    int64
+		claimable bool
+	}
+	var evicted []eviction
+	keeper.OnEvidenceEvicted(func(_ sdk.Ctx, n *types.PocketNode, evidence types.Evidence, claimable bool) {
+		assert.Equal(t, node, n)
+		evicted = append(evicted, eviction{evidence.SessionHeader, evidence.NumOfProofs, claimable})
+	})
+	assert.Panics(t, func() { keeper.OnEvidenceEvicted(func(sdk.Ctx, *types.PocketNode, types.Evidence, bool) {}) })
+	maxStored := types.GlobalPocketConfig.MaxStoredEvidence
+	t.Cleanup(func() { types.GlobalPocketConfig.MaxStoredEvidence = maxStored })
+	// within the cap nothing is evicted
+	types.GlobalPocketConfig.MaxStoredEvidence = 3
+	assert.Zero(t, keeper.CapEvidenceCache(ctx, node))
+	assert.Empty(t, evicted)
+	// the hook fires for each evicted session, with its relays and whether it could still be paid for
+	types.GlobalPocketConfig.MaxStoredEvidence = 1
+	assert.Equal(t, 2, keeper.CapEvidenceCache(ctx, node))
+	assert.Equal(t, []eviction{{done, 6, false}, {small, 2, true}}, evicted)
+	_, err := types.GetEvidence(large, types.RelayEvidence, sdk.ZeroInt(), node.EvidenceStore)
+	assert.Nil(t, err)
+}
+
+func TestKeeper_ClaimServicerAddress(t *testing.T) {
+	_, _, _, _, keeper, _, _ := createTestInput(t, false)
+	ethereum := hex.EncodeToString([]byte{01})
+	header := types.SessionHeader{
+		ApplicationPubKey:  getTestApplication().PublicKey.RawString(),
+		Chain:              ethereum,
+		SessionBlockHeight: 1,
+	}
+	node := newTestPocketNode(t)
+	clientKey := getRandomPrivateKey()
+	for j := 0; j < 3; j++ {
+		proof := createProof(getTestApplicationPrivateKey(), clientKey, node.PrivateKey.PublicKey(), ethereum, j)
+		types.SetProof(header, types.RelayEvidence, proof, sdk.NewInt(100000), node.EvidenceStore)
+	}
+	// host the node in this process
+	types.GlobalPocketNodes[node.GetAddress().String()] = node
+	t.Cleanup(func() { delete(types.GlobalPocketNodes, node.GetAddress().String()) })
+	claim := createTestClaim(node.GetAddress(), ethereum, 1, 3)
+	claim.SessionHeader = header
+	// the servicer of the evidence sent the claim
+	servicer, err := keeper.ClaimServicerAddress(claim)
+	assert.Nil(t, err)
+	assert.Equal(t, node.GetAddress(), servicer)
+	// a spoofed from address
+	spoofed := claim
+	spoofed.FromAddress = getRandomValidatorAddress()
+	servicer, err = keeper.ClaimServicerAddress(spoofed)
+	assert.NotNil(t, err)
+	assert.Equal(t, sdk.CodeType(types.CodeClaimServicerMismatchError), err.(sdk.Error).Code())
+	assert.Equal(t, node.GetAddress(), servicer)
+	// no evidence for the session
+	missing := claim
+	missing.SessionHeader.SessionBlockHeight = 5
+	_, err = keeper.ClaimServicerAddress(missing)
+	assert.NotNil(t, err)
+}
+
+func TestKeeper_ClaimIsProvable(t *testing.T) {
+	ctx, _, _, _, keeper, _, _ := createTestInput(t, false)
+	sessionBlockHeight := int64(1)
+	maturity := sessionBlockHeight + keeper.ClaimSubmissionWindow(ctx)*keeper.BlocksPerSession(ctx) + 1
+	// without a buffer a claim is provable as soon as it is mature
+	assert.False(t, keeper.ClaimIsProvable(ctx.WithBlockHeight(maturity-1), sessionBlockHeight))
+	assert.True(t, keeper.ClaimIsProvable(ctx.WithBlockHeight(maturity), sessionBlockHeight))
+	buffer := types.GlobalPocketConfig.ProofMaturityBuffer
+	types.GlobalPocketConfig.ProofMaturityBuffer = 3
+	t.Cleanup(func() { types.GlobalPocketConfig.ProofMaturityBuffer = buffer })
+	// the buffer delays proving by its blocks
+	for height := maturity; height < maturity+3; height++ {
+		assert.True(t, keeper.ClaimIsMature(ctx.WithBlockHeight(height), sessionBlockHeight))
+		assert.False(t, keeper.ClaimIsProvable(ctx.WithBlockHeight(height), sessionBlockHeight), height)
+	}
+	assert.True(t, keeper.ClaimIsProvable(ctx.WithBlockHeight(maturity+3), sessionBlockHeight))
+}
+
+func TestKeeper_ProofContextHeight(t *testing.T) {
+	ctx, _, _, _, keeper, _, _ := createTestInput(t, false)
+	header := types.SessionHeader{ApplicationPubKey: getRandomPubKey().RawString(), Chain: "0001", SessionBlockHeight: 1}
+	sessionCtx := ctx.WithBlockHeight(header.SessionBlockHeight)
+	proofHeight, err := keeper.proofContextHeight(ctx, header.SessionBlockHeight)
+	assert.Nil(t, err)
+	// the proof index is seeded by the block hash at the proof context height (no other height's hash is mocked)
+	mockCtx := &Ctx{}
+	mockCtx.On("GetPrevBlockHash", proofHeight).Return(types.Hash([]byte("block")), nil)
+	_, err = keeper.getPseudorandomIndex(mockCtx, 10, header, sessionCtx)
+	assert.Nil(t, err)
+	mockCtx.AssertCalled(t, "GetPrevBlockHash", proofHeight)
+	// and the claim is mature from the next height on
+	assert.False(t, keeper.ClaimIsMature(ctx.WithBlockHeight(proofHeight), header.SessionBlockHeight))
+	assert.True(t, keeper.ClaimIsMature(ctx.WithBlockHeight(proofHeight+1), header.SessionBlockHeight))
+	timeline, err := keeper.GetClaimTimeline(ctx, getRandomValidatorAddress(), header, types.RelayEvidence)
+	assert.Nil(t, err)
+	assert.Equal(t, proofHeight+1, timeline.ProvableAtHeight)
+	// the window is changed after the session began
+	codec.UpgradeFeatureMap[codec.ClaimMaturityIndexKey] = 1
+	t.Cleanup(func() { delete(codec.UpgradeFeatureMap, codec.ClaimMaturityIndexKey) })
+	changedCtx, _ := ctx.CacheContext()
+	claim := createTestClaim(getRandomValidatorAddress(), header.Chain, header.SessionBlockHeight, 10)
+	_, err = keeper.SetClaim(changedCtx, claim)
+	assert.Nil(t, err)
+	params := keeper.GetParams(changedCtx)
+	params.ClaimSubmissionWindow += 2
+	keeper.SetParams(changedCtx, params)
+	currentCtx := func(height int64, sessionErr error) *Ctx {
+		mockCtx := &Ctx{}
+		mockCtx.On("BlockHeight").Return(height)
+		mockCtx.On("Logger").Return(ctx.Logger())
+		mockCtx.On("KVStore", mock.Anything).Return(func(key storeTypes.StoreKey) storeTypes.KVStore { return changedCtx.KVStore(key) })
+		mockCtx.On("PrevCtx", header.SessionBlockHeight).Return(sessionCtx, sessionErr)
+		mockCtx.On("GetPrevBlockHash", proofHeight).Return(types.Hash([]byte("block")), nil)
+		return mockCtx
+	}
+	isMature := func(height int64) bool {
+		mature, err := keeper.GetMatureClaims(currentCtx(height, nil), claim.FromAddress)
+		assert.Nil(t, err)
+		assert.Equal(t, len(mature) == 1, keeper.ClaimIsMature(currentCtx(height, nil), header.SessionBlockHeight), height)
+		return len(mature) == 1
+	}
+	// before SPCTX the maturity reads the current params, the proof index the session's
+	changedProofHeight := header.SessionBlockHeight + keeper.ClaimSubmissionWindow(changedCtx)*keeper.BlocksPerSession(changedCtx)
+	assert.NotEqual(t, proofHeight, changedProofHeight)
+	assert.False(t, isMature(proofHeight+1))
+	assert.True(t, isMature(changedProofHeight+1))
+	// after SPCTX both (and the maturity index) keep the session's params
+	codec.UpgradeFeatureMap[codec.SessionProofContextKey] = 1
+	t.Cleanup(func() { delete(codec.UpgradeFeatureMap, codec.SessionProofContextKey) })
+	mockCtx = currentCtx(proofHeight+1, nil)
+	contextHeight, err := keeper.proofContextHeight(mockCtx, header.SessionBlockHeight)
+	assert.Nil(t, err)
+	assert.Equal(t, proofHeight, contextHeight)
+	_, err = keeper.getPseudorandomIndex(mockCtx, 10, header, sessionCtx)
+	assert.Nil(t, err)
+	mockCtx.AssertCalled(t, "GetPrevBlockHash", proofHeight)
+	assert.False(t, isMature(proofHeight))
+	assert.True(t, isMature(proofHeight+1))
+	maturityHeight, err := keeper.ClaimMaturityHeight(currentCtx(proofHeight+1, nil), header.SessionBlockHeight)
+	assert.Nil(t, err)
+	assert.Equal(t, proofHeight+1, maturityHeight)
+	// an unavailable session block is an error, and the claim is not mature
+	_, err = keeper.ClaimMaturityHeight(currentCtx(proofHeight+1, fmt.Errorf("block at height not found")), header.SessionBlockHeight)
+	assert.NotNil(t, err)
+	assert.False(t, keeper.ClaimIsMature(currentCtx(proofHeight+1, fmt.Errorf("block at height not found")), header.SessionBlockHeight))
+}
+
+func TestKeeper_SendClaimTxLockedNode(t *testing.T) {
+	ctx, _, _, _, keeper, _, _ := createTestInput(t, false)
+	ethereum := hex.EncodeToString([]byte{01})
+	header := types.SessionHeader{
+		ApplicationPubKey:  getTestApplication().PublicKey.RawString(),
+		Chain:              ethereum,
+		SessionBlockHeight: 1,
+	}
+	node := newTestPocketNode(t)
+	clientKey := getRandomPrivateKey()
+	for j := 0; j < 5; j++ {
+		proof := createProof(getTestApplicationPrivateKey(), clientKey, node.PrivateKey.PublicKey(), ethereum, j)
+		types.SetProof(header, types.RelayEvidence, proof, sdk.NewInt(100000), node.EvidenceStore)
+	}
+	acc := auth.NewBaseAccountWithAddress(node.GetAddress())
+	acc.Coins = sdk.NewCoins(sdk.NewCoin(sdk.DefaultStakeDenom, sdk.NewInt(100000000)))
+	keeper.authKeeper.(auth.Keeper).SetAccount(ctx, &acc)
+	var claimed int64
+	claimTx := func(_ crypto.PrivateKey, _ util.CLIContext, _ auth.TxBuilder, _ types.SessionHeader, totalProofs int64, _ types.HashRange, _ types.EvidenceType) (*sdk.TxResponse, error) {
+		claimed = totalProofs
+		return nil, nil
+	}
+	proofTx := func(util.CLIContext, auth.TxBuilder, types.MerkleProof, types.Proof, types.EvidenceType) (*sdk.TxResponse, error) {
+		t.Fatal("a locked node sent a proof")
+		return nil, nil
+	}
+	mockCtx := &Ctx{}
+	mockCtx.On("KVStore", mock.Anything).Return(func(key storeTypes.StoreKey) storeTypes.KVStore { return ctx.KVStore(key) })
+	mockCtx.On("Logger").Return(ctx.Logger())
+	mockCtx.On("BlockHeight").Return(header.SessionBlockHeight + keeper.BlocksPerSession(ctx))
+	mockCtx.On("ChainID").Return(ctx.ChainID())
+	mockCtx.On("PrevCtx", header.SessionBlockHeight).Return(ctx, nil)
+	// the key is not loaded, so the cycle is skipped instead of panicking
+	key := node.PrivateKey
+	node.PrivateKey = nil
+	assert.True(t, node.IsLocked())
+	assert.NotPanics(t, func() {
+		keeper.SendClaimTx(mockCtx, keeper, nil, node, claimTx)
+		keeper.SendProofTx(mockCtx, nil, node, proofTx, nil)
+	})
+	assert.Zero(t, claimed)
+	// and resumes once it is loaded
+	node.PrivateKey = key
+	assert.False(t, node.IsLocked())
+	keeper.SendClaimTx(mockCtx, keeper, nil, node, claimTx)
+	assert.Equal(t, int64(5), claimed)
+}
+
+func TestKeeper_SendClaimTxMerkleMemoryBudget(t *testing.T) {
+	ctx, _, _, _, keeper, _, _ := createTestInput(t, false)
+	ethereum := hex.EncodeToString([]byte{01})
+	node := newTestPocketNode(t)
+	clientKey := getRandomPrivateKey()
+	header := types.SessionHeader{
+		ApplicationPubKey:  getTestApplication().PublicKey.RawString(),
+		Chain:              ethereum,
+		SessionBlockHeight: 1,
+	}
+	for j := 0; j < 5; j++ {
+		proof := createProof(getTestApplicationPrivateKey(), clientKey, node.PrivateKey.PublicKey(), ethereum, j)
+		types.SetProof(header, types.RelayEvidence, proof, sdk.NewInt(100000), node.EvidenceStore)
+	}
+	acc := auth.NewBaseAccountWithAddress(node.GetAddress())
+	acc.Coins = sdk.NewCoins(sdk.NewCoin(sdk.DefaultStakeDenom, sdk.NewInt(100000000)))
+	keeper.authKeeper.(auth.Keeper).SetAccount(ctx, &acc)
+	budget := types.GlobalPocketConfig.MerkleMemoryBudget
+	types.GlobalPocketConfig.MerkleMemoryBudget = types.EstimateMerkleMemory(5) - 1
+	t.Cleanup(func() { types.GlobalPocketConfig.MerkleMemoryBudget = budget })
+	var claimed int
+	claimTx := func(_ crypto.PrivateKey, _ util.CLIContext, _ auth.TxBuilder, _ types.SessionHeader, _ int64, _ types.HashRange, _ types.EvidenceType) (*sdk.TxResponse, error) {
+		claimed++
+		return nil, nil
+	}
+	mockCtx := &Ctx{}
+	mockCtx.On("KVStore", mock.Anything).Return(func(key storeTypes.StoreKey) storeTypes.KVStore { return ctx.KVStore(key) })
+	mockCtx.On("Logger").Return(ctx.Logger())
+	mockCtx.On("BlockHeight").Return(1 + keeper.BlocksPerSession(ctx))
+	mockCtx.On("ChainID").Return(ctx.ChainID())
+	mockCtx.On("PrevCtx", int64(1)).Return(ctx, nil)
+	// over budget: the claim is not sent and the evidence is left in the cache
+	keeper.SendClaimTx(mockCtx, keeper, nil, node, claimTx)
+	assert.Zero(t, claimed)
+	evidence, err := types.GetEvidence(header, types.RelayEvidence, sdk.NewInt(100000), node.EvidenceStore)
+	assert.Nil(t, err)
+	assert.Equal(t, int64(5), evidence.NumOfProofs)
+	// within budget: the claim is sent
+	types.GlobalPocketConfig.MerkleMemoryBudget = types.EstimateMerkleMemory(5)
+	keeper.SendClaimTx(mockCtx, keeper, nil, node, claimTx)
+	assert.Equal(t, 1, claimed)
+}
+
+func TestKeeper_SendClaimTxClaimBreaker(t *testing.T) {
+	ctx, _, _, _, keeper, _, _ := createTestInput(t, false)
+	ethereum := hex.EncodeToString([]byte{01})
+	header := types.SessionHeader{
+		ApplicationPubKey:  getTestApplication().PublicKey.RawString(),
+		Chain:              ethereum,
+		SessionBlockHeight: 1,
+	}
+	node := newTestPocketNode(t)
+	clientKey := getRandomPrivateKey()
+	for j := 0; j < 5; j++ {
+		proof := createProof(getTestApplicationPrivateKey(), clientKey, node.PrivateKey.PublicKey(), ethereum, j)
+		types.SetProof(header, types.RelayEvidence, proof, sdk.NewInt(100000), node.EvidenceStore)
+	}
+	acc := auth.NewBaseAccountWithAddress(node.GetAddress())
+	acc.Coins = sdk.NewCoins(sdk.NewCoin(sdk.DefaultStakeDenom, sdk.NewInt(100000000)))
+	keeper.authKeeper.(auth.Keeper).SetAccount(ctx, &acc)
+	threshold, cooldown := types.GlobalPocketConfig.ClaimFailureThreshold, types.GlobalPocketConfig.ClaimFailureCooldown
+	types.GlobalPocketConfig.ClaimFailureThreshold, types.GlobalPocketConfig.ClaimFailureCooldown = 2, 2
+	t.Cleanup(func() {
+		types.GlobalPocketConfig.ClaimFailureThreshold, types.GlobalPocketConfig.ClaimFailureCooldown = threshold, cooldown
+	})
+	var attempts int
+	fail := true
+	claimTx := func(_ crypto.PrivateKey, _ util.CLIContext, _ auth.TxBuilder, _ types.SessionHeader, _ int64, _ types.HashRange, _ types.EvidenceType) (*sdk.TxResponse, error) {
+		attempts++
+		if fail {
+			return nil, errors.New("insufficient funds")
+		}
+		return nil, nil
+	}
+	cycle := func(height int64) {
+		mockCtx := &Ctx{}
+		mockCtx.On("KVStore", mock.Anything).Return(func(key storeTypes.StoreKey) storeTypes.KVStore { return ctx.KVStore(key) })
+		mockCtx.On("Logger").Return(ctx.Logger())
+		mockCtx.On("BlockHeight").Return(height)
+		mockCtx.On("ChainID").Return(ctx.ChainID())
+		mockCtx.On("PrevCtx", header.SessionBlockHeight).Return(ctx, nil)
+		keeper.SendClaimTx(mockCtx, keeper, nil, node, claimTx)
+	}
+	height := header.SessionBlockHeight + keeper.BlocksPerSession(ctx)
+	// the failures below the threshold are retried
+	cycle(height)
+	cycle(height)
+	assert.Equal(t, 2, attempts)
+	// the threshold is reached, so the cycle is skipped during the cooldown
+	assert.True(t, node.ClaimBreaker.Paused(height))
+	cycle(height + 1)
+	assert.Equal(t, 2, attempts)
+	// after the cooldown a claim is probed; it still fails, so the cycle is paused again
+	cycle(height + 2)
+	assert.Equal(t, 3, attempts)
+	cycle(height + 3)
+	assert.Equal(t, 3, attempts)
+	// the next probe succeeds and the auto claims resume
+	fail = false
+	cycle(height + 4)
+	assert.Equal(t, 4, attempts)
+	assert.False(t, node.ClaimBreaker.Paused(height+4))
+}
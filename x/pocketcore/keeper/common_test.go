@@ -91,6 +91,7 @@ func createTestInput(t *testing.T, isCheckTx bool) (sdk.Ctx, []nodesTypes.Valida
 	nodesKey := sdk.NewKVStoreKey(nodesTypes.StoreKey)
 	appsKey := sdk.NewKVStoreKey(appsTypes.StoreKey)
 	pocketKey := sdk.NewKVStoreKey(types.StoreKey)
+	pocketTKey := sdk.NewTransientStoreKey(types.TStoreKey)
 
 	keys := make(map[string]*sdk.KVStoreKey)
 	keys["params"] = keyParams
@@ -105,6 +106,7 @@ func createTestInput(t *testing.T, isCheckTx bool) (sdk.Ctx, []nodesTypes.Valida
 	ms.MountStoreWithDB(appsKey, sdk.StoreTypeIAVL, db)
 	ms.MountStoreWithDB(pocketKey, sdk.StoreTypeIAVL, db)
 	ms.MountStoreWithDB(tkeyParams, sdk.StoreTypeTransient, db)
+	ms.MountStoreWithDB(pocketTKey, sdk.StoreTypeTransient, db)
 	err = ms.LoadLatestVersion()
 	require.Nil(t, err)
 
@@ -168,7 +170,7 @@ func createTestInput(t *testing.T, isCheckTx bool) (sdk.Ctx, []nodesTypes.Valida
 	nk := nodesKeeper.NewKeeper(cdc, nodesKey, ak, nodesSubspace, nodesTypes.ModuleName)
 	appk := appsKeeper.NewKeeper(cdc, appsKey, nk, ak, nil, appSubspace, appsTypes.ModuleName)
 	appk.SetApplication(ctx, getTestApplication())
-	keeper := NewKeeper(pocketKey, cdc, ak, nk, appk, &hb, pocketSubspace)
+	keeper := NewKeeper(pocketKey, pocketTKey, cdc, ak, nk, appk, &hb, pocketSubspace)
 	appk.PocketKeeper = keeper
 	assert.Nil(t, err)
 	moduleManager := module.NewManager(
@@ -395,6 +397,33 @@ func simulateRelays(t *testing.T, k Keeper, ctx *sdk.Ctx, maxRelays int) (npk cr
 	return
 }
 
+// creates a pocket node with its own (on disk) evidence store, as hosted in lean pocket
+func newTestPocketNode(t *testing.T) *types.PocketNode {
+	node := &types.PocketNode{PrivateKey: getRandomPrivateKey(), EvidenceStore: &types.CacheStorage{}}
+	node.EvidenceStore.Init(t.TempDir(), "evidence", sdk.DefaultTestingPocketConfig().TendermintConfig.LevelDBOptions, 100, false)
+	t.Cleanup(func() { _ = node.EvidenceStore.DB.Close() })
+	return node
+}
+
+// creates a claim for state tests; the expiration height is preset so no session context is needed to store it
+func createTestClaim(address sdk.Address, chain string, sessionBlockHeight int64, totalProofs int64) types.MsgClaim {
+	return types.MsgClaim{
+		SessionHeader: types.SessionHeader{
+			ApplicationPubKey:  getRandomPubKey().RawString(),
+			Chain:              chain,
+			SessionBlockHeight: sessionBlockHeight,
+		},
+		MerkleRoot: types.HashRange{
+			Hash:  types.Hash([]byte(chain)),
+			Range: types.Range{Lower: 0, Upper: uint64(totalProofs)},
+		},
+		TotalProofs:      totalProofs,
+		FromAddress:      address,
+		EvidenceType:     types.RelayEvidence,
+		ExpirationHeight: 1000,
+	}
+}
+
 func createProof(private, client crypto.PrivateKey, npk crypto.PublicKey, chain string, entropy int) types.Proof {
 	aat := types.AAT{
 		Version:              "0.0.1",
@@ -0,0 +1,65 @@
+package keeper
+
+import (
+	"github.com/pokt-network/pocket-core/codec"
+	"github.com/pokt-network/pocket-core/crypto"
+	sdk "github.com/pokt-network/pocket-core/types"
+	pc "github.com/pokt-network/pocket-core/x/pocketcore/types"
+)
+
+// "SetClaimer" - Delegates the claims and proofs of the servicer to the claimer; an empty claimer revokes the delegation
+func (k Keeper) SetClaimer(ctx sdk.Ctx, servicer, claimer sdk.Address) {
+	store := ctx.KVStore(k.storeKey)
+	if claimer.Empty() {
+		_ = store.Delete(pc.KeyForClaimer(servicer))
+		return
+	}
+	_ = store.Set(pc.KeyForClaimer(servicer), claimer.Bytes())
+}
+
+// "GetClaimer" - Returns the claimer the servicer delegated its claims and proofs to, if any
+func (k Keeper) GetClaimer(ctx sdk.Ctx, servicer sdk.Address) (claimer sdk.Address, found bool) {
+	store := ctx.KVStore(k.storeKey)
+	bz, _ := store.Get(pc.KeyForClaimer(servicer))
+	if bz == nil {
+		return nil, false
+	}
+	return sdk.Address(bz), true
+}
+
+// "IsAuthorizedClaimer" - Returns true if the signer may send the claims and proofs of the servicer: it is the
+// servicer itself or the claimer the servicer delegated to
+func (k Keeper) IsAuthorizedClaimer(ctx sdk.Ctx, servicer, signer sdk.Address) bool {
+	if signer.Equals(servicer) {
+		return true
+	}
+	claimer, found := k.GetClaimer(ctx, servicer)
+	return found && claimer.Equals(signer)
+}
+
+// "ValidateClaimCommitment" - Verifies the commitment is the servicer's signature of the claim's merkle root, relay count
+// and session (see MsgClaim.CommitmentBytes), so a claim sent by a claimer carries the relay count the servicer built
+func (k Keeper) ValidateClaimCommitment(ctx sdk.Ctx, claim pc.MsgClaim, commitment []byte) sdk.Error {
+	node, found := k.GetNode(ctx, claim.FromAddress)
+	if !found {
+		return pc.NewInvalidClaimCommitmentError(pc.ModuleName, "the servicer is not staked")
+	}
+	if !node.GetPublicKey().VerifyBytes(claim.CommitmentBytes(), commitment) {
+		return pc.NewInvalidClaimCommitmentError(pc.ModuleName, "the signature does not verify")
+	}
+	return nil
+}
+
+// "autoTxKey" - Returns the key that signs the auto claim and proof txs of the node: its claimer key if the servicer
+// delegated to that claimer on-chain, else the servicer key; delegated reports whether the claimer key is used
+func (k Keeper) autoTxKey(ctx sdk.Ctx, node *pc.PocketNode) (key crypto.PrivateKey, delegated bool) {
+	if node.ClaimerKey == nil || !k.Cdc.IsAfterNamedFeatureActivationHeight(ctx.BlockHeight(), codec.DelegatedClaimerKey) {
+		return node.PrivateKey, false
+	}
+	claimer := sdk.Address(node.ClaimerKey.PublicKey().Address())
+	if !k.IsAuthorizedClaimer(ctx, node.GetAddress(), claimer) {
+		ctx.Logger().Error("the claimer key of the pocket node is not the claimer delegated to on-chain, so the servicer key signs its txs", "claimer", claimer.String())
+		return node.PrivateKey, false
+	}
+	return node.ClaimerKey, true
+}
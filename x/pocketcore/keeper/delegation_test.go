@@ -0,0 +1,43 @@
+package keeper
+
+import (
+	"testing"
+
+	"github.com/pokt-network/pocket-core/codec"
+	sdk "github.com/pokt-network/pocket-core/types"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestKeeper_AutoTxKey(t *testing.T) {
+	ctx, _, _, _, keeper, _, _ := createTestInput(t, false)
+	node := newTestPocketNode(t)
+	// without a claimer key, the servicer signs
+	key, delegated := keeper.autoTxKey(ctx, node)
+	assert.False(t, delegated)
+	assert.Equal(t, node.PrivateKey, key)
+	node.ClaimerKey = getRandomPrivateKey()
+	claimer := sdk.Address(node.ClaimerKey.PublicKey().Address())
+	keeper.SetClaimer(ctx, node.GetAddress(), claimer)
+	// before activation, the servicer signs
+	_, delegated = keeper.autoTxKey(ctx, node)
+	assert.False(t, delegated)
+	codec.UpgradeFeatureMap[codec.DelegatedClaimerKey] = 1
+	t.Cleanup(func() { delete(codec.UpgradeFeatureMap, codec.DelegatedClaimerKey) })
+	key, delegated = keeper.autoTxKey(ctx, node)
+	assert.True(t, delegated)
+	assert.Equal(t, node.ClaimerKey, key)
+	assert.True(t, keeper.IsAuthorizedClaimer(ctx, node.GetAddress(), claimer))
+	assert.True(t, keeper.IsAuthorizedClaimer(ctx, node.GetAddress(), node.GetAddress()))
+	// a claimer key that is not the one delegated to on-chain is not used
+	keeper.SetClaimer(ctx, node.GetAddress(), getRandomValidatorAddress())
+	key, delegated = keeper.autoTxKey(ctx, node)
+	assert.False(t, delegated)
+	assert.Equal(t, node.PrivateKey, key)
+	assert.False(t, keeper.IsAuthorizedClaimer(ctx, node.GetAddress(), claimer))
+	// nor once the delegation is revoked
+	keeper.SetClaimer(ctx, node.GetAddress(), nil)
+	_, found := keeper.GetClaimer(ctx, node.GetAddress())
+	assert.False(t, found)
+	_, delegated = keeper.autoTxKey(ctx, node)
+	assert.False(t, delegated)
+}
@@ -18,11 +18,22 @@ type Keeper struct {
 	hostedBlockchains *types.HostedBlockchains
 	Paramstore        sdk.Subspace
 	storeKey          sdk.StoreKey // Unexposed key to access store from sdk.Context
+	tStoreKey         sdk.StoreKey // Unexposed key to access the transient store (reset every block, not committed)
 	Cdc               *codec.Codec // The wire codec for binary encoding/decoding.
+	claimVerifiedHook ClaimVerifiedHook
+	evictedHook       EvidenceEvictedHook
+	verifyCaches      *proofVerificationCaches // Shared by the copies of the keeper (it is passed by value)
 }
 
+// "ClaimVerifiedHook" - Is called with a relay claim once its proof is verified and its relays are rewarded
+type ClaimVerifiedHook func(ctx sdk.Ctx, claim types.MsgClaim)
+
+// "EvidenceEvictedHook" - Is called with the evidence a node evicted to stay within max_stored_evidence, and whether
+// it could still be claimed or proven (so its reward is lost)
+type EvidenceEvictedHook func(ctx sdk.Ctx, node *types.PocketNode, evidence types.Evidence, claimable bool)
+
 // NewKeeper creates new instances of the pocketcore module Keeper
-func NewKeeper(storeKey sdk.StoreKey, cdc *codec.Codec, authKeeper types.AuthKeeper, posKeeper types.PosKeeper, appKeeper types.AppsKeeper, hostedChains *types.HostedBlockchains, paramstore sdk.Subspace) Keeper {
+func NewKeeper(storeKey sdk.StoreKey, tStoreKey *sdk.TransientStoreKey, cdc *codec.Codec, authKeeper types.AuthKeeper, posKeeper types.PosKeeper, appKeeper types.AppsKeeper, hostedChains *types.HostedBlockchains, paramstore sdk.Subspace) Keeper {
 	return Keeper{
 		authKeeper:        authKeeper,
 		posKeeper:         posKeeper,
@@ -30,8 +41,30 @@ func NewKeeper(storeKey sdk.StoreKey, cdc *codec.Codec, authKeeper types.AuthKee
 		hostedBlockchains: hostedChains,
 		Paramstore:        paramstore.WithKeyTable(ParamKeyTable()),
 		storeKey:          storeKey,
+		tStoreKey:         tStoreKey,
 		Cdc:               cdc,
+		verifyCaches:      &proofVerificationCaches{},
+	}
+}
+
+// "OnClaimVerified" - Registers a hook called after the default reward of every verified relay claim, so other reward
+// schemes can attach without editing the keeper; like TmNode, it must be set before the keeper is handed to the modules
+func (k *Keeper) OnClaimVerified(hook ClaimVerifiedHook) *Keeper {
+	if k.claimVerifiedHook != nil {
+		panic("cannot set the claim verified hook twice")
+	}
+	k.claimVerifiedHook = hook
+	return k
+}
+
+// "OnEvidenceEvicted" - Registers a hook called for every session CapEvidenceCache evicts from a node's evidence cache, so
+// operators can track the relays dropped; it can only be set once
+func (k *Keeper) OnEvidenceEvicted(hook EvidenceEvictedHook) *Keeper {
+	if k.evictedHook != nil {
+		panic("cannot set the evidence evicted hook twice")
 	}
+	k.evictedHook = hook
+	return k
 }
 
 func (k Keeper) Codec() *codec.Codec {
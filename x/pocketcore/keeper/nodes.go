@@ -3,6 +3,7 @@ package keeper
 import (
 	sdk "github.com/pokt-network/pocket-core/types"
 	"github.com/pokt-network/pocket-core/x/nodes/exported"
+	pc "github.com/pokt-network/pocket-core/x/pocketcore/types"
 )
 
 // "GetNode" - Gets a node from the state storage
@@ -19,6 +20,23 @@ func (k Keeper) AwardCoinsForRelays(ctx sdk.Ctx, relays int64, toAddr sdk.Addres
 	return k.posKeeper.RewardForRelays(ctx, sdk.NewInt(relays), toAddr)
 }
 
+// "AwardCoinsForChainRelays" - Award coins to nodes for relays of a chain completed, weighted by its relay reward multiplier
+func (k Keeper) AwardCoinsForChainRelays(ctx sdk.Ctx, chain string, relays int64, toAddr sdk.Address) sdk.BigInt {
+	return k.posKeeper.RewardForRelays(ctx, k.weightedRelays(ctx, chain, relays), toAddr)
+}
+
+// "EstimateClaimReward" - Estimates the coins a node would be awarded for proving a claim of totalRelays for the chain,
+// without awarding them
+func (k Keeper) EstimateClaimReward(ctx sdk.Ctx, address sdk.Address, chain string, totalRelays int64) sdk.Coin {
+	return sdk.NewCoin(k.posKeeper.StakeDenom(ctx), k.posKeeper.EstimateRewardForRelays(ctx, k.weightedRelays(ctx, chain, totalRelays), address))
+}
+
+// "weightedRelays" - Returns the relays of the chain scaled by its relay reward multiplier (truncated), the relays the
+// node is rewarded for
+func (k Keeper) weightedRelays(ctx sdk.Ctx, chain string, relays int64) sdk.BigInt {
+	return sdk.NewInt(relays).MulRaw(k.RelayRewardMultiplier(ctx, chain)).QuoRaw(pc.RelayRewardMultiplierBasis)
+}
+
 // "BurnCoinsForChallenges" - Executes the burn for challenge function in the nodes module
 func (k Keeper) BurnCoinsForChallenges(ctx sdk.Ctx, relays int64, toAddr sdk.Address) {
 	k.posKeeper.BurnForChallenge(ctx, sdk.NewInt(relays), toAddr)
@@ -0,0 +1,54 @@
+package keeper
+
+import (
+	"testing"
+
+	sdk "github.com/pokt-network/pocket-core/types"
+	"github.com/pokt-network/pocket-core/x/pocketcore/types"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestKeeper_EstimateClaimReward(t *testing.T) {
+	ctx, vals, _, _, k, _, _ := createTestInput(t, false)
+	addr := vals[0].Address
+	previous := sdk.ZeroInt()
+	for _, relays := range []int64{0, 1, 10, 100, 1000} {
+		reward := k.EstimateClaimReward(ctx, addr, "0001", relays)
+		assert.Equal(t, k.posKeeper.StakeDenom(ctx), reward.Denom)
+		// more relays never estimate a smaller reward
+		assert.True(t, reward.Amount.GTE(previous))
+		previous = reward.Amount
+	}
+	assert.True(t, previous.IsPositive())
+	// the estimate must not mint anything
+	supply := k.posKeeper.TotalTokens(ctx)
+	k.EstimateClaimReward(ctx, addr, "0001", 1000)
+	assert.Equal(t, supply, k.posKeeper.TotalTokens(ctx))
+	// the estimate matches what is awarded
+	assert.Equal(t, previous, k.AwardCoinsForRelays(ctx, 1000, addr))
+}
+
+func TestKeeper_EstimateClaimRewardMultipliers(t *testing.T) {
+	ctx, vals, _, _, k, _, _ := createTestInput(t, false)
+	addr := vals[0].Address
+	// without multipliers every chain is rewarded alike
+	assert.Equal(t, k.EstimateClaimReward(ctx, addr, "0001", 1000), k.EstimateClaimReward(ctx, addr, "0002", 1000))
+	assert.Equal(t, types.RelayRewardMultiplierBasis, k.RelayRewardMultiplier(ctx, "0001"))
+	// additional params are not set at genesis
+	ctx = ctx.WithBlockHeight(10)
+	p := k.GetParams(ctx)
+	p.RelayRewardMultipliers = []types.RelayRewardMultiplier{{Chain: "0001", Multiplier: 20000}, {Chain: "0002", Multiplier: 5000}}
+	k.SetParams(ctx, p)
+	assert.Equal(t, p.RelayRewardMultipliers, k.GetParams(ctx).RelayRewardMultipliers)
+	assert.Equal(t, int64(5000), k.RelayRewardMultiplier(ctx, "0002"))
+	assert.Equal(t, types.RelayRewardMultiplierBasis, k.RelayRewardMultiplier(ctx, "0003"))
+	// the relays of each chain are weighted by its multiplier, an unlisted chain by 1
+	unlisted := func(relays int64) sdk.Coin { return k.EstimateClaimReward(ctx, addr, "0003", relays) }
+	doubled, halved := k.EstimateClaimReward(ctx, addr, "0001", 1000), k.EstimateClaimReward(ctx, addr, "0002", 1000)
+	assert.Equal(t, unlisted(2000), doubled)
+	assert.Equal(t, unlisted(500), halved)
+	assert.True(t, doubled.Amount.GT(unlisted(1000).Amount))
+	assert.True(t, halved.Amount.LT(unlisted(1000).Amount))
+	// the estimate matches what is awarded
+	assert.Equal(t, doubled.Amount, k.AwardCoinsForChainRelays(ctx, "0001", 1000, addr))
+}
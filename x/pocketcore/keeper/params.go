@@ -1,6 +1,8 @@
 package keeper
 
 import (
+	"sort"
+
 	sdk "github.com/pokt-network/pocket-core/types"
 	"github.com/pokt-network/pocket-core/x/pocketcore/types"
 )
@@ -64,16 +66,82 @@ func (k Keeper) BlockByteSize(ctx sdk.Ctx) (res int64) {
 	return
 }
 
+// "MaxClaimsDeletedPerBlock" - Returns the max claims deleted per block parameter from the paramstore
+// How many expired claims are deleted each block (0 is unlimited)
+func (k Keeper) MaxClaimsDeletedPerBlock(ctx sdk.Ctx) (res int64) {
+	k.Paramstore.Get(ctx, types.KeyMaxClaimsDeletedPerBlock, &res)
+	return
+}
+
+// "MaxProofsPerAddressPerBlock" - Returns the max proofs per address per block parameter from the paramstore
+// How many proofs a single address can submit each block (0 is unlimited)
+func (k Keeper) MaxProofsPerAddressPerBlock(ctx sdk.Ctx) (res int64) {
+	k.Paramstore.Get(ctx, types.KeyMaxProofsPerAddressPerBlock, &res)
+	return
+}
+
+// "MaxRelaysPerSession" - Returns the max relays per session parameter from the paramstore
+// How many relays a claim can be proven for, which bounds the merkle proof levels (0 is unlimited)
+func (k Keeper) MaxRelaysPerSession(ctx sdk.Ctx) (res int64) {
+	k.Paramstore.Get(ctx, types.KeyMaxRelaysPerSession, &res)
+	return
+}
+
+// "RelayRewardMultipliers" - Returns the relay reward multipliers parameter from the paramstore (nil if none)
+func (k Keeper) RelayRewardMultipliers(ctx sdk.Ctx) (res []types.RelayRewardMultiplier) {
+	k.Paramstore.Get(ctx, types.KeyRelayRewardMultipliers, &res)
+	if len(res) == 0 {
+		return nil
+	}
+	return
+}
+
+// "RelayRewardMultiplier" - Returns the relay reward multiplier of the chain in basis points (RelayRewardMultiplierBasis
+// if the chain has none)
+func (k Keeper) RelayRewardMultiplier(ctx sdk.Ctx, chain string) int64 {
+	multipliers := k.RelayRewardMultipliers(ctx)
+	i := sort.Search(len(multipliers), func(i int) bool { return multipliers[i].Chain >= chain })
+	if i < len(multipliers) && multipliers[i].Chain == chain {
+		return multipliers[i].Multiplier
+	}
+	return types.RelayRewardMultiplierBasis
+}
+
+// "RejectSelfSignedProofs" - Returns the reject self signed proofs parameter from the paramstore
+// Whether relay proofs signed by the servicer's own key are rejected
+func (k Keeper) RejectSelfSignedProofs(ctx sdk.Ctx) (res bool) {
+	k.Paramstore.Get(ctx, types.KeyRejectSelfSignedProofs, &res)
+	return
+}
+
 // "GetParams" - Returns all module parameters in a `Params` struct
 func (k Keeper) GetParams(ctx sdk.Ctx) types.Params {
 	return types.Params{
-		SessionNodeCount:           k.SessionNodeCount(ctx),
-		ClaimSubmissionWindow:      k.ClaimSubmissionWindow(ctx),
-		SupportedBlockchains:       k.SupportedBlockchains(ctx),
-		ClaimExpiration:            k.ClaimExpiration(ctx),
-		ReplayAttackBurnMultiplier: k.ReplayAttackBurnMultiplier(ctx),
-		MinimumNumberOfProofs:      k.MinimumNumberOfProofs(ctx),
-		BlockByteSize:              k.BlockByteSize(ctx),
+		SessionNodeCount:            k.SessionNodeCount(ctx),
+		ClaimSubmissionWindow:       k.ClaimSubmissionWindow(ctx),
+		SupportedBlockchains:        k.SupportedBlockchains(ctx),
+		ClaimExpiration:             k.ClaimExpiration(ctx),
+		ReplayAttackBurnMultiplier:  k.ReplayAttackBurnMultiplier(ctx),
+		MinimumNumberOfProofs:       k.MinimumNumberOfProofs(ctx),
+		BlockByteSize:               k.BlockByteSize(ctx),
+		MaxClaimsDeletedPerBlock:    k.MaxClaimsDeletedPerBlock(ctx),
+		MaxProofsPerAddressPerBlock: k.MaxProofsPerAddressPerBlock(ctx),
+		MaxRelaysPerSession:         k.MaxRelaysPerSession(ctx),
+		RelayRewardMultipliers:      k.RelayRewardMultipliers(ctx),
+		RejectSelfSignedProofs:      k.RejectSelfSignedProofs(ctx),
+	}
+}
+
+// "GetProofParams" - Returns the parameters governing claims and proofs in a `ProofParams` struct
+func (k Keeper) GetProofParams(ctx sdk.Ctx) types.ProofParams {
+	return types.ProofParams{
+		BlocksPerSession:            k.BlocksPerSession(ctx),
+		ClaimSubmissionWindow:       k.ClaimSubmissionWindow(ctx),
+		ClaimExpiration:             k.ClaimExpiration(ctx),
+		MinimumNumberOfProofs:       k.MinimumNumberOfProofs(ctx),
+		MaxClaimsDeletedPerBlock:    k.MaxClaimsDeletedPerBlock(ctx),
+		MaxProofsPerAddressPerBlock: k.MaxProofsPerAddressPerBlock(ctx),
+		MaxRelaysPerSession:         k.MaxRelaysPerSession(ctx),
 	}
 }
 
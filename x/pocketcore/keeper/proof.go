@@ -1,6 +1,8 @@
 package keeper
 
 import (
+	"bytes"
+	"encoding/binary"
 	"encoding/hex"
 	"encoding/json"
 	"fmt"
@@ -13,11 +15,18 @@ import (
 	"github.com/tendermint/tendermint/rpc/client"
 	"math"
 	"reflect"
+	"sort"
+	"strconv"
+	"sync"
 	"time"
 )
 
 // auto sends a proof transaction for the claim
-func (k Keeper) SendProofTx(ctx sdk.Ctx, n client.Client, node *pc.PocketNode, proofTx func(cliCtx util.CLIContext, txBuilder auth.TxBuilder, merkleProof pc.MerkleProof, leafNode pc.Proof, evidenceType pc.EvidenceType) (*sdk.TxResponse, error)) {
+func (k Keeper) SendProofTx(ctx sdk.Ctx, n client.Client, node *pc.PocketNode, proofTx func(cliCtx util.CLIContext, txBuilder auth.TxBuilder, merkleProof pc.MerkleProof, leafNode pc.Proof, evidenceType pc.EvidenceType) (*sdk.TxResponse, error), proofBatchTx func(cliCtx util.CLIContext, txBuilder auth.TxBuilder, proofs []pc.MsgProof, allOrNothing bool) (*sdk.TxResponse, error)) {
+	if node.IsLocked() {
+		ctx.Logger().Error("skipping the proof-txs of a pocket node without a loaded private key")
+		return
+	}
 	addr := node.GetAddress()
 	// get all mature (waiting period has passed) claims for your address
 	claims, err := k.GetMatureClaims(ctx, addr)
@@ -25,66 +34,128 @@ func (k Keeper) SendProofTx(ctx sdk.Ctx, n client.Client, node *pc.PocketNode, p
 		ctx.Logger().Error(fmt.Sprintf("an error occured getting the mature claims in the Proof Transaction:\n%v", err))
 		return
 	}
+	// the proofs are signed by the delegated claimer if any (proofTx then wraps the proof)
+	key, delegated := k.autoTxKey(ctx, node)
+	var proofMsg sdk.ProtoMsg = &pc.MsgProof{}
+	if delegated {
+		proofMsg = &pc.MsgDelegatedProof{}
+	}
+	// if batching is enabled, the proofs are collected and sent in batches instead of a tx per claim (a batch is
+	// signed by the servicer, so delegated proofs are never batched)
+	batchSize := pc.GlobalPocketConfig.ProofBatchSize
+	if batchSize > pc.MaxProofBatchSize {
+		batchSize = pc.MaxProofBatchSize
+	}
+	batching := batchSize > 1 && !delegated && k.Cdc.IsAfterNamedFeatureActivationHeight(ctx.BlockHeight(), codec.ProofBatchKey)
+	var batch []pc.MsgProof
+	var batchClaims []pc.MsgClaim
+	sendBatch := func() {
+		if txHash, sent := k.sendProofBatch(ctx, n, node, proofBatchTx, batch); sent {
+			for _, claim := range batchClaims {
+				node.InFlightProofs.Set(claim, txHash, ctx.BlockHeight())
+			}
+		}
+		batch, batchClaims = nil, nil
+	}
+	defer func() {
+		if len(batch) != 0 {
+			sendBatch()
+		}
+	}()
+	// learn the outcome of the proof-txs sent before: a proven claim is deleted from the world state
+	k.reconcileInFlightProofs(ctx, node)
 
 	// for every claim of the mature set
 	for _, claim := range claims {
 		now := time.Now()
+		// log with the session context so operators can trace why a claim was (or was not) proven
+		logger := ctx.Logger().With(sessionLogKeyVals(claim.SessionHeader, claim.TotalProofs)...)
+		// wait out the configured buffer past maturity before proving (guards against reorgs near the maturity height)
+		if !k.ClaimIsProvable(ctx, claim.SessionHeader.SessionBlockHeight) {
+			logger.Info(fmt.Sprintf("the claim is mature, waiting %d buffer blocks before proving it", pc.GlobalPocketConfig.ProofMaturityBuffer))
+			continue
+		}
+		// only prove within the configured submission window (spreads the proof-txs of a node over fewer blocks)
+		if !k.proofWindowOpen(ctx, claim) {
+			logger.Info(fmt.Sprintf("the claim is outside its %d block proof submission window, deferring its proof", pc.GlobalPocketConfig.ProofSubmissionWindow))
+			continue
+		}
+		// a claim still held after its proof-tx was sent means the proof was rejected on chain (or the tx was dropped);
+		// it is proven again while the claim is within its expiration window
+		if sent, found := node.InFlightProofs.Get(claim.SessionHeader, claim.EvidenceType); found {
+			if sent.Height >= ctx.BlockHeight() {
+				logger.Info("the proof-tx is pending", "txhash", sent.TxHash)
+				continue
+			}
+			if ctx.BlockHeight() >= claim.ExpirationHeight {
+				logger.Error(fmt.Sprintf("the proof-tx %s sent at height %d was rejected and the claim has expired, so will not retry it", sent.TxHash, sent.Height))
+				node.InFlightProofs.Delete(claim.SessionHeader, claim.EvidenceType)
+				continue
+			}
+			logger.Error(fmt.Sprintf("the proof-tx %s sent at height %d was rejected, retrying the proof (attempt %d)", sent.TxHash, sent.Height, sent.Attempts+1))
+		}
 		// check to see if evidence is stored in cache
 		evidence, err := pc.GetEvidence(claim.SessionHeader, claim.EvidenceType, sdk.ZeroInt(), node.EvidenceStore)
 		if err != nil || evidence.Proofs == nil || len(evidence.Proofs) == 0 {
-			ctx.Logger().Info(fmt.Sprintf("the evidence object for evidence is not found, ignoring pending claim for app: %s, at sessionHeight: %d", claim.SessionHeader.ApplicationPubKey, claim.SessionHeader.SessionBlockHeight))
+			logger.Info(fmt.Sprintf("the evidence object for evidence is not found, ignoring pending claim for app: %s, at sessionHeight: %d", claim.SessionHeader.ApplicationPubKey, claim.SessionHeader.SessionBlockHeight))
 			continue
 		}
 		if ctx.BlockHeight()-claim.SessionHeader.SessionBlockHeight > int64(pc.GlobalPocketConfig.MaxClaimAgeForProofRetry) {
 			err := pc.DeleteEvidence(claim.SessionHeader, claim.EvidenceType, node.EvidenceStore)
-			ctx.Logger().Error(fmt.Sprintf("deleting evidence older than MaxClaimAgeForProofRetry"))
+			logger.Error(fmt.Sprintf("deleting evidence older than MaxClaimAgeForProofRetry"))
 			if err != nil {
-				ctx.Logger().Error(fmt.Sprintf("unable to delete evidence that is older than 32 blocks: %s", err.Error()))
+				logger.Error(fmt.Sprintf("unable to delete evidence that is older than 32 blocks: %s", err.Error()))
 			}
 			continue
 		}
 		if !node.EvidenceStore.IsSealed(evidence) {
 			err := pc.DeleteEvidence(claim.SessionHeader, claim.EvidenceType, node.EvidenceStore)
-			ctx.Logger().Error(fmt.Sprintf("evidence is not sealed, could cause a relay leak:"))
-			if err != nil {
-				ctx.Logger().Error(fmt.Sprintf("could not delete evidence is not sealed, could cause a relay leak: %s", err.Error()))
-			}
-		}
-		if evidence.NumOfProofs != claim.TotalProofs {
-			err := pc.DeleteEvidence(claim.SessionHeader, claim.EvidenceType, node.EvidenceStore)
-			ctx.Logger().Error(fmt.Sprintf("evidence num of proofs does not equal claim total proofs... possible relay leak"))
+			logger.Error(fmt.Sprintf("evidence is not sealed, could cause a relay leak:"))
 			if err != nil {
-				ctx.Logger().Error(fmt.Sprintf("evidence num of proofs does not equal claim total proofs... possible relay leak: %s", err.Error()))
+				logger.Error(fmt.Sprintf("could not delete evidence is not sealed, could cause a relay leak: %s", err.Error()))
 			}
 		}
 		// get the session context
-		sessionCtx, err := ctx.PrevCtx(claim.SessionHeader.SessionBlockHeight)
+		_, sessionCtx, err := k.GetSessionForClaim(ctx, claim)
 		if err != nil {
-			ctx.Logger().Info(fmt.Sprintf("could not get Session Context, ignoring pending claim for app: %s, at sessionHeight: %d", claim.SessionHeader.ApplicationPubKey, claim.SessionHeader.SessionBlockHeight))
+			logger.Info(fmt.Sprintf("could not get Session Context, ignoring pending claim for app: %s, at sessionHeight: %d", claim.SessionHeader.ApplicationPubKey, claim.SessionHeader.SessionBlockHeight))
 			continue
 		}
 		// generate the needed pseudorandom index using the information found in the first transaction
-		index, err := k.getPseudorandomIndex(ctx, claim.TotalProofs, claim.SessionHeader, sessionCtx)
+		index, err := k.requiredProofIndex(ctx, claim, sessionCtx)
 		if err != nil {
-			ctx.Logger().Error(err.Error())
+			logger.Error(err.Error())
 			continue
 		}
 		app, found := k.GetAppFromPublicKey(sessionCtx, claim.SessionHeader.ApplicationPubKey)
 		if !found {
-			ctx.Logger().Error(fmt.Sprintf("an error occurred creating the proof transaction with app %s not found with evidence %v", evidence.ApplicationPubKey, evidence))
+			logger.Error(fmt.Sprintf("an error occurred creating the proof transaction with app %s not found with evidence %v", evidence.ApplicationPubKey, evidence))
+		}
+		maxRelays := pc.MaxPossibleRelays(app, k.SessionNodeCount(sessionCtx)).Int64()
+		// the claim was sent for the capped number of proofs (see SendClaimTx)
+		if cappedNumOfProofs(evidence, maxRelays) != claim.TotalProofs {
+			err := pc.DeleteEvidence(claim.SessionHeader, claim.EvidenceType, node.EvidenceStore)
+			logger.Error(fmt.Sprintf("evidence num of proofs does not equal claim total proofs... possible relay leak"))
+			if err != nil {
+				logger.Error(fmt.Sprintf("evidence num of proofs does not equal claim total proofs... possible relay leak: %s", err.Error()))
+			}
 		}
 		// get the merkle proof object for the pseudorandom index
-		mProof, leaf := evidence.GenerateMerkleProof(claim.SessionHeader.SessionBlockHeight, int(index), pc.MaxPossibleRelays(app, k.SessionNodeCount(sessionCtx)).Int64())
+		mProof, leaf, err := requiredProofArtifacts(evidence, index, maxRelays)
+		if err != nil {
+			logger.Error(fmt.Sprintf("could not generate the proof for index %d: %s", index, err.Error()))
+			continue
+		}
 		// if prevalidation on, then pre-validate
 		if pc.GlobalPocketConfig.ProofPrevalidation {
 			// validate level count on claim by total relays
 			levelCount := len(mProof.HashRanges)
 			if levelCount != int(math.Ceil(math.Log2(float64(claim.TotalProofs)))) {
-				ctx.Logger().Error(fmt.Sprintf("produced invalid proof for pending claim for app: %s, at sessionHeight: %d, level count", claim.SessionHeader.ApplicationPubKey, claim.SessionHeader.SessionBlockHeight))
+				logger.Error(fmt.Sprintf("produced invalid proof for pending claim for app: %s, at sessionHeight: %d, level count", claim.SessionHeader.ApplicationPubKey, claim.SessionHeader.SessionBlockHeight))
 				continue
 			}
 			if isValid, _ := mProof.Validate(claim.SessionHeader.SessionBlockHeight, claim.MerkleRoot, leaf, levelCount); !isValid {
-				ctx.Logger().Error(fmt.Sprintf("produced invalid proof for pending claim for app: %s, at sessionHeight: %d", claim.SessionHeader.ApplicationPubKey, claim.SessionHeader.SessionBlockHeight))
+				logger.Error(fmt.Sprintf("produced invalid proof for pending claim for app: %s, at sessionHeight: %d", claim.SessionHeader.ApplicationPubKey, claim.SessionHeader.SessionBlockHeight))
 				continue
 			}
 		}
@@ -92,33 +163,187 @@ func (k Keeper) SendProofTx(ctx sdk.Ctx, n client.Client, node *pc.PocketNode, p
 		go func() {
 			pc.GlobalServiceMetric().AddProofTiming(evidence.SessionHeader.Chain, proofTxTotalTime, &addr)
 		}()
+		if batching {
+			logger.Info("adding the proof to the proof batch", "index", index)
+			batch = append(batch, pc.MsgProof{MerkleProof: mProof, Leaf: leaf, EvidenceType: evidence.EvidenceType})
+			batchClaims = append(batchClaims, claim)
+			if len(batch) == batchSize {
+				sendBatch()
+			}
+			continue
+		}
 		// generate the auto txbuilder and clictx
-		txBuilder, cliCtx, err := newTxBuilderAndCliCtx(ctx, &pc.MsgProof{}, n, node.PrivateKey, k)
+		txBuilder, cliCtx, err := newTxBuilderAndCliCtx(ctx, proofMsg, n, key, k)
 		if err != nil {
-			ctx.Logger().Error(fmt.Sprintf("an error occured in the transaction process of the Proof Transaction:\n%v", err))
+			logger.Error(fmt.Sprintf("an error occured in the transaction process of the Proof Transaction:\n%v", err))
 			return
 		}
+		logger.Info("sending the proof-tx", "index", index)
 		// send the proof TX
-		_, err = proofTx(cliCtx, txBuilder, mProof, leaf, evidence.EvidenceType)
+		res, err := proofTx(cliCtx, txBuilder, mProof, leaf, evidence.EvidenceType)
 		if err != nil {
-			ctx.Logger().Error(err.Error())
+			logger.Error(err.Error())
+			continue
+		}
+		addFeesSpent(node, txBuilder, res)
+		var txHash string
+		if res != nil {
+			txHash = res.TxHash
+			logger.Info("the proof-tx was sent", "txhash", txHash)
+		}
+		node.InFlightProofs.Set(claim, txHash, ctx.BlockHeight())
+	}
+}
+
+// "reconcileInFlightProofs" - Stops tracking the proof-txs whose claims are no longer held in the world state, logging
+// whether each was proven (deleted before its expiration height) or expired
+func (k Keeper) reconcileInFlightProofs(ctx sdk.Ctx, node *pc.PocketNode) {
+	addr := node.GetAddress()
+	for _, sent := range node.InFlightProofs.All() {
+		if _, found := k.GetClaim(ctx, addr, sent.SessionHeader, sent.EvidenceType); found {
+			continue
+		}
+		logger := ctx.Logger().With("chain", sent.SessionHeader.Chain, "session_height", sent.SessionHeader.SessionBlockHeight)
+		if ctx.BlockHeight() < sent.ExpirationHeight {
+			logger.Info(fmt.Sprintf("the proof-tx %s was accepted after %d attempt(s)", sent.TxHash, sent.Attempts))
+		} else {
+			logger.Error(fmt.Sprintf("the claim of the proof-tx %s expired before a proof was accepted", sent.TxHash))
+		}
+		node.InFlightProofs.Delete(sent.SessionHeader, sent.EvidenceType)
+	}
+}
+
+// "GetPendingProofs" - Returns the sessions whose proof-txs the node at address (hosted by this process) sent but whose
+// claims are still held in the world state, i.e. the proofs generated but not confirmed yet, sorted by session height
+func (k Keeper) GetPendingProofs(ctx sdk.Ctx, address sdk.Address) []pc.SessionHeader {
+	headers := make([]pc.SessionHeader, 0)
+	node, err := pc.GetPocketNodeByAddress(&address)
+	if err != nil {
+		return headers
+	}
+	for _, sent := range node.InFlightProofs.All() {
+		if _, found := k.GetClaim(ctx, address, sent.SessionHeader, sent.EvidenceType); found {
+			headers = append(headers, sent.SessionHeader)
 		}
 	}
+	sort.Slice(headers, func(i, j int) bool {
+		if headers[i].SessionBlockHeight != headers[j].SessionBlockHeight {
+			return headers[i].SessionBlockHeight < headers[j].SessionBlockHeight
+		}
+		return headers[i].HashString() < headers[j].HashString()
+	})
+	return headers
+}
+
+// "addFeesSpent" - Adds the fees of a sent auto tx to the node's fees spent, unless the tx was rejected by the mempool
+// (a tx that is not included in a block pays no fees)
+func addFeesSpent(node *pc.PocketNode, txBuilder auth.TxBuilder, res *sdk.TxResponse) {
+	if res != nil && res.Code != 0 {
+		return
+	}
+	node.FeesSpent.Add(txBuilder.Fees())
+}
+
+// "GetClaimFeesSpent" - Returns the fees of the auto claim and proof txs the node at address (hosted by this process) has
+// sent since it started; a node not hosted by this process has spent none
+func (k Keeper) GetClaimFeesSpent(ctx sdk.Ctx, address sdk.Address) sdk.Coins {
+	node, err := pc.GetPocketNodeByAddress(&address)
+	if err != nil {
+		return sdk.NewCoins()
+	}
+	return node.FeesSpent.Total()
+}
+
+// "sendProofBatch" - Sends the proofs in a single proof batch tx; invalid proofs are rejected individually
+// Returns the hash of the tx and whether it was sent
+func (k Keeper) sendProofBatch(ctx sdk.Ctx, n client.Client, node *pc.PocketNode, proofBatchTx func(cliCtx util.CLIContext, txBuilder auth.TxBuilder, proofs []pc.MsgProof, allOrNothing bool) (*sdk.TxResponse, error), proofs []pc.MsgProof) (txHash string, sent bool) {
+	// generate the auto txbuilder and clictx
+	// the fee is charged per proof
+	txBuilder, cliCtx, err := newTxBuilderAndCliCtx(ctx, &pc.MsgProofBatch{Proofs: proofs}, n, node.PrivateKey, k)
+	if err != nil {
+		ctx.Logger().Error(fmt.Sprintf("an error occured in the transaction process of the Proof Batch Transaction:\n%v", err))
+		return
+	}
+	ctx.Logger().Info("sending the proof-batch-tx", "proofs", len(proofs))
+	res, err := proofBatchTx(cliCtx, txBuilder, proofs, false)
+	if err != nil {
+		ctx.Logger().Error(err.Error())
+		return
+	}
+	addFeesSpent(node, txBuilder, res)
+	if res != nil {
+		txHash = res.TxHash
+		ctx.Logger().Info("the proof-batch-tx was sent", "proofs", len(proofs), "txhash", txHash)
+	}
+	return txHash, true
 }
 
 func (k Keeper) ValidateProof(ctx sdk.Ctx, proof pc.MsgProof) (servicerAddr sdk.Address, claim pc.MsgClaim, sdkError sdk.Error) {
+	return k.validateProof(ctx, proof, true)
+}
+
+// "validateProof" - Validates the proof (see ValidateProof), recording why it was rejected in the service metrics if
+// recordFailures is set; the validation query leaves it unset, so audits do not count as rejected proof txs
+func (k Keeper) validateProof(ctx sdk.Ctx, proof pc.MsgProof, recordFailures bool) (servicerAddr sdk.Address, claim pc.MsgClaim, sdkError sdk.Error) {
+	// record why the proof was rejected in the service metrics
+	fail := func(reason string, err sdk.Error) (sdk.Address, pc.MsgClaim, sdk.Error) {
+		if recordFailures {
+			pc.GlobalServiceMetric().AddProofValidationFailure(reason)
+		}
+		return servicerAddr, claim, err
+	}
+	// the proof is indexed below, so reject a malformed one instead of panicking (ValidateBasic may not have been run)
+	if proof.Leaf == nil {
+		return fail(pc.ProofFailureMalformed, pc.NewMalformedProofError(pc.ModuleName, "the leaf is empty"))
+	}
+	if len(proof.MerkleProof.HashRanges) == 0 {
+		return fail(pc.ProofFailureMalformed, pc.NewMalformedProofError(pc.ModuleName, "the merkle proof has no branches"))
+	}
+	// a relay signed by the servicer's own key was likely fabricated by the servicer; it only depends on the leaf, so it
+	// is detected before the claim is looked up, and rejected if the params say so
+	if leaf, ok := proof.Leaf.(pc.RelayProof); ok && leaf.IsSelfSigned() {
+		if k.RejectSelfSignedProofs(ctx) {
+			return fail(pc.ProofFailureSelfSigned, pc.NewSelfSignedProofError(pc.ModuleName))
+		}
+		ctx.Logger().Info(fmt.Sprintf("the relay proof of servicer %s for session %d is signed by its own key", leaf.ServicerPubKey, leaf.SessionBlockHeight))
+	}
+	// with a max relays per session, a proof can have at most the levels of a tree of that many relays; reject a larger
+	// proof before looking up its claim, and a claim of more relays before its level count is derived from them
+	maxRelays := k.MaxRelaysPerSession(ctx)
+	if maxRelays > 0 {
+		if maxLevels := int(math.Ceil(math.Log2(float64(maxRelays)))); len(proof.MerkleProof.HashRanges) > maxLevels {
+			return fail(pc.ProofFailureLevelCount, k.invalidProofError(ctx, pc.NewInvalidProofLevelCountError(pc.ModuleName, len(proof.MerkleProof.HashRanges), maxLevels)))
+		}
+	}
 	// get the public key from the claim
 	servicerAddr = proof.GetSigners()[0]
 	// get the claim for the address
 	claim, found := k.GetClaim(ctx, servicerAddr, proof.GetLeaf().SessionHeader(), proof.EvidenceType)
 	// if the claim is not found for this claim
 	if !found {
-		return servicerAddr, claim, pc.NewClaimNotFoundError(pc.ModuleName)
+		return fail(pc.ProofFailureClaimNotFound, pc.NewClaimNotFoundError(pc.ModuleName))
+	}
+	// the claim is found by the leaf's session, so it must be the claim of that session and not one stored under a
+	// colliding key; otherwise relays of one session could be proven against the claim of another (the header holds the
+	// chain, so this also keeps a relay of a cheap chain from proving the claim of a valuable one)
+	if claim.SessionHeader.Canonical() != proof.GetLeaf().SessionHeader().Canonical() {
+		return fail(pc.ProofFailureHeaderMismatch, pc.NewProofClaimHeaderMismatchError(pc.ModuleName))
+	}
+	// a claim with an empty or all zero root (only stored before EMROOT) cannot be proven
+	if claim.MerkleRoot.IsZero() && k.Cdc.IsAfterNamedFeatureActivationHeight(ctx.BlockHeight(), codec.EmptyMerkleRootKey) {
+		return fail(pc.ProofFailureEmptyRoot, pc.NewEmptyMerkleRootError(pc.ModuleName))
+	}
+	if maxRelays > 0 && claim.TotalProofs > maxRelays {
+		return fail(pc.ProofFailureLevelCount, pc.NewClaimExceedsMaxRelaysError(pc.ModuleName, claim.TotalProofs, maxRelays))
 	}
 	// validate level count on claim by total relays
 	levelCount := len(proof.MerkleProof.HashRanges)
-	if levelCount != int(math.Ceil(math.Log2(float64(claim.TotalProofs)))) {
-		return servicerAddr, claim, pc.NewInvalidProofsError(pc.ModuleName)
+	if requiredLevelCount := int(math.Ceil(math.Log2(float64(claim.TotalProofs)))); levelCount != requiredLevelCount {
+		return fail(pc.ProofFailureLevelCount, k.invalidProofError(ctx, pc.NewInvalidProofLevelCountError(pc.ModuleName, levelCount, requiredLevelCount)))
+	}
+	// the target must be one of the claimed leafs, in [0, TotalProofs)
+	if index := proof.MerkleProof.TargetIndex; index < 0 || index >= claim.TotalProofs {
+		return fail(pc.ProofFailureIndexOutOfRange, k.invalidProofError(ctx, pc.NewMerkleIndexOutOfRangeError(pc.ModuleName, int(index), int(claim.TotalProofs))))
 	}
 	var hasMatch bool
 	for _, m := range proof.MerkleProof.HashRanges {
@@ -128,46 +353,174 @@ func (k Keeper) ValidateProof(ctx sdk.Ctx, proof pc.MsgProof) (servicerAddr sdk.
 		}
 	}
 	if !hasMatch && proof.MerkleProof.Target.Range.Upper != claim.MerkleRoot.Range.Upper {
-		return servicerAddr, claim, pc.NewInvalidMerkleVerifyError(pc.ModuleName)
+		return fail(pc.ProofFailureRootRange, pc.NewInvalidMerkleVerifyError(pc.ModuleName))
 	}
 	// get the session context
-	sessionCtx, err := ctx.PrevCtx(claim.SessionHeader.SessionBlockHeight)
-	if err != nil {
-		return servicerAddr, claim, sdk.ErrInternal(err.Error())
+	_, sessionCtx, sdkError := k.GetSessionForClaim(ctx, claim)
+	if sdkError != nil {
+		return fail(pc.ProofFailureSessionCtx, sdkError)
 	}
 	// validate the proof
 	ctx.Logger().Info(fmt.Sprintf("Generate psuedorandom proof with %d proofs, at session height of %d, for app: %s", claim.TotalProofs, claim.SessionHeader.SessionBlockHeight, claim.SessionHeader.ApplicationPubKey))
-	reqProof, err := k.getPseudorandomIndex(ctx, claim.TotalProofs, claim.SessionHeader, sessionCtx)
+	reqProof, err := k.requiredProofIndex(ctx, claim, sessionCtx)
 	if err != nil {
-		return servicerAddr, claim, sdk.ErrInternal(err.Error())
+		return fail(pc.ProofFailurePseudorandomIdx, sdk.ErrInternal(err.Error()))
 	}
 	// if the required proof message index does not match the leaf node index
 	if reqProof != int64(proof.MerkleProof.TargetIndex) {
-		return servicerAddr, claim, pc.NewInvalidProofsError(pc.ModuleName)
+		return fail(pc.ProofFailureIndexMismatch, k.invalidProofError(ctx, pc.NewInvalidProofIndexError(pc.ModuleName, proof.MerkleProof.TargetIndex, reqProof)))
 	}
 	// validate the merkle proofs
 	isValid, isReplayAttack := proof.MerkleProof.Validate(claim.SessionHeader.SessionBlockHeight, claim.MerkleRoot, proof.GetLeaf(), levelCount)
 	// if is not valid for other reasons
 	if !isValid {
 		if isReplayAttack && k.Cdc.IsAfterNamedFeatureActivationHeight(ctx.BlockHeight(), codec.ReplayBurnKey) {
-			return servicerAddr, claim, pc.NewReplayAttackError(pc.ModuleName)
+			return fail(pc.ProofFailureReplayAttack, pc.NewReplayAttackError(pc.ModuleName))
 		}
-		return servicerAddr, claim, pc.NewInvalidMerkleVerifyError(pc.ModuleName)
+		return fail(pc.ProofFailureMerkleMismatch, pc.NewInvalidMerkleVerifyError(pc.ModuleName))
 	}
-	// get the application
+	// get the application; AATs carry no expiration, so a token is only valid if its application is staked at the session height
 	application, found := k.GetAppFromPublicKey(sessionCtx, claim.SessionHeader.ApplicationPubKey)
 	if !found {
-		return servicerAddr, claim, pc.NewAppNotFoundError(pc.ModuleName)
+		return fail(pc.ProofFailureAppNotFound, pc.NewAppNotFoundError(pc.ModuleName))
+	}
+	// the client key that signed the relay must be bound to the session's application by the token, or relays signed by
+	// an arbitrary client key could be proven (ValidateBasic checks the token on its own, and is not run by every caller)
+	if leaf, ok := proof.GetLeaf().(pc.RelayProof); ok && k.Cdc.IsAfterNamedFeatureActivationHeight(ctx.BlockHeight(), codec.ClientKeyBindingKey) {
+		if err := k.verifyLeafClientKey(ctx, leaf, application.GetPublicKey().RawString()); err != nil {
+			return fail(pc.ProofFailureInvalidLeaf, err)
+		}
 	}
 	// validate the proof depending on the type of proof it is
 	er := proof.GetLeaf().Validate(application.GetChains(), int(k.SessionNodeCount(sessionCtx)), claim.SessionHeader.SessionBlockHeight)
 	if er != nil {
+		if recordFailures {
+			pc.GlobalServiceMetric().AddProofValidationFailure(pc.ProofFailureInvalidLeaf)
+		}
 		return nil, claim, er
 	}
 	// return the needed info to the handler
 	return servicerAddr, claim, nil
 }
 
+// "proofVerificationCache" - The outcome of the client key check of every relay proof leaf validated at a height, keyed
+// by the leaf hash with its relay and token signatures (a leaf that differs in either is checked again). The check only
+// depends on the leaf and the application key, so a leaf proven again at the height is not re-verified; it is reset
+// once a leaf of another height is checked, and cleared at the end of every block
+type proofVerificationCache struct {
+	sync.Mutex
+	height  int64
+	results map[string]sdk.Error
+}
+
+// "proofVerificationCaches" - The proof verification caches of a keeper, one per ctx mode: DeliverTx, and CheckTx (which
+// simulate runs in too), so the mempool never fills the cache the block is executed with
+type proofVerificationCaches struct {
+	deliver proofVerificationCache
+	check   proofVerificationCache
+}
+
+// "verificationCache" - Returns the proof verification cache of the ctx mode
+func (k Keeper) verificationCache(ctx sdk.Ctx) *proofVerificationCache {
+	if ctx.IsCheckTx() {
+		return &k.verifyCaches.check
+	}
+	return &k.verifyCaches.deliver
+}
+
+// "verifyLeafClientKey" - Returns leaf.ValidateClientKey(appPubKey), from the verification cache of the ctx mode if the
+// leaf was already checked at this height
+func (k Keeper) verifyLeafClientKey(ctx sdk.Ctx, leaf pc.RelayProof, appPubKey string) sdk.Error {
+	cache := k.verificationCache(ctx)
+	key := appPubKey + "/" + leaf.HashStringWithSignature() + "/" + leaf.Token.ApplicationSignature
+	cache.Lock()
+	if cache.height != ctx.BlockHeight() || cache.results == nil {
+		cache.height, cache.results = ctx.BlockHeight(), make(map[string]sdk.Error)
+	}
+	err, found := cache.results[key]
+	cache.Unlock()
+	if found {
+		return err
+	}
+	err = leaf.ValidateClientKey(appPubKey)
+	cache.Lock()
+	if cache.height == ctx.BlockHeight() {
+		cache.results[key] = err
+	}
+	cache.Unlock()
+	return err
+}
+
+// "ClearProofVerificationCache" - Clears the proof verification caches of every ctx mode, so they only ever hold the
+// leafs of a single block
+func (k Keeper) ClearProofVerificationCache() {
+	for _, cache := range []*proofVerificationCache{&k.verifyCaches.deliver, &k.verifyCaches.check} {
+		cache.Lock()
+		cache.results = nil
+		cache.Unlock()
+	}
+}
+
+// "GetRequiredProofArtifacts" - Returns the merkle proof and the leaf at the required index of the session's evidence in the
+// store, checking both are present; the merkle proof carries the leaf's whole branch, so there is no cousin to look up
+func (k Keeper) GetRequiredProofArtifacts(header pc.SessionHeader, evidenceType pc.EvidenceType, index, maxRelays int64, evidenceStore *pc.CacheStorage) (pc.MerkleProof, pc.Proof, error) {
+	evidence, err := pc.GetEvidence(header, evidenceType, sdk.ZeroInt(), evidenceStore)
+	if err != nil {
+		return pc.MerkleProof{}, nil, err
+	}
+	return requiredProofArtifacts(evidence, index, maxRelays)
+}
+
+// "requiredProofArtifacts" - Returns the merkle proof and the leaf at the index of the evidence (see GetRequiredProofArtifacts)
+func requiredProofArtifacts(evidence pc.Evidence, index, maxRelays int64) (pc.MerkleProof, pc.Proof, error) {
+	mProof, leaf, err := evidence.GenerateMerkleProofForIndex(evidence.SessionBlockHeight, int(index), maxRelays)
+	if err != nil {
+		return pc.MerkleProof{}, nil, err
+	}
+	if leaf == nil {
+		return pc.MerkleProof{}, nil, fmt.Errorf("the leaf at index %d is missing from the evidence", index)
+	}
+	return mProof, leaf, nil
+}
+
+// "invalidProofError" - Returns the specific error of an invalid proof once the PERRC feature is active, the generic
+// invalid proofs error before (the error code is part of the tx result, so changing it must be coordinated)
+func (k Keeper) invalidProofError(ctx sdk.Ctx, err sdk.Error) sdk.Error {
+	if k.Cdc.IsAfterNamedFeatureActivationHeight(ctx.BlockHeight(), codec.ProofErrorCodesKey) {
+		return err
+	}
+	return pc.NewInvalidProofsError(pc.ModuleName)
+}
+
+// "ValidateProofCount" - Errors once the address has MaxProofsPerAddressPerBlock proofs counted this block (0 is unlimited)
+func (k Keeper) ValidateProofCount(ctx sdk.Ctx, address sdk.Address) sdk.Error {
+	maxProofs := k.MaxProofsPerAddressPerBlock(ctx)
+	if maxProofs > 0 && k.getProofCount(ctx, address) >= maxProofs {
+		return pc.NewProofLimitExceededError(pc.ModuleName, maxProofs)
+	}
+	return nil
+}
+
+// "IncrementProofCount" - Counts a proof of the address this block; the count is held in the transient store, so it is
+// reset every block and never part of the app hash
+func (k Keeper) IncrementProofCount(ctx sdk.Ctx, address sdk.Address) {
+	if k.MaxProofsPerAddressPerBlock(ctx) <= 0 {
+		return
+	}
+	bz := make([]byte, 8)
+	binary.BigEndian.PutUint64(bz, uint64(k.getProofCount(ctx, address)+1))
+	_ = ctx.KVStore(k.tStoreKey).Set(pc.KeyForProofCount(address), bz)
+}
+
+// "getProofCount" - Returns the number of proofs of the address counted this block
+func (k Keeper) getProofCount(ctx sdk.Ctx, address sdk.Address) int64 {
+	bz, _ := ctx.KVStore(k.tStoreKey).Get(pc.KeyForProofCount(address))
+	if bz == nil {
+		return 0
+	}
+	return int64(binary.BigEndian.Uint64(bz))
+}
+
 func (k Keeper) ExecuteProof(ctx sdk.Ctx, proof pc.MsgProof, claim pc.MsgClaim) (tokens sdk.BigInt, err sdk.Error) {
 	// convert to value for switch consistency
 	l := proof.GetLeaf()
@@ -177,11 +530,28 @@ func (k Keeper) ExecuteProof(ctx sdk.Ctx, proof pc.MsgProof, claim pc.MsgClaim)
 	switch l.(type) {
 	case pc.RelayProof:
 		ctx.Logger().Info(fmt.Sprintf("reward coins to %s, for %d relays", claim.FromAddress.String(), claim.TotalProofs))
-		tokens = k.AwardCoinsForRelays(ctx, claim.TotalProofs, claim.FromAddress)
+		tokens = k.AwardCoinsForChainRelays(ctx, claim.SessionHeader.Chain, claim.TotalProofs, claim.FromAddress)
 		err := k.DeleteClaim(ctx, claim.FromAddress, claim.SessionHeader, pc.RelayEvidence)
 		if err != nil {
 			return tokens, sdk.ErrInternal(err.Error())
 		}
+		if k.Cdc.IsAfterNamedFeatureActivationHeight(ctx.BlockHeight(), codec.RotatingProofIndexKey) {
+			k.incrementAppProofRotation(ctx, claim.SessionHeader.ApplicationPubKey)
+		}
+		if k.Cdc.IsAfterNamedFeatureActivationHeight(ctx.BlockHeight(), codec.VerifiedRelaysKey) {
+			k.addVerifiedRelays(ctx, claim.FromAddress, claim.TotalProofs)
+		}
+		if k.claimVerifiedHook != nil {
+			k.claimVerifiedHook(ctx, claim)
+		}
+		ctx.EventManager().EmitEvent(sdk.NewEvent(
+			pc.EventTypeProofVerified,
+			sdk.NewAttribute(pc.AttributeKeyServicer, claim.FromAddress.String()),
+			sdk.NewAttribute(pc.AttributeKeyChain, claim.SessionHeader.Chain),
+			sdk.NewAttribute(pc.AttributeKeySessionHeight, strconv.FormatInt(claim.SessionHeader.SessionBlockHeight, 10)),
+			sdk.NewAttribute(pc.AttributeKeyTotalRelays, strconv.FormatInt(claim.TotalProofs, 10)),
+			sdk.NewAttribute(pc.AttributeKeyProofIndex, strconv.FormatInt(proof.MerkleProof.TargetIndex, 10)),
+		))
 	case pc.ChallengeProofInvalidData:
 		ctx.Logger().Info(fmt.Sprintf("burning coins from %s, for %d valid challenges", claim.FromAddress.String(), claim.TotalProofs))
 		proof, ok := proof.GetLeaf().(pc.ChallengeProofInvalidData)
@@ -208,31 +578,232 @@ func (k Keeper) ExecuteProof(ctx sdk.Ctx, proof pc.MsgProof, claim pc.MsgClaim)
 type pseudorandomGenerator struct {
 	BlockHash string
 	Header    string
+	Rotation  int64 `json:",omitempty"` // omitted when zero, so the legacy seed is unchanged
 }
 
 // generates the required pseudorandom index for the zero knowledge proof
+// "GetRequiredProof" - Returns the leaf index and merkle proof level count the claim must be proven with
+func (k Keeper) GetRequiredProof(ctx sdk.Ctx, address sdk.Address, header pc.SessionHeader, evidenceType pc.EvidenceType) (requiredProof pc.RequiredProof, err sdk.Error) {
+	// get the claim
+	claim, found := k.GetClaim(ctx, address, header, evidenceType)
+	if !found {
+		return requiredProof, pc.NewClaimNotFoundError(pc.ModuleName)
+	}
+	// get the session context
+	_, sessionCtx, err := k.GetSessionForClaim(ctx, claim)
+	if err != nil {
+		return requiredProof, err
+	}
+	// generate the pseudorandom index (errors if the proof context height hasn't been reached yet)
+	index, er := k.requiredProofIndex(ctx, claim, sessionCtx)
+	if er != nil {
+		return requiredProof, sdk.ErrInternal(er.Error())
+	}
+	return pc.RequiredProof{
+		Index:      index,
+		LevelCount: int(math.Ceil(math.Log2(float64(claim.TotalProofs)))),
+	}, nil
+}
+
 func (k Keeper) getPseudorandomIndex(ctx sdk.Ctx, totalRelays int64, header pc.SessionHeader, sessionCtx sdk.Ctx) (int64, error) {
 	// get the context for the proof (the proof context is X sessions after the session began)
-	proofHeight := header.SessionBlockHeight + k.ClaimSubmissionWindow(sessionCtx)*k.BlocksPerSession(sessionCtx) // next session block hash
+	proofHeight, err := k.proofContextHeight(sessionCtx, header.SessionBlockHeight) // next session block hash
+	if err != nil {
+		return 0, err
+	}
 	// get the pseudorandomGenerator json bytes
 	blockHashBz, err := ctx.GetPrevBlockHash(proofHeight)
 	if err != nil {
 		return 0, err
 	}
+	// an empty hash (a missing or pruned header) would seed every session of the height with the same degenerate value
+	// (gated, like the selection below, by the proof height)
+	if k.Cdc.IsAfterNamedFeatureActivationHeight(proofHeight, codec.EmptyBlockHashKey) && len(bytes.Trim(blockHashBz, "\x00")) == 0 {
+		return 0, fmt.Errorf("the block hash at the proof height %d is unavailable", proofHeight)
+	}
+	// the selection is chosen by the proof height, so the node building the proof and the validators agree on it
+	uniform := k.Cdc.IsAfterNamedFeatureActivationHeight(proofHeight, codec.UniformProofIndexKey)
+	// the rotation is read at the session height, so proofs verified after the session began never change the index
+	var rotation int64
+	if k.Cdc.IsAfterNamedFeatureActivationHeight(proofHeight, codec.RotatingProofIndexKey) {
+		rotation = k.GetAppProofRotation(sessionCtx, header.ApplicationPubKey)
+	}
+	return pseudorandomIndex(blockHashBz, header, totalRelays, uniform, rotation)
+}
+
+// "GetMerklePath" - Returns the merkle path of the leaf at the index of the tree the stored claim committed to, so a
+// client can verify the leaf's inclusion against the claim's root; the leafs are held in the node's evidence
+func (k Keeper) GetMerklePath(ctx sdk.Ctx, address sdk.Address, header pc.SessionHeader, evidenceType pc.EvidenceType, index int64, evidenceStore *pc.CacheStorage) (path pc.MerklePath, err sdk.Error) {
+	claim, found := k.GetClaim(ctx, address, header, evidenceType)
+	if !found {
+		return path, pc.NewClaimNotFoundError(pc.ModuleName)
+	}
+	evidence, er := pc.GetEvidence(header, evidenceType, sdk.ZeroInt(), evidenceStore)
+	if er != nil {
+		return path, sdk.ErrInternal(er.Error())
+	}
+	// the tree is built from the claimed (capped) number of proofs
+	mProof, _, err := evidence.GenerateMerkleProofForIndex(header.SessionBlockHeight, int(index), claim.TotalProofs)
+	if err != nil {
+		return path, err
+	}
+	return pc.MerklePath{
+		Index:      mProof.TargetIndex,
+		Target:     mProof.Target,
+		Path:       mProof.HashRanges,
+		Root:       claim.MerkleRoot,
+		SortedPair: pc.IsSortedPairMerkle(header.SessionBlockHeight),
+	}, nil
+}
+
+// "requiredProofIndex" - Returns the leaf index the claim must be proven with: after PPIDX is activated, the index
+// persisted for the claim once it matured (see persistProofIndex), so the prover and the validators read the same value;
+// otherwise (or if it was not persisted yet) a fresh computation
+func (k Keeper) requiredProofIndex(ctx sdk.Ctx, claim pc.MsgClaim, sessionCtx sdk.Ctx) (int64, error) {
+	if k.Cdc.IsAfterNamedFeatureActivationHeight(ctx.BlockHeight(), codec.PersistedProofIndexKey) {
+		if index, found := k.GetPersistedProofIndex(ctx, claim); found {
+			return index, nil
+		}
+	}
+	return k.getPseudorandomIndex(ctx, claim.TotalProofs, claim.SessionHeader, sessionCtx)
+}
+
+// "GetPersistedProofIndex" - Returns the leaf index persisted for the claim, if it matured after PPIDX was activated
+func (k Keeper) GetPersistedProofIndex(ctx sdk.Ctx, claim pc.MsgClaim) (index int64, found bool) {
+	claimKey, err := pc.KeyForClaim(ctx, claim.FromAddress, claim.SessionHeader, claim.EvidenceType)
+	if err != nil {
+		return 0, false
+	}
+	store := ctx.KVStore(k.storeKey)
+	bz, _ := store.Get(pc.KeyForProofIndex(claimKey))
+	if bz == nil {
+		return 0, false
+	}
+	return int64(binary.BigEndian.Uint64(bz)), true
+}
+
+// "persistProofIndex" - Computes and stores the leaf index of a stored claim that is mature (its proof context block
+// hash is known) and has none yet; the index is computed exactly as ValidateProof would, at the proof context height
+func (k Keeper) persistProofIndex(ctx sdk.Ctx, store sdk.KVStore, claimKey []byte, claim pc.MsgClaim) {
+	if !k.ClaimIsMature(ctx, claim.SessionHeader.SessionBlockHeight) {
+		return
+	}
+	indexKey := pc.KeyForProofIndex(claimKey)
+	if found, _ := store.Has(indexKey); found {
+		return
+	}
+	_, sessionCtx, err := k.GetSessionForClaim(ctx, claim)
+	if err != nil {
+		return
+	}
+	index, er := k.getPseudorandomIndex(ctx, claim.TotalProofs, claim.SessionHeader, sessionCtx)
+	if er != nil {
+		ctx.Logger().Error(fmt.Sprintf("could not persist the proof index of the claim of %s at session height %d: %s", claim.FromAddress.String(), claim.SessionHeader.SessionBlockHeight, er.Error()))
+		return
+	}
+	bz := make([]byte, 8)
+	binary.BigEndian.PutUint64(bz, uint64(index))
+	_ = store.Set(indexKey, bz)
+}
+
+// "GetAppProofRotation" - Returns the number of relay proofs verified for the application since RPIDX was activated;
+// it is mixed into the proof index seed, so successive sessions of a long-running application are sampled anew
+func (k Keeper) GetAppProofRotation(ctx sdk.Ctx, appPubKey string) int64 {
+	store := ctx.KVStore(k.storeKey)
+	bz, _ := store.Get(pc.KeyForAppProofRotation(appPubKey))
+	if bz == nil {
+		return 0
+	}
+	return int64(binary.BigEndian.Uint64(bz))
+}
+
+// "incrementAppProofRotation" - Counts a relay proof verified for the application (see GetAppProofRotation)
+func (k Keeper) incrementAppProofRotation(ctx sdk.Ctx, appPubKey string) {
+	store := ctx.KVStore(k.storeKey)
+	bz := make([]byte, 8)
+	binary.BigEndian.PutUint64(bz, uint64(k.GetAppProofRotation(ctx, appPubKey)+1))
+	_ = store.Set(pc.KeyForAppProofRotation(appPubKey), bz)
+}
+
+// "GetVerifiedRelays" - Returns the total relays verified for the servicer since VRELS was activated; verified claims
+// are deleted once rewarded, so this running total is the only record of them in the state
+func (k Keeper) GetVerifiedRelays(ctx sdk.Ctx, addr sdk.Address) int64 {
+	store := ctx.KVStore(k.storeKey)
+	bz, _ := store.Get(pc.KeyForVerifiedRelays(addr))
+	if bz == nil {
+		return 0
+	}
+	return int64(binary.BigEndian.Uint64(bz))
+}
+
+// "GetVerifiedRelaysAsOf" - Returns the total relays verified for the servicer up to (and including) the height, read
+// from the state at that height; zero if the height is not in the state (pruned or not yet reached)
+func (k Keeper) GetVerifiedRelaysAsOf(ctx sdk.Ctx, addr sdk.Address, height int64) int64 {
+	if height >= ctx.BlockHeight() {
+		return k.GetVerifiedRelays(ctx, addr)
+	}
+	heightCtx, err := ctx.PrevCtx(height)
+	if err != nil {
+		ctx.Logger().Error(fmt.Sprintf("unable to get the context at height %d for the verified relays of %s: %s", height, addr.String(), err.Error()))
+		return 0
+	}
+	return k.GetVerifiedRelays(heightCtx, addr)
+}
+
+// "addVerifiedRelays" - Adds the relays of a verified claim to the servicer's total (see GetVerifiedRelays)
+func (k Keeper) addVerifiedRelays(ctx sdk.Ctx, addr sdk.Address, relays int64) {
+	total := saturatingAdd(k.GetVerifiedRelays(ctx, addr), relays)
+	store := ctx.KVStore(k.storeKey)
+	bz := make([]byte, 8)
+	binary.BigEndian.PutUint64(bz, uint64(total))
+	_ = store.Set(pc.KeyForVerifiedRelays(addr), bz)
+}
+
+// "pseudorandomIndex" - Returns the leaf index selected for the session by the block hash; with uniform, every index
+// is equally likely (see UniformPseudorandomSelection), otherwise the legacy modulo selection is used. A non-zero
+// rotation is mixed into the seed (see GetAppProofRotation)
+func pseudorandomIndex(blockHash []byte, header pc.SessionHeader, totalRelays int64, uniform bool, rotation int64) (int64, error) {
 	headerHash := header.HashString()
-	pseudoGenerator := pseudorandomGenerator{hex.EncodeToString(blockHashBz), headerHash}
+	pseudoGenerator := pseudorandomGenerator{hex.EncodeToString(blockHash), headerHash, rotation}
 	r, err := json.Marshal(pseudoGenerator)
 	if err != nil {
 		return 0, err
 	}
+	if uniform {
+		return pc.UniformPseudorandomSelection(totalRelays, pc.Hash(r)), nil
+	}
 	return pc.PseudorandomSelection(sdk.NewInt(totalRelays), pc.Hash(r)).Int64(), nil
 }
 
+// "SimulateIndexDistribution" - Returns how many times each leaf index of the session is selected over a number of
+// candidate block hashes (the hash of each sample number), for analysing the fairness of the proof selection
+func SimulateIndexDistribution(header pc.SessionHeader, totalRelays int64, samples int, uniform bool) ([]int64, error) {
+	if totalRelays <= 0 {
+		return nil, fmt.Errorf("the total relays must be positive: %d", totalRelays)
+	}
+	histogram := make([]int64, totalRelays)
+	sample := make([]byte, 8)
+	for i := 0; i < samples; i++ {
+		binary.BigEndian.PutUint64(sample, uint64(i))
+		index, err := pseudorandomIndex(pc.Hash(sample), header, totalRelays, uniform, 0)
+		if err != nil {
+			return nil, err
+		}
+		histogram[index]++
+	}
+	return histogram, nil
+}
+
 func (k Keeper) HandleReplayAttack(ctx sdk.Ctx, address sdk.Address, numberOfChallenges sdk.BigInt) {
 	ctx.Logger().Error(fmt.Sprintf("Replay Attack Detected: By %s, for %v proofs", address.String(), numberOfChallenges))
 	k.posKeeper.BurnForChallenge(ctx, numberOfChallenges.Mul(sdk.NewInt(k.ReplayAttackBurnMultiplier(ctx))), address)
 }
 
+// "sessionLogKeyVals" - Returns the structured logging key values identifying a session in the auto-tx flow
+func sessionLogKeyVals(header pc.SessionHeader, totalRelays int64) []interface{} {
+	return []interface{}{"chain", header.Chain, "session_height", header.SessionBlockHeight, "relays", totalRelays}
+}
+
 func newTxBuilderAndCliCtx(ctx sdk.Ctx, msg sdk.ProtoMsg, n client.Client, key crypto.PrivateKey, k Keeper) (txBuilder auth.TxBuilder, cliCtx util.CLIContext, err error) {
 	// get the from address from the pkf
 	fromAddr := sdk.Address(key.PublicKey().Address())
@@ -240,17 +811,29 @@ func newTxBuilderAndCliCtx(ctx sdk.Ctx, msg sdk.ProtoMsg, n client.Client, key c
 	cliCtx = util.NewCLIContext(n, fromAddr, "").WithCodec(k.Cdc).WithHeight(ctx.BlockHeight())
 
 	cliCtx.PrivateKey = key
-	// broadcast synchronously
-	cliCtx.BroadcastMode = util.BroadcastSync
+	// broadcast with the configured mode (sync if unset, e.g. in a config file written by an older version)
+	mode := pc.GlobalPocketConfig.AutoTxBroadcastMode
+	if mode == "" {
+		mode = sdk.DefaultAutoTxBroadcastMode
+	}
+	cliCtx.BroadcastMode, err = util.ParseBroadcastType(mode)
+	if err != nil {
+		return txBuilder, cliCtx, fmt.Errorf("invalid auto_tx_broadcast_mode: %s", err.Error())
+	}
 	// get the account to ensure balance
 	// retrieve the account for a balance check (and ensure it exists)
 	account := k.authKeeper.GetAccount(ctx, fromAddr)
 	if account == nil {
 		return txBuilder, cliCtx, fmt.Errorf("unable to locate an account at address: %s", fromAddr)
 	}
+	// a missing denom would build an unpayable tx; the denom is read once so the balance check and the fee agree
+	denom := k.posKeeper.StakeDenom(ctx)
+	if denom == "" {
+		return txBuilder, cliCtx, fmt.Errorf("unable to build the auto %s transaction: the stake denom is empty", msg.Type())
+	}
 	// check the fee amount
 	fee := k.authKeeper.GetFee(ctx, msg)
-	if account.GetCoins().AmountOf(k.posKeeper.StakeDenom(ctx)).LT(fee) {
+	if account.GetCoins().AmountOf(denom).LT(fee) {
 		return txBuilder, cliCtx, fmt.Errorf("insufficient funds for the auto %s transaction: the fee needed is %v ", msg.Type(), fee)
 	}
 	// ensure that the tx builder has the correct tx encoder, chainID, fee
@@ -259,7 +842,7 @@ func newTxBuilderAndCliCtx(ctx sdk.Ctx, msg sdk.ProtoMsg, n client.Client, key c
 		auth.DefaultTxDecoder(k.Cdc),
 		ctx.ChainID(),
 		"",
-		sdk.NewCoins(sdk.NewCoin(k.posKeeper.StakeDenom(ctx), fee)),
+		sdk.NewCoins(sdk.NewCoin(denom, fee)),
 	)
 	return
 }
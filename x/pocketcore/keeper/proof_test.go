@@ -2,15 +2,28 @@ package keeper
 
 import (
 	"encoding/binary"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
 	"math/rand"
+	"strings"
 	"testing"
 
 	"time"
 
+	"github.com/pokt-network/pocket-core/codec"
+	"github.com/pokt-network/pocket-core/crypto"
+	storeTypes "github.com/pokt-network/pocket-core/store/types"
 	sdk "github.com/pokt-network/pocket-core/types"
 	appsTypes "github.com/pokt-network/pocket-core/x/apps/types"
+	"github.com/pokt-network/pocket-core/x/auth"
+	"github.com/pokt-network/pocket-core/x/auth/util"
+	nodesKeeper "github.com/pokt-network/pocket-core/x/nodes/keeper"
 	"github.com/pokt-network/pocket-core/x/pocketcore/types"
+	stdPrometheus "github.com/prometheus/client_golang/prometheus"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	abci "github.com/tendermint/tendermint/abci/types"
 )
 
 func TestKeeper_ValidateProof(t *testing.T) { // happy path only todo
@@ -49,14 +62,15 @@ func TestKeeper_ValidateProof(t *testing.T) { // happy path only todo
 	assert.Nil(t, er)
 	merkleProofs, _ := evidence.GenerateMerkleProof(0, int(neededLeafIndex), maxRelays)
 	// get leaf and cousin node
-	leafNode := types.GetProof(header, types.RelayEvidence, neededLeafIndex, types.GlobalEvidenceCache)
+	leafNode, found := types.GetProof(header, types.RelayEvidence, neededLeafIndex, types.GlobalEvidenceCache)
+	assert.True(t, found)
 	// create proof message
 	proofMsg := types.MsgProof{
 		MerkleProof:  merkleProofs,
 		Leaf:         leafNode,
 		EvidenceType: types.RelayEvidence,
 	}
-	err = keeper.SetClaim(mockCtx, claimMsg)
+	_, err = keeper.SetClaim(mockCtx, claimMsg)
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -67,6 +81,378 @@ func TestKeeper_ValidateProof(t *testing.T) { // happy path only todo
 	}
 }
 
+func TestKeeper_ValidateProofFailureMetrics(t *testing.T) {
+	maxRelays := int64(5)
+	ctx, _, _, _, keeper, keys, _ := createTestInput(t, false)
+	types.ClearEvidence(types.GlobalEvidenceCache)
+	npk, header, _ := simulateRelays(t, keeper, &ctx, 8)
+	evidence, err := types.GetEvidence(header, types.RelayEvidence, sdk.NewInt(1000), types.GlobalEvidenceCache)
+	if err != nil {
+		t.Fatalf("Set evidence not found")
+	}
+	root := evidence.GenerateMerkleRoot(0, maxRelays, types.GlobalEvidenceCache)
+	claimMsg := types.MsgClaim{
+		SessionHeader: header,
+		MerkleRoot:    root,
+		TotalProofs:   maxRelays,
+		FromAddress:   sdk.Address(npk.Address()),
+		EvidenceType:  types.RelayEvidence,
+	}
+	mockCtx := &Ctx{}
+	mockCtx.On("KVStore", keeper.storeKey).Return(ctx.KVStore(keeper.storeKey))
+	mockCtx.On("KVStore", keys[sdk.ParamsKey.Name()]).Return(ctx.KVStore(keys[sdk.ParamsKey.Name()]))
+	mockCtx.On("KVStore", keys[appsTypes.StoreKey]).Return(ctx.KVStore(keys[appsTypes.StoreKey]))
+	mockCtx.On("Logger").Return(ctx.Logger())
+	mockCtx.On("BlockHeight").Return(ctx.BlockHeight())
+	mockCtx.On("PrevCtx", header.SessionBlockHeight).Return(ctx, nil)
+	mockCtx.On("GetPrevBlockHash", int64(76)).Return(ctx.BlockHeader().LastBlockId.Hash, nil)
+	neededLeafIndex, er := keeper.getPseudorandomIndex(mockCtx, maxRelays, header, mockCtx)
+	assert.Nil(t, er)
+	merkleProof, _ := evidence.GenerateMerkleProof(0, int(neededLeafIndex), maxRelays)
+	leaf, found := types.GetProof(header, types.RelayEvidence, neededLeafIndex, types.GlobalEvidenceCache)
+	assert.True(t, found)
+	validProof := types.MsgProof{
+		MerkleProof:  merkleProof,
+		Leaf:         leaf,
+		EvidenceType: types.RelayEvidence,
+	}
+	// copies the hash ranges so a test case never touches the valid proof
+	withHashRanges := func(f func(hr []types.HashRange)) types.MsgProof {
+		p := validProof
+		p.MerkleProof.HashRanges = make([]types.HashRange, len(validProof.MerkleProof.HashRanges))
+		copy(p.MerkleProof.HashRanges, validProof.MerkleProof.HashRanges)
+		f(p.MerkleProof.HashRanges)
+		return p
+	}
+	// no claim is stored yet
+	assertProofFailure(t, types.ProofFailureClaimNotFound, func() {
+		_, _, err := keeper.ValidateProof(mockCtx, validProof)
+		assert.NotNil(t, err)
+	})
+	_, err = keeper.SetClaim(mockCtx, claimMsg)
+	assert.Nil(t, err)
+	wrongLevelCount := validProof
+	wrongLevelCount.MerkleProof.HashRanges = validProof.MerkleProof.HashRanges[1:]
+	wrongIndex := validProof
+	wrongIndex.MerkleProof.TargetIndex = (validProof.MerkleProof.TargetIndex + 1) % maxRelays
+	outOfRange := validProof
+	outOfRange.MerkleProof.TargetIndex = maxRelays
+	lastIndex := validProof
+	lastIndex.MerkleProof.TargetIndex = maxRelays - 1
+	wrongRange := withHashRanges(func(hr []types.HashRange) {
+		for i := range hr {
+			hr[i].Range.Upper++
+		}
+	})
+	wrongRange.MerkleProof.Target.Range.Upper++
+	wrongHash := withHashRanges(func(hr []types.HashRange) {
+		hr[0].Hash = types.Hash([]byte("not the sibling"))
+	})
+	for _, tc := range []struct {
+		reason string
+		proof  types.MsgProof
+	}{
+		{types.ProofFailureLevelCount, wrongLevelCount},
+		{types.ProofFailureIndexMismatch, wrongIndex},
+		{types.ProofFailureIndexOutOfRange, outOfRange},
+		{types.ProofFailureRootRange, wrongRange},
+		{types.ProofFailureMerkleMismatch, wrongHash},
+	} {
+		assertProofFailure(t, tc.reason, func() {
+			_, _, err := keeper.ValidateProof(mockCtx, tc.proof)
+			assert.NotNil(t, err, tc.reason)
+		})
+	}
+	// the index and level count failures have their own error codes once PERRC is active
+	for _, tc := range []struct {
+		proof    types.MsgProof
+		specific sdk.CodeType
+	}{
+		{wrongLevelCount, types.CodeInvalidProofLevelCountError},
+		{wrongIndex, types.CodeInvalidProofIndexError},
+		{outOfRange, types.CodeMerkleIndexOutOfRangeError},
+	} {
+		_, _, err := keeper.ValidateProof(mockCtx, tc.proof)
+		assert.Equal(t, sdk.CodeType(types.CodeInvalidProofsError), err.Code())
+		codec.UpgradeFeatureMap[codec.ProofErrorCodesKey] = 1
+		_, _, err = keeper.ValidateProof(mockCtx, tc.proof)
+		delete(codec.UpgradeFeatureMap, codec.ProofErrorCodesKey)
+		assert.Equal(t, tc.specific, err.Code())
+		assert.Equal(t, sdk.CodespaceType(types.ModuleName), err.Codespace())
+	}
+	// the last leaf is in range (it is only rejected if it is not the required index)
+	outOfRangeFailures := proofFailureCount(t, types.ProofFailureIndexOutOfRange)
+	_, _, err = keeper.ValidateProof(mockCtx, lastIndex)
+	assert.Equal(t, outOfRangeFailures, proofFailureCount(t, types.ProofFailureIndexOutOfRange))
+	if neededLeafIndex != maxRelays-1 {
+		assert.NotNil(t, err)
+	}
+	// a valid proof records no failure
+	reasons := []string{types.ProofFailureClaimNotFound, types.ProofFailureLevelCount, types.ProofFailureIndexMismatch,
+		types.ProofFailureIndexOutOfRange, types.ProofFailureRootRange, types.ProofFailureMerkleMismatch}
+	before := make([]float64, len(reasons))
+	for i, reason := range reasons {
+		before[i] = proofFailureCount(t, reason)
+	}
+	_, _, err = keeper.ValidateProof(mockCtx, validProof)
+	assert.Nil(t, err)
+	for i, reason := range reasons {
+		assert.Equal(t, before[i], proofFailureCount(t, reason), reason)
+	}
+}
+
+func TestKeeper_ValidateProofClaimHeaderMismatch(t *testing.T) {
+	ctx, _, _, _, keeper, _, _ := createTestInput(t, false)
+	servicer := getRandomPubKey()
+	leaf := types.RelayProof{
+		ServicerPubKey:     servicer.RawString(),
+		SessionBlockHeight: 1,
+		Blockchain:         "0001",
+		Token:              types.AAT{ApplicationPublicKey: getRandomPubKey().RawString()},
+	}
+	proof := types.MsgProof{
+		MerkleProof:  types.MerkleProof{HashRanges: []types.HashRange{{Range: types.Range{Upper: 1}}}},
+		Leaf:         leaf,
+		EvidenceType: types.RelayEvidence,
+	}
+	// store the claim of another session under the key of the leaf's session
+	claim := createTestClaim(sdk.Address(servicer.Address()), "0001", 1, 2)
+	key, err := types.KeyForClaim(ctx, claim.FromAddress, leaf.SessionHeader(), types.RelayEvidence)
+	assert.Nil(t, err)
+	bz, err := keeper.Cdc.MarshalBinaryBare(&claim, ctx.BlockHeight())
+	assert.Nil(t, err)
+	assert.Nil(t, ctx.KVStore(keeper.storeKey).Set(key, bz))
+	assertProofFailure(t, types.ProofFailureHeaderMismatch, func() {
+		_, _, err := keeper.ValidateProof(ctx, proof)
+		assert.NotNil(t, err)
+		assert.Equal(t, sdk.CodeType(types.CodeProofClaimHeaderMismatchError), err.Code())
+	})
+}
+
+func TestKeeper_ValidateProofChainMismatch(t *testing.T) {
+	ctx, _, _, _, keeper, _, _ := createTestInput(t, false)
+	servicer := getRandomPubKey()
+	leaf := types.RelayProof{
+		ServicerPubKey:     servicer.RawString(),
+		SessionBlockHeight: 1,
+		Blockchain:         "0001",
+		Token:              types.AAT{ApplicationPublicKey: getRandomPubKey().RawString()},
+	}
+	proof := types.MsgProof{
+		MerkleProof:  types.MerkleProof{HashRanges: []types.HashRange{{Range: types.Range{Upper: 1}}}},
+		Leaf:         leaf,
+		EvidenceType: types.RelayEvidence,
+	}
+	// the claim of the same application and session on another chain
+	claim := createTestClaim(sdk.Address(servicer.Address()), "0002", 1, 2)
+	claim.SessionHeader.ApplicationPubKey = leaf.Token.ApplicationPublicKey
+	keeper.SetClaims(ctx, []types.MsgClaim{claim})
+	_, _, err := keeper.ValidateProof(ctx, proof)
+	assert.NotNil(t, err)
+	assert.Equal(t, sdk.CodeType(types.CodeClaimNotFoundError), err.Code())
+	// and even if it is stored under the key of the leaf's session
+	key, er := types.KeyForClaim(ctx, claim.FromAddress, leaf.SessionHeader(), types.RelayEvidence)
+	assert.Nil(t, er)
+	bz, er := keeper.Cdc.MarshalBinaryBare(&claim, ctx.BlockHeight())
+	assert.Nil(t, er)
+	assert.Nil(t, ctx.KVStore(keeper.storeKey).Set(key, bz))
+	_, _, err = keeper.ValidateProof(ctx, proof)
+	assert.NotNil(t, err)
+	assert.Equal(t, sdk.CodeType(types.CodeProofClaimHeaderMismatchError), err.Code())
+}
+
+func TestKeeper_ValidateProofMixedCaseHeader(t *testing.T) {
+	ctx, _, _, _, keeper, _, _ := createTestInput(t, false)
+	servicer := getRandomPubKey()
+	leaf := types.RelayProof{
+		ServicerPubKey:     servicer.RawString(),
+		SessionBlockHeight: 1,
+		Blockchain:         "0001",
+		Token:              types.AAT{ApplicationPublicKey: getRandomPubKey().RawString()},
+	}
+	proof := types.MsgProof{
+		MerkleProof:  types.MerkleProof{HashRanges: []types.HashRange{{Range: types.Range{Upper: 1}}}},
+		Leaf:         leaf,
+		EvidenceType: types.RelayEvidence,
+	}
+	// the claim names the leaf's application in upper case
+	claim := createTestClaim(sdk.Address(servicer.Address()), "0001", 1, 2)
+	claim.SessionHeader.ApplicationPubKey = strings.ToUpper(leaf.Token.ApplicationPublicKey)
+	key, err := types.KeyForClaim(ctx, claim.FromAddress, leaf.SessionHeader(), types.RelayEvidence)
+	assert.Nil(t, err)
+	bz, err := keeper.Cdc.MarshalBinaryBare(&claim, ctx.BlockHeight())
+	assert.Nil(t, err)
+	assert.Nil(t, ctx.KVStore(keeper.storeKey).Set(key, bz))
+	// before the activation height the headers differ
+	_, _, er := keeper.ValidateProof(ctx, proof)
+	assert.NotNil(t, er)
+	assert.Equal(t, sdk.CodeType(types.CodeProofClaimHeaderMismatchError), er.Code())
+	// after it they are the same session, so the proof is checked against the claim
+	codec.UpgradeFeatureMap[codec.CanonicalHeaderKey] = 1
+	t.Cleanup(func() { delete(codec.UpgradeFeatureMap, codec.CanonicalHeaderKey) })
+	_, _, er = keeper.ValidateProof(ctx, proof)
+	assert.NotNil(t, er)
+	assert.NotEqual(t, sdk.CodeType(types.CodeProofClaimHeaderMismatchError), er.Code())
+	assert.NotEqual(t, sdk.CodeType(types.CodeClaimNotFoundError), er.Code())
+}
+
+func TestKeeper_ValidateProofSelfSigned(t *testing.T) {
+	ctx, _, _, _, keeper, _, _ := createTestInput(t, false)
+	servicer := getRandomPubKey()
+	// the relay is signed by a client key the servicer controls: its own
+	leaf := types.RelayProof{
+		ServicerPubKey:     servicer.RawString(),
+		SessionBlockHeight: 1,
+		Blockchain:         "0001",
+		Token:              types.AAT{ApplicationPublicKey: getRandomPubKey().RawString(), ClientPublicKey: strings.ToUpper(servicer.RawString())},
+	}
+	assert.True(t, leaf.IsSelfSigned())
+	proof := types.MsgProof{
+		MerkleProof:  types.MerkleProof{HashRanges: []types.HashRange{{Range: types.Range{Upper: 1}}}},
+		Leaf:         leaf,
+		EvidenceType: types.RelayEvidence,
+	}
+	// by default the proof is only flagged, and validated as usual (it has no claim)
+	_, _, err := keeper.ValidateProof(ctx, proof)
+	assert.NotNil(t, err)
+	assert.Equal(t, sdk.CodeType(types.CodeClaimNotFoundError), err.Code())
+	p := keeper.GetParams(ctx)
+	p.RejectSelfSignedProofs = true
+	keeper.SetParams(ctx, p)
+	_, _, err = keeper.ValidateProof(ctx, proof)
+	assert.NotNil(t, err)
+	assert.Equal(t, sdk.CodeType(types.CodeSelfSignedProofError), err.Code())
+	// a relay signed by another client key is not
+	leaf.Token.ClientPublicKey = getRandomPubKey().RawString()
+	assert.False(t, leaf.IsSelfSigned())
+	proof.Leaf = leaf
+	_, _, err = keeper.ValidateProof(ctx, proof)
+	assert.NotNil(t, err)
+	assert.Equal(t, sdk.CodeType(types.CodeClaimNotFoundError), err.Code())
+}
+
+func TestKeeper_ValidateProofMaxRelaysPerSession(t *testing.T) {
+	ctx, _, _, _, keeper, _, _ := createTestInput(t, false)
+	codec.UpgradeFeatureMap[codec.ProofErrorCodesKey] = 1
+	t.Cleanup(func() { delete(codec.UpgradeFeatureMap, codec.ProofErrorCodesKey) })
+	// additional params are not set at genesis
+	ctx = ctx.WithBlockHeight(10)
+	p := keeper.GetParams(ctx)
+	p.MaxRelaysPerSession = 1000 // at most 10 levels
+	keeper.SetParams(ctx, p)
+	servicer := getRandomPubKey()
+	leaf := types.RelayProof{
+		ServicerPubKey:     servicer.RawString(),
+		SessionBlockHeight: 1,
+		Blockchain:         "0001",
+		Token:              types.AAT{ApplicationPublicKey: getRandomPubKey().RawString()},
+	}
+	proofOfLevels := func(levels int) types.MsgProof {
+		return types.MsgProof{
+			MerkleProof:  types.MerkleProof{HashRanges: make([]types.HashRange, levels)},
+			Leaf:         leaf,
+			EvidenceType: types.RelayEvidence,
+		}
+	}
+	// a proof of more levels than the max relays allow is rejected before its claim is looked up (there is none)
+	assertProofFailure(t, types.ProofFailureLevelCount, func() {
+		_, _, err := keeper.ValidateProof(ctx, proofOfLevels(11))
+		assert.NotNil(t, err)
+		assert.Equal(t, sdk.CodeType(types.CodeInvalidProofLevelCountError), err.Code())
+	})
+	_, _, err := keeper.ValidateProof(ctx, proofOfLevels(10))
+	assert.Equal(t, sdk.CodeType(types.CodeClaimNotFoundError), err.Code())
+	// a claim of an oversized relay count is rejected before the proof's levels are compared to it
+	claim := createTestClaim(sdk.Address(servicer.Address()), "0001", 1, 1<<40)
+	claim.SessionHeader = leaf.SessionHeader()
+	_, er := keeper.SetClaim(ctx, claim)
+	assert.Nil(t, er)
+	assertProofFailure(t, types.ProofFailureLevelCount, func() {
+		_, _, err := keeper.ValidateProof(ctx, proofOfLevels(10))
+		assert.NotNil(t, err)
+		assert.Equal(t, sdk.CodeType(types.CodeClaimExceedsMaxRelaysError), err.Code())
+	})
+	// without the param, the claim's level count is required instead
+	p.MaxRelaysPerSession = 0
+	keeper.SetParams(ctx, p)
+	_, _, err = keeper.ValidateProof(ctx, proofOfLevels(10))
+	assert.Equal(t, sdk.CodeType(types.CodeInvalidProofLevelCountError), err.Code())
+}
+
+func TestKeeper_ValidateProofMalformed(t *testing.T) {
+	ctx, _, _, _, keeper, _, _ := createTestInput(t, false)
+	leaf := types.RelayProof{ServicerPubKey: getRandomPubKey().RawString(), SessionBlockHeight: 1, Blockchain: "0001"}
+	for _, proof := range []types.MsgProof{
+		{Leaf: leaf, EvidenceType: types.RelayEvidence},
+		{MerkleProof: types.MerkleProof{HashRanges: []types.HashRange{{Range: types.Range{Upper: 1}}}}, EvidenceType: types.RelayEvidence},
+	} {
+		assertProofFailure(t, types.ProofFailureMalformed, func() {
+			_, _, err := keeper.ValidateProof(ctx, proof)
+			assert.NotNil(t, err)
+			assert.Equal(t, sdk.CodeType(types.CodeMalformedProofError), err.Code())
+		})
+	}
+}
+
+func assertProofFailure(t *testing.T, reason string, f func()) {
+	before := proofFailureCount(t, reason)
+	f()
+	assert.Equal(t, float64(1), proofFailureCount(t, reason)-before, reason)
+}
+
+func proofFailureCount(t *testing.T, reason string) float64 {
+	families, err := stdPrometheus.DefaultGatherer.Gather()
+	if err != nil {
+		t.Fatal(err)
+	}
+	name := types.ModuleName + "_" + types.ServiceMetricsNamespace + "_" + types.ProofFailureCountName
+	for _, family := range families {
+		if family.GetName() != name {
+			continue
+		}
+		for _, m := range family.GetMetric() {
+			for _, l := range m.GetLabel() {
+				if l.GetName() == types.ProofFailureReasonLabel && l.GetValue() == reason {
+					return m.GetCounter().GetValue()
+				}
+			}
+		}
+	}
+	return 0
+}
+
+func TestKeeper_IncrementProofCount(t *testing.T) {
+	ctx, _, _, _, keeper, _, _ := createTestInput(t, false)
+	addr, other := getRandomValidatorAddress(), getRandomValidatorAddress()
+	// unlimited by default and nothing is recorded
+	for i := 0; i < 5; i++ {
+		assert.Nil(t, keeper.ValidateProofCount(ctx, addr))
+		keeper.IncrementProofCount(ctx, addr)
+	}
+	recorded, _ := ctx.KVStore(keeper.tStoreKey).Has(types.KeyForProofCount(addr))
+	assert.False(t, recorded)
+	p := keeper.GetParams(ctx)
+	p.MaxProofsPerAddressPerBlock = 2
+	keeper.SetParams(ctx, p)
+	// within the limit
+	for i := 0; i < 2; i++ {
+		assert.Nil(t, keeper.ValidateProofCount(ctx, addr))
+		keeper.IncrementProofCount(ctx, addr)
+	}
+	// over the limit
+	err := keeper.ValidateProofCount(ctx, addr)
+	assert.NotNil(t, err)
+	assert.Equal(t, sdk.CodeType(types.CodeProofLimitExceededError), err.Code())
+	// the limit is per address
+	assert.Nil(t, keeper.ValidateProofCount(ctx, other))
+	// the count is never committed to the module store
+	recorded, _ = ctx.KVStore(keeper.storeKey).Has(types.KeyForProofCount(addr))
+	assert.False(t, recorded)
+	// and is reset once the block is committed
+	ctx.MultiStore().(sdk.CommitMultiStore).GetCommitKVStore(keeper.tStoreKey).Commit()
+	assert.Nil(t, keeper.ValidateProofCount(ctx, addr))
+}
+
 func TestKeeper_GetPsuedorandomIndex(t *testing.T) {
 	var totalRelays = []int{10, 100, 10000000}
 	for _, relays := range totalRelays {
@@ -80,6 +466,7 @@ func TestKeeper_GetPsuedorandomIndex(t *testing.T) {
 		mockCtx.On("KVStore", keeper.storeKey).Return(ctx.KVStore(keeper.storeKey))
 		mockCtx.On("KVStore", keys["params"]).Return(ctx.KVStore(keys["params"]))
 		mockCtx.On("PrevCtx", header.SessionBlockHeight+keeper.ClaimSubmissionWindow(ctx)*keeper.BlocksPerSession(ctx)).Return(ctx, nil)
+		mockCtx.On("BlockHeight").Return(header.SessionBlockHeight)
 		mockCtx.On("GetPrevBlockHash", int64(76)).Return(ctx.BlockHeader().LastBlockId.Hash, nil)
 		// generate the pseudorandom proof
 		neededLeafIndex, err := keeper.getPseudorandomIndex(mockCtx, int64(relays), header, mockCtx)
@@ -111,3 +498,720 @@ func TestPseudoRandomSelection(t *testing.T) {
 	// 	fmt.Printf("index %d, was selected %d times\n", i, dataArr[i])
 	// }
 }
+
+func TestSimulateIndexDistribution(t *testing.T) {
+	header := types.SessionHeader{
+		ApplicationPubKey:  getRandomPubKey().RawString(),
+		Chain:              "0001",
+		SessionBlockHeight: 1,
+	}
+	const totalRelays, samples = 100, 50000
+	for _, uniform := range []bool{false, true} {
+		histogram, err := SimulateIndexDistribution(header, totalRelays, samples, uniform)
+		assert.Nil(t, err)
+		assert.Len(t, histogram, totalRelays)
+		// every index is selected, and none is far from the mean
+		var total int64
+		mean := float64(samples) / totalRelays
+		for index, count := range histogram {
+			assert.NotZero(t, count, index)
+			assert.InDelta(t, mean, float64(count), mean*0.3, index)
+			total += count
+		}
+		assert.Equal(t, int64(samples), total)
+	}
+	_, err := SimulateIndexDistribution(header, 0, samples, false)
+	assert.NotNil(t, err)
+}
+
+func TestSessionLogKeyVals(t *testing.T) {
+	header := types.SessionHeader{
+		ApplicationPubKey:  getRandomPubKey().RawString(),
+		Chain:              "0001",
+		SessionBlockHeight: 101,
+	}
+	kv := sessionLogKeyVals(header, 25)
+	assert.Len(t, kv, 6)
+	assert.Equal(t, []interface{}{"chain", "0001", "session_height", int64(101), "relays", int64(25)}, kv)
+}
+
+func TestNewTxBuilderAndCliCtxMissingAccount(t *testing.T) {
+	ctx, _, _, _, keeper, _, _ := createTestInput(t, false)
+	// a key with no account returns an error instead of panicking
+	_, _, err := newTxBuilderAndCliCtx(ctx, &types.MsgClaim{}, nil, getRandomPrivateKey(), keeper)
+	assert.NotNil(t, err)
+}
+
+func TestNewTxBuilderAndCliCtxBroadcastMode(t *testing.T) {
+	ctx, _, _, _, keeper, _, _ := createTestInput(t, false)
+	key := getRandomPrivateKey()
+	acc := auth.NewBaseAccountWithAddress(sdk.Address(key.PublicKey().Address()))
+	acc.Coins = sdk.NewCoins(sdk.NewCoin(sdk.DefaultStakeDenom, sdk.NewInt(100000000)))
+	keeper.authKeeper.(auth.Keeper).SetAccount(ctx, &acc)
+	defaultMode := types.GlobalPocketConfig.AutoTxBroadcastMode
+	t.Cleanup(func() { types.GlobalPocketConfig.AutoTxBroadcastMode = defaultMode })
+	for mode, expected := range map[string]util.BroadcastType{
+		"":      util.BroadcastSync,
+		"sync":  util.BroadcastSync,
+		"async": util.BroadcastAsync,
+		"block": util.BroadcastBlock,
+	} {
+		types.GlobalPocketConfig.AutoTxBroadcastMode = mode
+		_, cliCtx, err := newTxBuilderAndCliCtx(ctx, &types.MsgClaim{}, nil, key, keeper)
+		assert.Nil(t, err, mode)
+		assert.Equal(t, expected, cliCtx.BroadcastMode, mode)
+	}
+	types.GlobalPocketConfig.AutoTxBroadcastMode = "commit"
+	_, _, err := newTxBuilderAndCliCtx(ctx, &types.MsgClaim{}, nil, key, keeper)
+	assert.NotNil(t, err)
+}
+
+func TestNewTxBuilderAndCliCtxStakeDenom(t *testing.T) {
+	ctx, _, _, _, keeper, _, _ := createTestInput(t, false)
+	key := getRandomPrivateKey()
+	acc := auth.NewBaseAccountWithAddress(sdk.Address(key.PublicKey().Address()))
+	acc.Coins = sdk.NewCoins(sdk.NewCoin(sdk.DefaultStakeDenom, sdk.NewInt(100000000)))
+	keeper.authKeeper.(auth.Keeper).SetAccount(ctx, &acc)
+	txBuilder, _, err := newTxBuilderAndCliCtx(ctx, &types.MsgClaim{}, nil, key, keeper)
+	assert.Nil(t, err)
+	assert.Equal(t, sdk.DefaultStakeDenom, txBuilder.Fees()[0].Denom)
+	// the fee follows the stake denom of the nodes module
+	nk := keeper.posKeeper.(nodesKeeper.Keeper)
+	params := nk.GetParams(ctx)
+	params.StakeDenom = "uother"
+	nk.SetParams(ctx, params)
+	_, _, err = newTxBuilderAndCliCtx(ctx, &types.MsgClaim{}, nil, key, keeper)
+	assert.NotNil(t, err)
+	acc.Coins = acc.Coins.Add(sdk.NewCoins(sdk.NewCoin("uother", sdk.NewInt(100000000))))
+	keeper.authKeeper.(auth.Keeper).SetAccount(ctx, &acc)
+	txBuilder, _, err = newTxBuilderAndCliCtx(ctx, &types.MsgClaim{}, nil, key, keeper)
+	assert.Nil(t, err)
+	assert.Equal(t, "uother", txBuilder.Fees()[0].Denom)
+	params.StakeDenom = ""
+	nk.SetParams(ctx, params)
+	_, _, err = newTxBuilderAndCliCtx(ctx, &types.MsgClaim{}, nil, key, keeper)
+	assert.NotNil(t, err)
+	assert.Contains(t, err.Error(), "stake denom")
+}
+
+func TestKeeper_OnClaimVerified(t *testing.T) {
+	ctx, vals, _, _, keeper, _, _ := createTestInput(t, false)
+	claim := createTestClaim(vals[0].Address, "0001", 1, 10)
+	proof := types.MsgProof{Leaf: types.RelayProof{}, EvidenceType: types.RelayEvidence}
+	// the default behavior without a hook
+	_, err := keeper.SetClaim(ctx, claim)
+	assert.Nil(t, err)
+	rewarded, err := keeper.ExecuteProof(ctx, proof, claim)
+	assert.Nil(t, err)
+	assert.True(t, rewarded.IsPositive())
+	// the hook fires with the verified claim, after the default reward
+	var verified []types.MsgClaim
+	keeper.OnClaimVerified(func(ctx sdk.Ctx, claim types.MsgClaim) {
+		_, found := keeper.GetClaim(ctx, claim.FromAddress, claim.SessionHeader, claim.EvidenceType)
+		assert.False(t, found)
+		verified = append(verified, claim)
+	})
+	_, err = keeper.SetClaim(ctx, claim)
+	assert.Nil(t, err)
+	tokens, err := keeper.ExecuteProof(ctx, proof, claim)
+	assert.Nil(t, err)
+	assert.Equal(t, rewarded, tokens)
+	assert.Equal(t, []types.MsgClaim{claim}, verified)
+	assert.Panics(t, func() { keeper.OnClaimVerified(func(sdk.Ctx, types.MsgClaim) {}) })
+}
+
+func TestKeeper_GetPseudorandomIndexMissingBlockHash(t *testing.T) {
+	ctx, _, _, _, keeper, _, _ := createTestInput(t, false)
+	header := types.SessionHeader{
+		ApplicationPubKey:  getRandomPubKey().RawString(),
+		Chain:              "0001",
+		SessionBlockHeight: 1,
+	}
+	proofHeight := header.SessionBlockHeight + keeper.ClaimSubmissionWindow(ctx)*keeper.BlocksPerSession(ctx)
+	withBlockHash := func(hash []byte, err error) sdk.Ctx {
+		mockCtx := &Ctx{}
+		mockCtx.On("KVStore", mock.Anything).Return(func(key storeTypes.StoreKey) storeTypes.KVStore { return ctx.KVStore(key) })
+		mockCtx.On("Logger").Return(ctx.Logger())
+		mockCtx.On("BlockHeight").Return(ctx.BlockHeight())
+		mockCtx.On("GetPrevBlockHash", proofHeight).Return(hash, err)
+		return mockCtx
+	}
+	// a missing block is always an error
+	_, err := keeper.getPseudorandomIndex(withBlockHash(nil, errors.New("block at height not found")), 10, header, ctx)
+	assert.NotNil(t, err)
+	// an empty hash is only rejected after EBHASH is activated (at the proof height)
+	for _, hash := range [][]byte{nil, make([]byte, 32)} {
+		_, err := keeper.getPseudorandomIndex(withBlockHash(hash, nil), 10, header, ctx)
+		assert.Nil(t, err)
+	}
+	codec.UpgradeFeatureMap[codec.EmptyBlockHashKey] = proofHeight
+	t.Cleanup(func() { delete(codec.UpgradeFeatureMap, codec.EmptyBlockHashKey) })
+	for _, hash := range [][]byte{nil, make([]byte, 32)} {
+		_, err := keeper.getPseudorandomIndex(withBlockHash(hash, nil), 10, header, ctx)
+		assert.NotNil(t, err)
+	}
+	index, err := keeper.getPseudorandomIndex(withBlockHash(types.Hash([]byte("block")), nil), 10, header, ctx)
+	assert.Nil(t, err)
+	assert.True(t, index >= 0 && index < 10)
+}
+
+func TestPseudorandomIndexRotation(t *testing.T) {
+	header := types.SessionHeader{
+		ApplicationPubKey:  getRandomPubKey().RawString(),
+		Chain:              "0001",
+		SessionBlockHeight: 1,
+	}
+	blockHash := types.Hash([]byte("block"))
+	// no rotation keeps the legacy seed
+	legacy, err := json.Marshal(struct{ BlockHash, Header string }{hex.EncodeToString(blockHash), header.HashString()})
+	assert.Nil(t, err)
+	index, err := pseudorandomIndex(blockHash, header, 1000, false, 0)
+	assert.Nil(t, err)
+	assert.Equal(t, types.PseudorandomSelection(sdk.NewInt(1000), types.Hash(legacy)).Int64(), index)
+	// successive rotations of the same session select different leafs
+	const totalRelays = int64(1) << 40
+	seen := make(map[int64]bool)
+	for rotation := int64(0); rotation < 10; rotation++ {
+		index, err := pseudorandomIndex(blockHash, header, totalRelays, true, rotation)
+		assert.Nil(t, err)
+		assert.False(t, seen[index], rotation)
+		seen[index] = true
+	}
+}
+
+func TestKeeper_AppProofRotation(t *testing.T) {
+	ctx, vals, _, _, keeper, _, _ := createTestInput(t, false)
+	claim := createTestClaim(vals[0].Address, "0001", 1, 10)
+	app := claim.SessionHeader.ApplicationPubKey
+	proof := types.MsgProof{Leaf: types.RelayProof{}, EvidenceType: types.RelayEvidence}
+	execute := func() {
+		_, err := keeper.SetClaim(ctx, claim)
+		assert.Nil(t, err)
+		_, err = keeper.ExecuteProof(ctx, proof, claim)
+		assert.Nil(t, err)
+	}
+	proofHeight := claim.SessionHeader.SessionBlockHeight + keeper.ClaimSubmissionWindow(ctx)*keeper.BlocksPerSession(ctx)
+	mockCtx := &Ctx{}
+	mockCtx.On("KVStore", mock.Anything).Return(func(key storeTypes.StoreKey) storeTypes.KVStore { return ctx.KVStore(key) })
+	mockCtx.On("GetPrevBlockHash", proofHeight).Return(types.Hash([]byte("block")), nil)
+	index := func() int64 {
+		index, err := keeper.getPseudorandomIndex(mockCtx, 1<<40, claim.SessionHeader, ctx)
+		assert.Nil(t, err)
+		return index
+	}
+	// not counted before RPIDX
+	execute()
+	assert.Zero(t, keeper.GetAppProofRotation(ctx, app))
+	before := index()
+	codec.UpgradeFeatureMap[codec.RotatingProofIndexKey] = 1
+	t.Cleanup(func() { delete(codec.UpgradeFeatureMap, codec.RotatingProofIndexKey) })
+	// a zero rotation keeps the index
+	assert.Equal(t, before, index())
+	// each verified relay proof rotates the index of the application's later sessions
+	execute()
+	assert.Equal(t, int64(1), keeper.GetAppProofRotation(ctx, app))
+	rotated := index()
+	assert.NotEqual(t, before, rotated)
+	execute()
+	assert.Equal(t, int64(2), keeper.GetAppProofRotation(ctx, app))
+	assert.NotEqual(t, rotated, index())
+}
+
+func TestKeeper_GetRequiredProofArtifacts(t *testing.T) {
+	maxRelays := int64(5)
+	ctx, _, _, _, keeper, _, _ := createTestInput(t, false)
+	types.ClearEvidence(types.GlobalEvidenceCache)
+	_, header, _ := simulateRelays(t, keeper, &ctx, 8)
+	for index := int64(0); index < maxRelays; index++ {
+		mProof, leaf, err := keeper.GetRequiredProofArtifacts(header, types.RelayEvidence, index, maxRelays, types.GlobalEvidenceCache)
+		assert.Nil(t, err)
+		// the inline generation SendProofTx used
+		evidence, er := types.GetEvidence(header, types.RelayEvidence, sdk.ZeroInt(), types.GlobalEvidenceCache)
+		assert.Nil(t, er)
+		inlineProof, inlineLeaf := evidence.GenerateMerkleProof(header.SessionBlockHeight, int(index), maxRelays)
+		assert.Equal(t, inlineProof, mProof)
+		assert.Equal(t, inlineLeaf.Hash(), leaf.Hash())
+		assert.Equal(t, index, mProof.TargetIndex)
+	}
+	// an index past the claimed leafs
+	_, _, err := keeper.GetRequiredProofArtifacts(header, types.RelayEvidence, maxRelays, maxRelays, types.GlobalEvidenceCache)
+	assert.NotNil(t, err)
+	// no evidence for the session
+	other := header
+	other.SessionBlockHeight++
+	_, _, err = keeper.GetRequiredProofArtifacts(other, types.RelayEvidence, 0, maxRelays, types.GlobalEvidenceCache)
+	assert.NotNil(t, err)
+}
+
+func TestKeeper_GetVerifiedRelaysAsOf(t *testing.T) {
+	ctx, vals, _, _, keeper, _, _ := createTestInput(t, false)
+	addr := vals[0].Address
+	proof := types.MsgProof{Leaf: types.RelayProof{}, EvidenceType: types.RelayEvidence}
+	verify := func(ctx sdk.Ctx, sessionBlockHeight, totalProofs int64) {
+		claim := createTestClaim(addr, "0001", sessionBlockHeight, totalProofs)
+		_, err := keeper.SetClaim(ctx, claim)
+		assert.Nil(t, err)
+		_, err = keeper.ExecuteProof(ctx, proof, claim)
+		assert.Nil(t, err)
+	}
+	// not counted before VRELS
+	verify(ctx, 1, 10)
+	assert.Zero(t, keeper.GetVerifiedRelays(ctx, addr))
+	codec.UpgradeFeatureMap[codec.VerifiedRelaysKey] = 1
+	t.Cleanup(func() { delete(codec.UpgradeFeatureMap, codec.VerifiedRelaysKey) })
+	// the state at the queried height has one verified claim, the latest state has another verified after it
+	queriedCtx, _ := ctx.CacheContext()
+	verify(queriedCtx, 1, 10)
+	latestCtx, _ := ctx.CacheContext()
+	verify(latestCtx, 1, 10)
+	verify(latestCtx, 5, 7)
+	queriedHeight, latestHeight := int64(10), int64(20)
+	mockCtx := &Ctx{}
+	mockCtx.On("KVStore", mock.Anything).Return(func(key storeTypes.StoreKey) storeTypes.KVStore { return latestCtx.KVStore(key) })
+	mockCtx.On("BlockHeight").Return(latestHeight)
+	mockCtx.On("PrevCtx", queriedHeight).Return(queriedCtx, nil)
+	assert.Equal(t, int64(17), keeper.GetVerifiedRelaysAsOf(mockCtx, addr, latestHeight))
+	// excludes the claim verified after the queried height
+	assert.Equal(t, int64(10), keeper.GetVerifiedRelaysAsOf(mockCtx, addr, queriedHeight))
+	// other servicers have none
+	assert.Zero(t, keeper.GetVerifiedRelaysAsOf(mockCtx, vals[1].Address, queriedHeight))
+}
+
+func TestKeeper_SendProofTxRetriesRejectedProof(t *testing.T) {
+	ctx, _, _, _, keeper, _, _ := createTestInput(t, false)
+	ethereum := hex.EncodeToString([]byte{01})
+	header := types.SessionHeader{
+		ApplicationPubKey:  getTestApplication().PublicKey.RawString(),
+		Chain:              ethereum,
+		SessionBlockHeight: 1,
+	}
+	node := newTestPocketNode(t)
+	clientKey := getRandomPrivateKey()
+	for j := 0; j < 5; j++ {
+		proof := createProof(getTestApplicationPrivateKey(), clientKey, node.PrivateKey.PublicKey(), ethereum, j)
+		types.SetProof(header, types.RelayEvidence, proof, sdk.NewInt(100000), node.EvidenceStore)
+	}
+	acc := auth.NewBaseAccountWithAddress(node.GetAddress())
+	acc.Coins = sdk.NewCoins(sdk.NewCoin(sdk.DefaultStakeDenom, sdk.NewInt(100000000)))
+	keeper.authKeeper.(auth.Keeper).SetAccount(ctx, &acc)
+	maxAge := types.GlobalPocketConfig.MaxClaimAgeForProofRetry
+	types.GlobalPocketConfig.MaxClaimAgeForProofRetry = 1000
+	t.Cleanup(func() { types.GlobalPocketConfig.MaxClaimAgeForProofRetry = maxAge })
+	newMockCtx := func(height int64) *Ctx {
+		mockCtx := &Ctx{}
+		mockCtx.On("KVStore", mock.Anything).Return(func(key storeTypes.StoreKey) storeTypes.KVStore { return ctx.KVStore(key) })
+		mockCtx.On("Logger").Return(ctx.Logger())
+		mockCtx.On("BlockHeight").Return(height)
+		mockCtx.On("ChainID").Return(ctx.ChainID())
+		mockCtx.On("PrevCtx", header.SessionBlockHeight).Return(ctx, nil)
+		mockCtx.On("GetPrevBlockHash", mock.Anything).Return(types.Hash([]byte("block")), nil)
+		return mockCtx
+	}
+	// claim the evidence (the expiration height is preset so no session context is needed to store it)
+	claimTx := func(_ crypto.PrivateKey, _ util.CLIContext, _ auth.TxBuilder, header types.SessionHeader, totalProofs int64, root types.HashRange, evidenceType types.EvidenceType) (*sdk.TxResponse, error) {
+		_, err := keeper.SetClaim(ctx, types.MsgClaim{SessionHeader: header, MerkleRoot: root, TotalProofs: totalProofs, FromAddress: node.GetAddress(), EvidenceType: evidenceType, ExpirationHeight: 1000})
+		return nil, err
+	}
+	keeper.SendClaimTx(newMockCtx(header.SessionBlockHeight+keeper.BlocksPerSession(ctx)), keeper, nil, node, claimTx)
+	claim, found := keeper.GetClaim(ctx, node.GetAddress(), header, types.RelayEvidence)
+	assert.True(t, found)
+	var proofs int
+	proofTx := func(util.CLIContext, auth.TxBuilder, types.MerkleProof, types.Proof, types.EvidenceType) (*sdk.TxResponse, error) {
+		proofs++
+		return &sdk.TxResponse{TxHash: "hash"}, nil
+	}
+	provable, _ := keeper.ClaimMaturityHeight(ctx, header.SessionBlockHeight)
+	keeper.SendProofTx(newMockCtx(provable), nil, node, proofTx, nil)
+	assert.Equal(t, 1, proofs)
+	sent, found := node.InFlightProofs.Get(header, types.RelayEvidence)
+	assert.True(t, found)
+	assert.Equal(t, 1, sent.Attempts)
+	assert.Equal(t, provable, sent.Height)
+	assert.Equal(t, "hash", sent.TxHash)
+	// the proof is not re-sent while its tx is pending
+	keeper.SendProofTx(newMockCtx(provable), nil, node, proofTx, nil)
+	assert.Equal(t, 1, proofs)
+	// the proof-tx is rejected on chain, so the claim is still held next session and the proof is retried
+	nextSession := provable + keeper.BlocksPerSession(ctx)
+	keeper.SendProofTx(newMockCtx(nextSession), nil, node, proofTx, nil)
+	assert.Equal(t, 2, proofs)
+	sent, _ = node.InFlightProofs.Get(header, types.RelayEvidence)
+	assert.Equal(t, 2, sent.Attempts)
+	// once the proof is accepted (the claim is deleted) it is no longer tracked
+	assert.Nil(t, keeper.DeleteClaim(ctx, node.GetAddress(), header, types.RelayEvidence))
+	keeper.SendProofTx(newMockCtx(nextSession+keeper.BlocksPerSession(ctx)), nil, node, proofTx, nil)
+	assert.Equal(t, 2, proofs)
+	assert.Empty(t, node.InFlightProofs.All())
+	// a rejected proof of an expired claim is not retried
+	_, err := keeper.SetClaim(ctx, claim)
+	assert.Nil(t, err)
+	node.InFlightProofs.Set(claim, "hash", provable)
+	keeper.SendProofTx(newMockCtx(claim.ExpirationHeight), nil, node, proofTx, nil)
+	assert.Equal(t, 2, proofs)
+	_, found = node.InFlightProofs.Get(header, types.RelayEvidence)
+	assert.False(t, found)
+}
+
+func TestKeeper_SendProofTxSubmissionWindow(t *testing.T) {
+	ctx, _, _, _, keeper, _, _ := createTestInput(t, false)
+	ethereum := hex.EncodeToString([]byte{01})
+	header := types.SessionHeader{
+		ApplicationPubKey:  getTestApplication().PublicKey.RawString(),
+		Chain:              ethereum,
+		SessionBlockHeight: 1,
+	}
+	node := newTestPocketNode(t)
+	clientKey := getRandomPrivateKey()
+	for j := 0; j < 5; j++ {
+		proof := createProof(getTestApplicationPrivateKey(), clientKey, node.PrivateKey.PublicKey(), ethereum, j)
+		types.SetProof(header, types.RelayEvidence, proof, sdk.NewInt(100000), node.EvidenceStore)
+	}
+	acc := auth.NewBaseAccountWithAddress(node.GetAddress())
+	acc.Coins = sdk.NewCoins(sdk.NewCoin(sdk.DefaultStakeDenom, sdk.NewInt(100000000)))
+	keeper.authKeeper.(auth.Keeper).SetAccount(ctx, &acc)
+	window := types.GlobalPocketConfig.ProofSubmissionWindow
+	types.GlobalPocketConfig.ProofSubmissionWindow = 2
+	t.Cleanup(func() { types.GlobalPocketConfig.ProofSubmissionWindow = window })
+	newMockCtx := func(height int64) *Ctx {
+		mockCtx := &Ctx{}
+		mockCtx.On("KVStore", mock.Anything).Return(func(key storeTypes.StoreKey) storeTypes.KVStore { return ctx.KVStore(key) })
+		mockCtx.On("Logger").Return(ctx.Logger())
+		mockCtx.On("BlockHeight").Return(height)
+		mockCtx.On("ChainID").Return(ctx.ChainID())
+		mockCtx.On("PrevCtx", header.SessionBlockHeight).Return(ctx, nil)
+		mockCtx.On("GetPrevBlockHash", mock.Anything).Return(types.Hash([]byte("block")), nil)
+		return mockCtx
+	}
+	claimTx := func(_ crypto.PrivateKey, _ util.CLIContext, _ auth.TxBuilder, header types.SessionHeader, totalProofs int64, root types.HashRange, evidenceType types.EvidenceType) (*sdk.TxResponse, error) {
+		_, err := keeper.SetClaim(ctx, types.MsgClaim{SessionHeader: header, MerkleRoot: root, TotalProofs: totalProofs, FromAddress: node.GetAddress(), EvidenceType: evidenceType, ExpirationHeight: 1000})
+		return nil, err
+	}
+	keeper.SendClaimTx(newMockCtx(header.SessionBlockHeight+keeper.BlocksPerSession(ctx)), keeper, nil, node, claimTx)
+	claim, found := keeper.GetClaim(ctx, node.GetAddress(), header, types.RelayEvidence)
+	assert.True(t, found)
+	var proofs int
+	proofTx := func(util.CLIContext, auth.TxBuilder, types.MerkleProof, types.Proof, types.EvidenceType) (*sdk.TxResponse, error) {
+		proofs++
+		return &sdk.TxResponse{TxHash: "hash"}, nil
+	}
+	provable, _ := keeper.ClaimMaturityHeight(ctx, header.SessionBlockHeight)
+	// past the window the proof is deferred
+	keeper.SendProofTx(newMockCtx(provable+2), nil, node, proofTx, nil)
+	assert.Equal(t, 0, proofs)
+	keeper.SendProofTx(newMockCtx(claim.ExpirationHeight-keeper.BlocksPerSession(ctx)-1), nil, node, proofTx, nil)
+	assert.Equal(t, 0, proofs)
+	// within the window the proof is sent
+	keeper.SendProofTx(newMockCtx(provable+1), nil, node, proofTx, nil)
+	assert.Equal(t, 1, proofs)
+	// a claim that missed its window is still proven in the last session before it expires
+	node.InFlightProofs.Delete(header, types.RelayEvidence)
+	keeper.SendProofTx(newMockCtx(claim.ExpirationHeight-keeper.BlocksPerSession(ctx)), nil, node, proofTx, nil)
+	assert.Equal(t, 2, proofs)
+}
+
+func TestKeeper_ValidateProofClientKey(t *testing.T) {
+	ctx, _, _, _, keeper, keys, _ := createTestInput(t, false)
+	ethereum := hex.EncodeToString([]byte{01})
+	header := types.SessionHeader{
+		ApplicationPubKey:  getTestApplication().PublicKey.RawString(),
+		Chain:              ethereum,
+		SessionBlockHeight: 1,
+	}
+	mockCtx := &Ctx{}
+	mockCtx.On("KVStore", keeper.storeKey).Return(ctx.KVStore(keeper.storeKey))
+	mockCtx.On("KVStore", keys[sdk.ParamsKey.Name()]).Return(ctx.KVStore(keys[sdk.ParamsKey.Name()]))
+	mockCtx.On("KVStore", keys[appsTypes.StoreKey]).Return(ctx.KVStore(keys[appsTypes.StoreKey]))
+	mockCtx.On("Logger").Return(ctx.Logger())
+	mockCtx.On("BlockHeight").Return(ctx.BlockHeight())
+	mockCtx.On("IsCheckTx").Return(false)
+	mockCtx.On("PrevCtx", header.SessionBlockHeight).Return(ctx, nil)
+	proofHeight, _ := keeper.proofContextHeight(ctx, header.SessionBlockHeight)
+	mockCtx.On("GetPrevBlockHash", proofHeight).Return(ctx.BlockHeader().LastBlockId.Hash, nil)
+	// claims the relays signed with the client key and returns the proof of the required relay
+	prove := func(forge bool) types.MsgProof {
+		node := newTestPocketNode(t)
+		clientKey := getRandomPrivateKey()
+		for j := 0; j < 5; j++ {
+			proof := createProof(getTestApplicationPrivateKey(), clientKey, node.PrivateKey.PublicKey(), ethereum, j).(types.RelayProof)
+			if forge {
+				// the token names the session's application, but the client key signed it itself
+				sig, err := clientKey.Sign(proof.Token.Hash())
+				assert.Nil(t, err)
+				proof.Token.ApplicationSignature = hex.EncodeToString(sig)
+			}
+			types.SetProof(header, types.RelayEvidence, proof, sdk.NewInt(100000), node.EvidenceStore)
+		}
+		evidence, err := types.GetEvidence(header, types.RelayEvidence, sdk.NewInt(100000), node.EvidenceStore)
+		assert.Nil(t, err)
+		_, err = keeper.SetClaim(mockCtx, types.MsgClaim{
+			SessionHeader: header,
+			MerkleRoot:    evidence.GenerateMerkleRoot(header.SessionBlockHeight, 5, node.EvidenceStore),
+			TotalProofs:   5,
+			FromAddress:   node.GetAddress(),
+			EvidenceType:  types.RelayEvidence,
+		})
+		assert.Nil(t, err)
+		index, err := keeper.getPseudorandomIndex(mockCtx, 5, header, mockCtx)
+		assert.Nil(t, err)
+		mProof, leaf, err := keeper.GetRequiredProofArtifacts(header, types.RelayEvidence, index, 5, node.EvidenceStore)
+		assert.Nil(t, err)
+		return types.MsgProof{MerkleProof: mProof, Leaf: leaf, EvidenceType: types.RelayEvidence}
+	}
+	// before the activation height the client key is not checked
+	_, _, err := keeper.ValidateProof(mockCtx, prove(true))
+	assert.Nil(t, err)
+	codec.UpgradeFeatureMap[codec.ClientKeyBindingKey] = ctx.BlockHeight()
+	t.Cleanup(func() { delete(codec.UpgradeFeatureMap, codec.ClientKeyBindingKey) })
+	// a client key the session's application issued the token to
+	_, _, err = keeper.ValidateProof(mockCtx, prove(false))
+	assert.Nil(t, err)
+	// an arbitrary client key
+	assertProofFailure(t, types.ProofFailureInvalidLeaf, func() {
+		_, _, err := keeper.ValidateProof(mockCtx, prove(true))
+		assert.NotNil(t, err)
+		assert.Equal(t, sdk.CodeType(types.CodeInvalidTokenError), err.Code())
+	})
+}
+
+func TestKeeper_GetPendingProofs(t *testing.T) {
+	ctx, _, _, _, keeper, _, _ := createTestInput(t, false)
+	node := newTestPocketNode(t)
+	types.GlobalPocketNodes[node.GetAddress().String()] = node
+	t.Cleanup(func() { delete(types.GlobalPocketNodes, node.GetAddress().String()) })
+	// nothing sent
+	assert.NotNil(t, keeper.GetPendingProofs(ctx, node.GetAddress()))
+	assert.Empty(t, keeper.GetPendingProofs(ctx, node.GetAddress()))
+	// a node not hosted by this process has no pending proofs
+	assert.Empty(t, keeper.GetPendingProofs(ctx, getRandomValidatorAddress()))
+	// the proof-txs of two claims are sent but not confirmed
+	later, earlier := createTestClaim(node.GetAddress(), "0001", 5, 10), createTestClaim(node.GetAddress(), "0001", 1, 10)
+	for _, claim := range []types.MsgClaim{later, earlier} {
+		_, err := keeper.SetClaim(ctx, claim)
+		assert.Nil(t, err)
+		node.InFlightProofs.Set(claim, "hash", ctx.BlockHeight())
+	}
+	assert.Equal(t, []types.SessionHeader{earlier.SessionHeader, later.SessionHeader}, keeper.GetPendingProofs(ctx, node.GetAddress()))
+	// once the claim is verified its proof is no longer pending
+	_, err := keeper.ExecuteProof(ctx, types.MsgProof{Leaf: types.RelayProof{}, EvidenceType: types.RelayEvidence}, earlier)
+	assert.Nil(t, err)
+	assert.Equal(t, []types.SessionHeader{later.SessionHeader}, keeper.GetPendingProofs(ctx, node.GetAddress()))
+}
+
+func TestKeeper_PersistedProofIndex(t *testing.T) {
+	ctx, _, _, _, keeper, keys, _ := createTestInput(t, false)
+	addr := getRandomValidatorAddress()
+	claim := createTestClaim(addr, "0001", 1, 10)
+	mockCtx := new(Ctx)
+	mockCtx.On("KVStore", keeper.storeKey).Return(ctx.KVStore(keeper.storeKey))
+	mockCtx.On("KVStore", keys[sdk.ParamsKey.Name()]).Return(ctx.KVStore(keys[sdk.ParamsKey.Name()]))
+	// the claim is mature: past its proof context height
+	mockCtx.On("BlockHeight").Return(int64(100))
+	mockCtx.On("Logger").Return(ctx.Logger())
+	mockCtx.On("PrevCtx", claim.SessionHeader.SessionBlockHeight).Return(ctx, nil)
+	mockCtx.On("GetPrevBlockHash", int64(76)).Return(types.Hash([]byte("proof block")), nil)
+	_, err := keeper.SetClaim(mockCtx, claim)
+	assert.Nil(t, err)
+	// not persisted before the upgrade
+	keeper.DeleteExpiredClaims(mockCtx)
+	_, found := keeper.GetPersistedProofIndex(mockCtx, claim)
+	assert.False(t, found)
+	codec.UpgradeFeatureMap[codec.PersistedProofIndexKey] = 1
+	t.Cleanup(func() { delete(codec.UpgradeFeatureMap, codec.PersistedProofIndexKey) })
+	// a capped sweep resuming past the claim does not visit it, so it is persisted late, once the sweep wraps around
+	p := keeper.GetParams(ctx)
+	p.MaxClaimsDeletedPerBlock = 2
+	keeper.SetParams(ctx, p)
+	claimKey, er := types.KeyForClaim(mockCtx, addr, claim.SessionHeader, claim.EvidenceType)
+	assert.Nil(t, er)
+	_ = ctx.KVStore(keeper.storeKey).Set(types.ExpiredClaimsCursorKey, append(append([]byte{}, claimKey...), 0x00))
+	keeper.DeleteExpiredClaims(mockCtx)
+	_, found = keeper.GetPersistedProofIndex(mockCtx, claim)
+	assert.False(t, found)
+	keeper.DeleteExpiredClaims(mockCtx)
+	index, found := keeper.GetPersistedProofIndex(mockCtx, claim)
+	assert.True(t, found)
+	// the stored index equals a fresh computation
+	fresh, er := keeper.getPseudorandomIndex(mockCtx, claim.TotalProofs, claim.SessionHeader, ctx)
+	assert.Nil(t, er)
+	assert.Equal(t, fresh, index)
+	requiredProof, err := keeper.GetRequiredProof(mockCtx, addr, claim.SessionHeader, claim.EvidenceType)
+	assert.Nil(t, err)
+	assert.Equal(t, fresh, requiredProof.Index)
+	// once persisted, the prover and the validators read it instead of recomputing
+	bz := make([]byte, 8)
+	binary.BigEndian.PutUint64(bz, uint64(fresh+1))
+	_ = ctx.KVStore(keeper.storeKey).Set(types.KeyForProofIndex(claimKey), bz)
+	persisted, er := keeper.requiredProofIndex(mockCtx, claim, ctx)
+	assert.Nil(t, er)
+	assert.Equal(t, fresh+1, persisted)
+	// a later sweep does not overwrite it
+	keeper.DeleteExpiredClaims(mockCtx)
+	index, _ = keeper.GetPersistedProofIndex(mockCtx, claim)
+	assert.Equal(t, fresh+1, index)
+	// and it is deleted with the claim
+	assert.Nil(t, keeper.DeleteClaim(mockCtx, addr, claim.SessionHeader, claim.EvidenceType))
+	_, found = keeper.GetPersistedProofIndex(mockCtx, claim)
+	assert.False(t, found)
+}
+
+func TestKeeper_ExecuteProofEmitsProofVerified(t *testing.T) {
+	ctx, vals, _, _, k, _, _ := createTestInput(t, false)
+	addr := vals[0].Address
+	claim := createTestClaim(addr, "0001", 1, 10)
+	_, err := k.SetClaim(ctx, claim)
+	assert.Nil(t, err)
+	ctx = ctx.WithEventManager(sdk.NewEventManager())
+	proof := types.MsgProof{MerkleProof: types.MerkleProof{TargetIndex: 3}, Leaf: types.RelayProof{}, EvidenceType: types.RelayEvidence}
+	_, er := k.ExecuteProof(ctx, proof, claim)
+	assert.Nil(t, er)
+	attributes := make(map[string]string)
+	for _, e := range ctx.EventManager().Events() {
+		if e.Type != types.EventTypeProofVerified {
+			continue
+		}
+		for _, a := range e.Attributes {
+			attributes[string(a.Key)] = string(a.Value)
+		}
+	}
+	assert.Equal(t, map[string]string{
+		types.AttributeKeyServicer:      addr.String(),
+		types.AttributeKeyChain:         "0001",
+		types.AttributeKeySessionHeight: "1",
+		types.AttributeKeyTotalRelays:   "10",
+		types.AttributeKeyProofIndex:    "3",
+	}, attributes)
+}
+
+func TestVerifyLeafClientKeyCache(t *testing.T) {
+	ctx, _, _, _, k, _, _ := createTestInput(t, false)
+	appPrivateKey := getRandomPrivateKey()
+	appPubKey := appPrivateKey.PublicKey().RawString()
+	leaf := createProof(appPrivateKey, getRandomPrivateKey(), getRandomPubKey(), "0001", 1).(types.RelayProof)
+	// a leaf whose token signature belongs to another token
+	forged := leaf
+	forged.Token.ApplicationSignature = createProof(appPrivateKey, getRandomPrivateKey(), getRandomPubKey(), "0001", 1).(types.RelayProof).Token.ApplicationSignature
+	for _, tc := range []struct {
+		name      string
+		leaf      types.RelayProof
+		appPubKey string
+	}{
+		{"valid leaf", leaf, appPubKey},
+		{"forged token signature", forged, appPubKey},
+		{"other application", leaf, getRandomPubKey().RawString()},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			expected := tc.leaf.ValidateClientKey(tc.appPubKey)
+			// the first check misses the cache, the second hits it; both return the uncached result
+			assert.Equal(t, expected, k.verifyLeafClientKey(ctx, tc.leaf, tc.appPubKey))
+			assert.Equal(t, expected, k.verifyLeafClientKey(ctx, tc.leaf, tc.appPubKey))
+		})
+	}
+	assert.Nil(t, k.verifyLeafClientKey(ctx, leaf, appPubKey))
+	assert.NotNil(t, k.verifyLeafClientKey(ctx, forged, appPubKey))
+	assert.Len(t, k.verifyCaches.deliver.results, 3)
+	// CheckTx (and simulate) use a cache of their own
+	assert.Empty(t, k.verifyCaches.check.results)
+	checkCtx := ctx.WithIsCheckTx(true)
+	assert.Nil(t, k.verifyLeafClientKey(checkCtx, leaf, appPubKey))
+	assert.Len(t, k.verifyCaches.check.results, 1)
+	assert.Len(t, k.verifyCaches.deliver.results, 3)
+	// a leaf checked at another height resets the cache of its mode
+	assert.Nil(t, k.verifyLeafClientKey(checkCtx.WithBlockHeight(ctx.BlockHeight()+1), leaf, appPubKey))
+	assert.Len(t, k.verifyCaches.check.results, 1)
+	assert.Equal(t, ctx.BlockHeight()+1, k.verifyCaches.check.height)
+	k.ClearProofVerificationCache()
+	assert.Empty(t, k.verifyCaches.deliver.results)
+	assert.Empty(t, k.verifyCaches.check.results)
+}
+
+func BenchmarkVerifyLeafClientKey(b *testing.B) {
+	k := Keeper{verifyCaches: &proofVerificationCaches{}}
+	ctx := sdk.NewContext(nil, abci.Header{Height: 1}, false, nil)
+	appPrivateKey := getRandomPrivateKey()
+	appPubKey := appPrivateKey.PublicKey().RawString()
+	leaf := createProof(appPrivateKey, getRandomPrivateKey(), getRandomPubKey(), "0001", 1).(types.RelayProof)
+	b.Run("uncached", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			_ = leaf.ValidateClientKey(appPubKey)
+		}
+	})
+	b.Run("cached", func(b *testing.B) {
+		k.ClearProofVerificationCache()
+		b.ResetTimer()
+		for i := 0; i < b.N; i++ {
+			_ = k.verifyLeafClientKey(ctx, leaf, appPubKey)
+		}
+	})
+}
+
+func TestKeeper_GetClaimFeesSpent(t *testing.T) {
+	ctx, _, _, _, keeper, _, _ := createTestInput(t, false)
+	ethereum := hex.EncodeToString([]byte{01})
+	header := types.SessionHeader{
+		ApplicationPubKey:  getTestApplication().PublicKey.RawString(),
+		Chain:              ethereum,
+		SessionBlockHeight: 1,
+	}
+	node := newTestPocketNode(t)
+	types.GlobalPocketNodes[node.GetAddress().String()] = node
+	t.Cleanup(func() { delete(types.GlobalPocketNodes, node.GetAddress().String()) })
+	clientKey := getRandomPrivateKey()
+	for j := 0; j < 5; j++ {
+		proof := createProof(getTestApplicationPrivateKey(), clientKey, node.PrivateKey.PublicKey(), ethereum, j)
+		types.SetProof(header, types.RelayEvidence, proof, sdk.NewInt(100000), node.EvidenceStore)
+	}
+	acc := auth.NewBaseAccountWithAddress(node.GetAddress())
+	acc.Coins = sdk.NewCoins(sdk.NewCoin(sdk.DefaultStakeDenom, sdk.NewInt(100000000)))
+	keeper.authKeeper.(auth.Keeper).SetAccount(ctx, &acc)
+	maxAge := types.GlobalPocketConfig.MaxClaimAgeForProofRetry
+	types.GlobalPocketConfig.MaxClaimAgeForProofRetry = 1000
+	t.Cleanup(func() { types.GlobalPocketConfig.MaxClaimAgeForProofRetry = maxAge })
+	newMockCtx := func(height int64) *Ctx {
+		mockCtx := &Ctx{}
+		mockCtx.On("KVStore", mock.Anything).Return(func(key storeTypes.StoreKey) storeTypes.KVStore { return ctx.KVStore(key) })
+		mockCtx.On("Logger").Return(ctx.Logger())
+		mockCtx.On("BlockHeight").Return(height)
+		mockCtx.On("ChainID").Return(ctx.ChainID())
+		mockCtx.On("PrevCtx", header.SessionBlockHeight).Return(ctx, nil)
+		mockCtx.On("GetPrevBlockHash", mock.Anything).Return(types.Hash([]byte("block")), nil)
+		return mockCtx
+	}
+	query := func() (fees sdk.Coins) {
+		bz, er := makeTestCodec().MarshalJSON(types.QueryClaimFeesSpentParams{Address: node.GetAddress()})
+		assert.Nil(t, er)
+		res, err := queryClaimFeesSpent(ctx, abci.RequestQuery{Data: bz}, keeper)
+		assert.Nil(t, err)
+		assert.Nil(t, makeTestCodec().UnmarshalJSON(res, &fees))
+		return
+	}
+	// nothing sent yet
+	assert.True(t, keeper.GetClaimFeesSpent(ctx, node.GetAddress()).IsZero())
+	claimTx := func(_ crypto.PrivateKey, _ util.CLIContext, _ auth.TxBuilder, header types.SessionHeader, totalProofs int64, root types.HashRange, evidenceType types.EvidenceType) (*sdk.TxResponse, error) {
+		_, err := keeper.SetClaim(ctx, types.MsgClaim{SessionHeader: header, MerkleRoot: root, TotalProofs: totalProofs, FromAddress: node.GetAddress(), EvidenceType: evidenceType, ExpirationHeight: 1000})
+		return &sdk.TxResponse{TxHash: "claim"}, err
+	}
+	keeper.SendClaimTx(newMockCtx(header.SessionBlockHeight+keeper.BlocksPerSession(ctx)), keeper, nil, node, claimTx)
+	claimFee := sdk.NewCoins(sdk.NewCoin(sdk.DefaultStakeDenom, keeper.authKeeper.GetFee(ctx, &types.MsgClaim{})))
+	assert.Equal(t, claimFee, keeper.GetClaimFeesSpent(ctx, node.GetAddress()))
+	// the proof-tx is sent, rejected on chain and sent again: both pay their fees
+	proofFee := sdk.NewCoins(sdk.NewCoin(sdk.DefaultStakeDenom, keeper.authKeeper.GetFee(ctx, &types.MsgProof{})))
+	code := uint32(0)
+	proofTx := func(util.CLIContext, auth.TxBuilder, types.MerkleProof, types.Proof, types.EvidenceType) (*sdk.TxResponse, error) {
+		return &sdk.TxResponse{TxHash: "proof", Code: code}, nil
+	}
+	provable, _ := keeper.ClaimMaturityHeight(ctx, header.SessionBlockHeight)
+	keeper.SendProofTx(newMockCtx(provable), nil, node, proofTx, nil)
+	keeper.SendProofTx(newMockCtx(provable+keeper.BlocksPerSession(ctx)), nil, node, proofTx, nil)
+	expected := claimFee.Add(proofFee).Add(proofFee)
+	assert.Equal(t, expected, keeper.GetClaimFeesSpent(ctx, node.GetAddress()))
+	// a tx rejected by the mempool pays no fees
+	code = 1
+	keeper.SendProofTx(newMockCtx(provable+2*keeper.BlocksPerSession(ctx)), nil, node, proofTx, nil)
+	assert.Equal(t, expected, keeper.GetClaimFeesSpent(ctx, node.GetAddress()))
+	assert.Equal(t, expected, query())
+	// a node not hosted by this process cannot be queried
+	bz, er := makeTestCodec().MarshalJSON(types.QueryClaimFeesSpentParams{Address: getRandomValidatorAddress()})
+	assert.Nil(t, er)
+	_, err := queryClaimFeesSpent(ctx, abci.RequestQuery{Data: bz}, keeper)
+	assert.NotNil(t, err)
+}
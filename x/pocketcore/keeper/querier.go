@@ -1,11 +1,16 @@
 package keeper
 
 import (
+	"encoding/hex"
 	"fmt"
 	"github.com/pokt-network/pocket-core/codec"
 	sdk "github.com/pokt-network/pocket-core/types"
+	"github.com/pokt-network/pocket-core/x/auth/util"
 	"github.com/pokt-network/pocket-core/x/pocketcore/types"
 	abci "github.com/tendermint/tendermint/abci/types"
+	"math"
+	"sort"
+	"sync"
 )
 
 // "NewQuerier" - Creates an sdk.Querier for the pocket core module
@@ -27,6 +32,72 @@ func NewQuerier(k Keeper) sdk.Querier {
 		// endpoint allowing a client to submit a challenge for an invalid relay-response
 		case types.QueryChallenge:
 			return queryChallenge(ctx, req, k)
+		// query the total relays claimed per chain across all addresses
+		case types.QueryRelaysByChain:
+			return queryRelaysByChain(ctx, k)
+		// query the estimated reward for a claim of relays by a node
+		case types.QueryEstimateClaimReward:
+			return queryEstimateClaimReward(ctx, req, k)
+		// query the leaf index and level count a claim must be proven with
+		case types.QueryRequiredProof:
+			return queryRequiredProof(ctx, req, k)
+		// query the evidence and claims of a node still waiting on a claim or proof
+		case types.QueryProofBacklog:
+			return queryProofBacklog(ctx, req, k)
+		// query the claims of a node that expire within a number of sessions
+		case types.QueryExpiringClaims:
+			return queryExpiringClaims(ctx, req, k)
+		// query the parameters governing claims and proofs
+		case types.QueryProofParameters:
+			return queryProofParameters(ctx, k)
+		// query the claims of a node between two session heights
+		case types.QueryClaimsInHeightRange:
+			return queryClaimsInHeightRange(ctx, req, k)
+		// query the distinct chains a node holds claims for
+		case types.QueryServedChains:
+			return queryServedChains(ctx, req, k)
+		// query whether a proof is held in the evidence of a node (by its leaf hash)
+		case types.QueryProofByHash:
+			return queryProofByHash(ctx, req, k)
+		// query when the claim of a session can be submitted and proven
+		case types.QueryClaimTimeline:
+			return queryClaimTimeline(ctx, req, k)
+		// query only the merkle root of a stored claim
+		case types.QueryClaimRoot:
+			return queryClaimRoot(ctx, req, k)
+		// query whether a proof would validate against the stored claim, without sending it
+		case types.QueryValidateProof:
+			return queryValidateProof(ctx, req, k)
+		// query the total relays verified for a servicer as of a height
+		case types.QueryVerifiedRelays:
+			return queryVerifiedRelays(ctx, req, k)
+		// query a page of the claims for a chain across all addresses
+		case types.QueryClaimsByChain:
+			return queryClaimsByChain(ctx, req, k)
+		// query the aggregate statistics of the proof module
+		case types.QueryProofModuleStats:
+			return queryProofModuleStats(ctx, k)
+		// query the merkle path of a leaf of a node's stored claim
+		case types.QueryMerklePath:
+			return queryMerklePath(ctx, req, k)
+		// query the claims of a node grouped by session block height
+		case types.QueryClaimsBySession:
+			return queryClaimsBySession(ctx, req, k)
+		// query the expiration height of a stored claim under the current params
+		case types.QueryClaimExpiration:
+			return queryClaimExpiration(ctx, req, k)
+		// query the fees a node hosted by this process has spent on claim and proof txs
+		case types.QueryClaimFeesSpent:
+			return queryClaimFeesSpent(ctx, req, k)
+		// query the claims of an address awaiting maturity, with the blocks until each is mature
+		case types.QueryImmatureClaims:
+			return queryImmatureClaims(ctx, req, k)
+		// query the relays per chain held in the evidence cache of a node hosted by this process
+		case types.QueryCachedRelaysByChain:
+			return queryCachedRelaysByChain(req)
+		// query whether an address has a relay claim of a session
+		case types.QueryIsClaimant:
+			return queryIsClaimant(ctx, req, k)
 		default:
 			return nil, sdk.ErrUnknownRequest("unknown pocketcore query endpoint")
 		}
@@ -99,6 +170,397 @@ func queryDispatch(ctx sdk.Ctx, req abci.RequestQuery, k Keeper) ([]byte, sdk.Er
 	return res, nil
 }
 
+// "queryRelaysByChain" - Is a handler for the relays by chain query
+// Returns the total relays claimed per chain across all addresses
+func queryRelaysByChain(ctx sdk.Ctx, k Keeper) ([]byte, sdk.Error) {
+	// marshal response data into amino-json
+	res, err := codec.MarshalJSONIndent(types.ModuleCdc, k.GetRelaysByChainAll(ctx))
+	if err != nil {
+		return nil, sdk.ErrInternal(sdk.AppendMsgToErr("failed to JSON marshal result: %s", err.Error()))
+	}
+	return res, nil
+}
+
+// "queryEstimateClaimReward" - Is a handler for the estimate claim reward query
+// Returns the coins a node would be awarded for proving a claim of the given relays (for the chain, if any)
+func queryEstimateClaimReward(ctx sdk.Ctx, req abci.RequestQuery, k Keeper) ([]byte, sdk.Error) {
+	// unmarshal data into a query params object
+	var params types.QueryEstimateClaimRewardParams
+	err := types.ModuleCdc.UnmarshalJSON(req.Data, &params)
+	if err != nil {
+		return nil, sdk.ErrInternal(fmt.Sprintf("failed to parse params: %s", err))
+	}
+	// marshal response data into amino-json
+	res, err := codec.MarshalJSONIndent(types.ModuleCdc, k.EstimateClaimReward(ctx, params.Address, params.Chain, params.TotalRelays))
+	if err != nil {
+		return nil, sdk.ErrInternal(sdk.AppendMsgToErr("failed to JSON marshal result: %s", err.Error()))
+	}
+	return res, nil
+}
+
+// "queryRequiredProof" - Is a handler for the required proof query
+// Returns the leaf index and level count the proof for a claim must be built with
+func queryRequiredProof(ctx sdk.Ctx, req abci.RequestQuery, k Keeper) ([]byte, sdk.Error) {
+	// unmarshal data into a query params object
+	var params types.QueryRequiredProofParams
+	err := types.ModuleCdc.UnmarshalJSON(req.Data, &params)
+	if err != nil {
+		return nil, sdk.ErrInternal(fmt.Sprintf("failed to parse params: %s", err))
+	}
+	evidenceType, er := types.EvidenceTypeFromString(params.Type)
+	if er != nil {
+		return nil, er
+	}
+	// compute the required proof for the claim
+	requiredProof, er := k.GetRequiredProof(ctx, params.Address, params.Header, evidenceType)
+	if er != nil {
+		return nil, er
+	}
+	// marshal response data into amino-json
+	res, err := codec.MarshalJSONIndent(types.ModuleCdc, requiredProof)
+	if err != nil {
+		return nil, sdk.ErrInternal(sdk.AppendMsgToErr("failed to JSON marshal result: %s", err.Error()))
+	}
+	return res, nil
+}
+
+// "queryProofBacklog" - Is a handler for the proof backlog query
+// Returns how much of a node's work is waiting on a claim or proof
+func queryProofBacklog(ctx sdk.Ctx, req abci.RequestQuery, k Keeper) ([]byte, sdk.Error) {
+	// unmarshal data into a query params object
+	var params types.QueryProofBacklogParams
+	err := types.ModuleCdc.UnmarshalJSON(req.Data, &params)
+	if err != nil {
+		return nil, sdk.ErrInternal(fmt.Sprintf("failed to parse params: %s", err))
+	}
+	backlog, err := k.GetProofBacklog(ctx, params.Address)
+	if err != nil {
+		return nil, sdk.ErrInternal(err.Error())
+	}
+	// marshal response data into amino-json
+	res, err := codec.MarshalJSONIndent(types.ModuleCdc, backlog)
+	if err != nil {
+		return nil, sdk.ErrInternal(sdk.AppendMsgToErr("failed to JSON marshal result: %s", err.Error()))
+	}
+	return res, nil
+}
+
+// "queryExpiringClaims" - Is a handler for the expiring claims query
+// Returns the claims of the address that expire within the given number of sessions, so they can be polled for alerting
+func queryExpiringClaims(ctx sdk.Ctx, req abci.RequestQuery, k Keeper) ([]byte, sdk.Error) {
+	// unmarshal data into a query params object
+	var params types.QueryExpiringClaimsParams
+	err := types.ModuleCdc.UnmarshalJSON(req.Data, &params)
+	if err != nil {
+		return nil, sdk.ErrInternal(fmt.Sprintf("failed to parse params: %s", err))
+	}
+	if params.Within < 0 {
+		return nil, sdk.ErrUnknownRequest(fmt.Sprintf("the number of sessions must not be negative: %d", params.Within))
+	}
+	claims, err := k.GetExpiringClaims(ctx, params.Address, params.Within)
+	if err != nil {
+		return nil, sdk.ErrInternal(err.Error())
+	}
+	// marshal response data into amino-json
+	res, err := codec.MarshalJSONIndent(types.ModuleCdc, claims)
+	if err != nil {
+		return nil, sdk.ErrInternal(sdk.AppendMsgToErr("failed to JSON marshal result: %s", err.Error()))
+	}
+	return res, nil
+}
+
+// "queryClaimsInHeightRange" - Is a handler for the claims in height range query
+// Returns the claims of an address with a session block height within the inclusive bounds
+func queryClaimsInHeightRange(ctx sdk.Ctx, req abci.RequestQuery, k Keeper) ([]byte, sdk.Error) {
+	// unmarshal data into a query params object
+	var params types.QueryClaimsInHeightRangeParams
+	err := types.ModuleCdc.UnmarshalJSON(req.Data, &params)
+	if err != nil {
+		return nil, sdk.ErrInternal(fmt.Sprintf("failed to parse params: %s", err))
+	}
+	if params.StartHeight > params.EndHeight {
+		return nil, sdk.ErrUnknownRequest(fmt.Sprintf("the start height %d is after the end height %d", params.StartHeight, params.EndHeight))
+	}
+	claims, err := k.GetClaimsInHeightRange(ctx, params.Address, params.StartHeight, params.EndHeight)
+	if err != nil {
+		return nil, sdk.ErrInternal(err.Error())
+	}
+	// marshal response data into amino-json
+	res, err := codec.MarshalJSONIndent(types.ModuleCdc, claims)
+	if err != nil {
+		return nil, sdk.ErrInternal(sdk.AppendMsgToErr("failed to JSON marshal result: %s", err.Error()))
+	}
+	return res, nil
+}
+
+// "queryServedChains" - Is a handler for the served chains query
+// Returns the sorted, distinct chains an address holds claims for
+func queryServedChains(ctx sdk.Ctx, req abci.RequestQuery, k Keeper) ([]byte, sdk.Error) {
+	// unmarshal data into a query params object
+	var params types.QueryServedChainsParams
+	err := types.ModuleCdc.UnmarshalJSON(req.Data, &params)
+	if err != nil {
+		return nil, sdk.ErrInternal(fmt.Sprintf("failed to parse params: %s", err))
+	}
+	chains, err := k.GetServedChains(ctx, params.Address)
+	if err != nil {
+		return nil, sdk.ErrInternal(err.Error())
+	}
+	// marshal response data into amino-json
+	res, err := codec.MarshalJSONIndent(types.ModuleCdc, chains)
+	if err != nil {
+		return nil, sdk.ErrInternal(sdk.AppendMsgToErr("failed to JSON marshal result: %s", err.Error()))
+	}
+	return res, nil
+}
+
+// "queryProofByHash" - Is a handler for the proof by hash query
+// Returns whether a relay proof is held in the evidence of a node hosted by this process, and its index there
+func queryProofByHash(_ sdk.Ctx, req abci.RequestQuery, _ Keeper) ([]byte, sdk.Error) {
+	// unmarshal data into a query params object
+	var params types.QueryProofByHashParams
+	err := types.ModuleCdc.UnmarshalJSON(req.Data, &params)
+	if err != nil {
+		return nil, sdk.ErrInternal(fmt.Sprintf("failed to parse params: %s", err))
+	}
+	evidenceType, er := types.EvidenceTypeFromString(params.Type)
+	if er != nil {
+		return nil, er
+	}
+	leafHash, err := hex.DecodeString(params.Hash)
+	if err != nil {
+		return nil, sdk.ErrUnknownRequest(fmt.Sprintf("the hash is not hex encoded: %s", err))
+	}
+	// evidence is held locally, so only the nodes hosted by this process can be queried
+	node, err := types.GetPocketNodeByAddress(&params.Address)
+	if err != nil || node.EvidenceStore == nil {
+		return nil, sdk.ErrUnknownRequest(fmt.Sprintf("the node %s is not hosted by this process", params.Address.String()))
+	}
+	var location types.ProofLocation
+	location.Index, location.Found = types.FindProofByHash(params.Header, evidenceType, leafHash, node.EvidenceStore)
+	// marshal response data into amino-json
+	res, err := codec.MarshalJSONIndent(types.ModuleCdc, location)
+	if err != nil {
+		return nil, sdk.ErrInternal(sdk.AppendMsgToErr("failed to JSON marshal result: %s", err.Error()))
+	}
+	return res, nil
+}
+
+// "queryClaimTimeline" - Is a handler for the claim timeline query
+// Returns whether a session's claim can be submitted now, when it can be proven and when it expires
+func queryClaimTimeline(ctx sdk.Ctx, req abci.RequestQuery, k Keeper) ([]byte, sdk.Error) {
+	// unmarshal data into a query params object
+	var params types.QueryClaimTimelineParams
+	err := types.ModuleCdc.UnmarshalJSON(req.Data, &params)
+	if err != nil {
+		return nil, sdk.ErrInternal(fmt.Sprintf("failed to parse params: %s", err))
+	}
+	evidenceType, er := types.EvidenceTypeFromString(params.Type)
+	if er != nil {
+		return nil, er
+	}
+	timeline, err := k.GetClaimTimeline(ctx, params.Address, params.Header, evidenceType)
+	if err != nil {
+		return nil, sdk.ErrInternal(err.Error())
+	}
+	// marshal response data into amino-json
+	res, err := codec.MarshalJSONIndent(types.ModuleCdc, timeline)
+	if err != nil {
+		return nil, sdk.ErrInternal(sdk.AppendMsgToErr("failed to JSON marshal result: %s", err.Error()))
+	}
+	return res, nil
+}
+
+// "queryClaimRoot" - Is a handler for the claim root query
+// Returns the merkle root of a stored claim without the rest of the claim
+func queryClaimRoot(ctx sdk.Ctx, req abci.RequestQuery, k Keeper) ([]byte, sdk.Error) {
+	// unmarshal data into a query params object
+	var params types.QueryClaimRootParams
+	err := types.ModuleCdc.UnmarshalJSON(req.Data, &params)
+	if err != nil {
+		return nil, sdk.ErrInternal(fmt.Sprintf("failed to parse params: %s", err))
+	}
+	evidenceType, er := types.EvidenceTypeFromString(params.Type)
+	if er != nil {
+		return nil, er
+	}
+	claim, found := k.GetClaim(ctx, params.Address, params.Header, evidenceType)
+	if !found {
+		return nil, types.NewClaimNotFoundError(types.ModuleName)
+	}
+	// marshal response data into amino-json
+	res, err := codec.MarshalJSONIndent(types.ModuleCdc, claim.MerkleRoot)
+	if err != nil {
+		return nil, sdk.ErrInternal(sdk.AppendMsgToErr("failed to JSON marshal result: %s", err.Error()))
+	}
+	return res, nil
+}
+
+// "queryValidateProof" - Is a handler for the validate proof query
+// Runs the proof validation of the proof handler against the stored claim, without changing state
+func queryValidateProof(ctx sdk.Ctx, req abci.RequestQuery, k Keeper) ([]byte, sdk.Error) {
+	// unmarshal data into a query params object
+	var params types.QueryValidateProofParams
+	err := types.ModuleCdc.UnmarshalJSON(req.Data, &params)
+	if err != nil {
+		return nil, sdk.ErrInternal(fmt.Sprintf("failed to parse params: %s", err))
+	}
+	var validation types.ProofValidation
+	if er := params.Proof.ValidateBasic(); er != nil {
+		validation = types.ProofValidation{Code: er.Code(), Codespace: er.Codespace(), Error: er.Error()}
+	} else if servicer, _, er := k.validateProof(ctx, params.Proof, false); er != nil {
+		validation = types.ProofValidation{Code: er.Code(), Codespace: er.Codespace(), Error: er.Error()}
+	} else {
+		validation = types.ProofValidation{Valid: true, Servicer: servicer}
+	}
+	// marshal response data into amino-json
+	res, err := codec.MarshalJSONIndent(types.ModuleCdc, validation)
+	if err != nil {
+		return nil, sdk.ErrInternal(sdk.AppendMsgToErr("failed to JSON marshal result: %s", err.Error()))
+	}
+	return res, nil
+}
+
+// "queryVerifiedRelays" - Is a handler for the verified relays query
+// Returns the total relays verified for the servicer up to the height (the latest height if zero)
+func queryVerifiedRelays(ctx sdk.Ctx, req abci.RequestQuery, k Keeper) ([]byte, sdk.Error) {
+	// unmarshal data into a query params object
+	var params types.QueryVerifiedRelaysParams
+	err := types.ModuleCdc.UnmarshalJSON(req.Data, &params)
+	if err != nil {
+		return nil, sdk.ErrInternal(fmt.Sprintf("failed to parse params: %s", err))
+	}
+	height := params.Height
+	if height == 0 {
+		height = ctx.BlockHeight()
+	}
+	// marshal response data into amino-json
+	res, err := codec.MarshalJSONIndent(types.ModuleCdc, k.GetVerifiedRelaysAsOf(ctx, params.Address, height))
+	if err != nil {
+		return nil, sdk.ErrInternal(sdk.AppendMsgToErr("failed to JSON marshal result: %s", err.Error()))
+	}
+	return res, nil
+}
+
+// "queryClaimsByChain" - Is a handler for the claims by chain query
+// Returns a page of the claims for a chain across all addresses
+func queryClaimsByChain(ctx sdk.Ctx, req abci.RequestQuery, k Keeper) ([]byte, sdk.Error) {
+	// unmarshal data into a query params object
+	var params types.QueryClaimsByChainParams
+	err := types.ModuleCdc.UnmarshalJSON(req.Data, &params)
+	if err != nil {
+		return nil, sdk.ErrInternal(fmt.Sprintf("failed to parse params: %s", err))
+	}
+	page := paginateClaims(params.Page, params.Limit, k.GetClaimsByChainAll(ctx, params.Chain))
+	// marshal response data into amino-json
+	res, err := codec.MarshalJSONIndent(types.ModuleCdc, page)
+	if err != nil {
+		return nil, sdk.ErrInternal(sdk.AppendMsgToErr("failed to JSON marshal result: %s", err.Error()))
+	}
+	return res, nil
+}
+
+// "proofModuleStatsCache" - The proof module statistics of the last height queried; the state of a committed height does
+// not change, so the claims are only iterated once per block however often the stats are queried
+var proofModuleStatsCache struct {
+	sync.Mutex
+	stats *types.ProofModuleStats
+}
+
+// "queryProofModuleStats" - Is a handler for the proof module stats query
+// Returns the aggregate statistics of the proof module, cached per block
+func queryProofModuleStats(ctx sdk.Ctx, k Keeper) ([]byte, sdk.Error) {
+	proofModuleStatsCache.Lock()
+	if proofModuleStatsCache.stats == nil || proofModuleStatsCache.stats.Height != ctx.BlockHeight() {
+		stats := k.GetProofModuleStats(ctx)
+		proofModuleStatsCache.stats = &stats
+	}
+	stats := *proofModuleStatsCache.stats
+	proofModuleStatsCache.Unlock()
+	// marshal response data into amino-json
+	res, err := codec.MarshalJSONIndent(types.ModuleCdc, stats)
+	if err != nil {
+		return nil, sdk.ErrInternal(sdk.AppendMsgToErr("failed to JSON marshal result: %s", err.Error()))
+	}
+	return res, nil
+}
+
+// "queryMerklePath" - Is a handler for the merkle path query
+// Returns the merkle path of a leaf of a node's stored claim for client side verification
+func queryMerklePath(ctx sdk.Ctx, req abci.RequestQuery, k Keeper) ([]byte, sdk.Error) {
+	// unmarshal data into a query params object
+	var params types.QueryMerklePathParams
+	err := types.ModuleCdc.UnmarshalJSON(req.Data, &params)
+	if err != nil {
+		return nil, sdk.ErrInternal(fmt.Sprintf("failed to parse params: %s", err))
+	}
+	evidenceType, er := types.EvidenceTypeFromString(params.Type)
+	if er != nil {
+		return nil, er
+	}
+	// evidence is held locally, so only the nodes hosted by this process can be queried
+	node, err := types.GetPocketNodeByAddress(&params.Address)
+	if err != nil || node.EvidenceStore == nil {
+		return nil, sdk.ErrUnknownRequest(fmt.Sprintf("the node %s is not hosted by this process", params.Address.String()))
+	}
+	path, er := k.GetMerklePath(ctx, params.Address, params.Header, evidenceType, params.Index, node.EvidenceStore)
+	if er != nil {
+		return nil, er
+	}
+	// marshal response data into amino-json
+	res, err := codec.MarshalJSONIndent(types.ModuleCdc, path)
+	if err != nil {
+		return nil, sdk.ErrInternal(sdk.AppendMsgToErr("failed to JSON marshal result: %s", err.Error()))
+	}
+	return res, nil
+}
+
+// "queryClaimsBySession" - Is a handler for the claims by session query
+// Returns the claims of an address grouped by session block height, in ascending height order
+func queryClaimsBySession(ctx sdk.Ctx, req abci.RequestQuery, k Keeper) ([]byte, sdk.Error) {
+	// unmarshal data into a query params object
+	var params types.QueryClaimsBySessionParams
+	err := types.ModuleCdc.UnmarshalJSON(req.Data, &params)
+	if err != nil {
+		return nil, sdk.ErrInternal(fmt.Sprintf("failed to parse params: %s", err))
+	}
+	bySession, err := k.GetClaimsBySession(ctx, params.Address)
+	if err != nil {
+		return nil, sdk.ErrInternal(err.Error())
+	}
+	// a list ordered by height, as the json keys of a map are unordered strings
+	sessions := make([]types.SessionClaims, 0, len(bySession))
+	for height, claims := range bySession {
+		sessions = append(sessions, types.SessionClaims{SessionBlockHeight: height, Claims: claims})
+	}
+	sort.Slice(sessions, func(i, j int) bool { return sessions[i].SessionBlockHeight < sessions[j].SessionBlockHeight })
+	// marshal response data into amino-json
+	res, err := codec.MarshalJSONIndent(types.ModuleCdc, sessions)
+	if err != nil {
+		return nil, sdk.ErrInternal(sdk.AppendMsgToErr("failed to JSON marshal result: %s", err.Error()))
+	}
+	return res, nil
+}
+
+// "paginateClaims" - Returns the 1-indexed page of the claims (an empty result for an invalid or out of range page)
+func paginateClaims(page, limit int, claims []types.MsgClaim) types.ClaimsPage {
+	claimsLen := len(claims)
+	start, end := util.Paginate(claimsLen, page, limit, types.DefaultClaimsPageLimit)
+	if start < 0 || end < 0 {
+		claims = []types.MsgClaim{}
+	} else {
+		claims = claims[start:end]
+	}
+	if limit <= 0 {
+		limit = types.DefaultClaimsPageLimit
+	}
+	totalPages := int(math.Ceil(float64(claimsLen) / float64(limit)))
+	if totalPages < 1 {
+		totalPages = 1
+	}
+	return types.ClaimsPage{Result: claims, Total: totalPages, Page: page}
+}
+
 // "queryParameters" - Is a handler for the parameters query
 // Returns all the parameters in the module
 func queryParameters(ctx sdk.Ctx, k Keeper) ([]byte, sdk.Error) {
@@ -112,6 +574,16 @@ func queryParameters(ctx sdk.Ctx, k Keeper) ([]byte, sdk.Error) {
 	return res, nil
 }
 
+// "queryProofParameters" - Is a handler for the proof parameters query
+// Returns the params needed to compute claim maturity and expiration locally, all read at the same height
+func queryProofParameters(ctx sdk.Ctx, k Keeper) ([]byte, sdk.Error) {
+	res, err := codec.MarshalJSONIndent(types.ModuleCdc, k.GetProofParams(ctx))
+	if err != nil {
+		return nil, sdk.ErrInternal(sdk.AppendMsgToErr("could not marshal result to JSON", err.Error()))
+	}
+	return res, nil
+}
+
 // "querySupportedBlockchains" - Is a handler for the supported blockchains query
 // Returns the non native chains supported on pocket network
 func querySupportedBlockchains(ctx sdk.Ctx, _ abci.RequestQuery, k Keeper) ([]byte, sdk.Error) {
@@ -122,3 +594,114 @@ func querySupportedBlockchains(ctx sdk.Ctx, _ abci.RequestQuery, k Keeper) ([]by
 	}
 	return res, nil
 }
+
+// "queryClaimExpiration" - Is a handler for the claim expiration query
+// Returns the expiration height stored with a claim and the one it would have under the current params
+func queryClaimExpiration(ctx sdk.Ctx, req abci.RequestQuery, k Keeper) ([]byte, sdk.Error) {
+	// unmarshal data into a query params object
+	var params types.QueryClaimExpirationParams
+	err := types.ModuleCdc.UnmarshalJSON(req.Data, &params)
+	if err != nil {
+		return nil, sdk.ErrInternal(fmt.Sprintf("failed to parse params: %s", err))
+	}
+	evidenceType, er := types.EvidenceTypeFromString(params.Type)
+	if er != nil {
+		return nil, er
+	}
+	claim, found := k.GetClaim(ctx, params.Address, params.Header, evidenceType)
+	if !found {
+		return nil, types.NewClaimNotFoundError(types.ModuleName)
+	}
+	expiration := types.ClaimExpiration{
+		StoredExpirationHeight:  claim.ExpirationHeight,
+		CurrentExpirationHeight: k.ClaimExpirationHeight(ctx, claim),
+	}
+	// marshal response data into amino-json
+	res, err := codec.MarshalJSONIndent(types.ModuleCdc, expiration)
+	if err != nil {
+		return nil, sdk.ErrInternal(sdk.AppendMsgToErr("failed to JSON marshal result: %s", err.Error()))
+	}
+	return res, nil
+}
+
+// "queryClaimFeesSpent" - Is a handler for the claim fees spent query
+// Returns the fees of the auto claim and proof txs a node hosted by this process has sent since it started
+func queryClaimFeesSpent(ctx sdk.Ctx, req abci.RequestQuery, k Keeper) ([]byte, sdk.Error) {
+	// unmarshal data into a query params object
+	var params types.QueryClaimFeesSpentParams
+	err := types.ModuleCdc.UnmarshalJSON(req.Data, &params)
+	if err != nil {
+		return nil, sdk.ErrInternal(fmt.Sprintf("failed to parse params: %s", err))
+	}
+	// the fees are tracked locally, so only the nodes hosted by this process can be queried
+	if _, err := types.GetPocketNodeByAddress(&params.Address); err != nil {
+		return nil, sdk.ErrUnknownRequest(fmt.Sprintf("the node %s is not hosted by this process", params.Address.String()))
+	}
+	// marshal response data into amino-json
+	res, err := codec.MarshalJSONIndent(types.ModuleCdc, k.GetClaimFeesSpent(ctx, params.Address))
+	if err != nil {
+		return nil, sdk.ErrInternal(sdk.AppendMsgToErr("failed to JSON marshal result: %s", err.Error()))
+	}
+	return res, nil
+}
+
+// "queryImmatureClaims" - Is a handler for the immature claims query
+// Returns the claims of the address still in their waiting period with the blocks until each is mature, for countdowns
+func queryImmatureClaims(ctx sdk.Ctx, req abci.RequestQuery, k Keeper) ([]byte, sdk.Error) {
+	// unmarshal data into a query params object
+	var params types.QueryImmatureClaimsParams
+	err := types.ModuleCdc.UnmarshalJSON(req.Data, &params)
+	if err != nil {
+		return nil, sdk.ErrInternal(fmt.Sprintf("failed to parse params: %s", err))
+	}
+	claims, err := k.GetImmatureClaims(ctx, params.Address)
+	if err != nil {
+		return nil, sdk.ErrInternal(err.Error())
+	}
+	// marshal response data into amino-json
+	res, err := codec.MarshalJSONIndent(types.ModuleCdc, claims)
+	if err != nil {
+		return nil, sdk.ErrInternal(sdk.AppendMsgToErr("failed to JSON marshal result: %s", err.Error()))
+	}
+	return res, nil
+}
+
+// "queryCachedRelaysByChain" - Is a handler for the cached relays by chain query
+// Reads the evidence cache of the node, not the world state
+func queryCachedRelaysByChain(req abci.RequestQuery) ([]byte, sdk.Error) {
+	// unmarshal data into a query params object
+	var params types.QueryCachedRelaysByChainParams
+	err := types.ModuleCdc.UnmarshalJSON(req.Data, &params)
+	if err != nil {
+		return nil, sdk.ErrInternal(fmt.Sprintf("failed to parse params: %s", err))
+	}
+	// evidence is held locally, so only the nodes hosted by this process can be queried
+	node, err := types.GetPocketNodeByAddress(&params.Address)
+	if err != nil || node.EvidenceStore == nil {
+		return nil, sdk.ErrUnknownRequest(fmt.Sprintf("the node %s is not hosted by this process", params.Address.String()))
+	}
+	// marshal response data into amino-json
+	res, err := codec.MarshalJSONIndent(types.ModuleCdc, types.GetCachedRelaysByChain(node.EvidenceStore))
+	if err != nil {
+		return nil, sdk.ErrInternal(sdk.AppendMsgToErr("failed to JSON marshal result: %s", err.Error()))
+	}
+	return res, nil
+}
+
+// "queryIsClaimant" - Is a handler for the is claimant query
+func queryIsClaimant(ctx sdk.Ctx, req abci.RequestQuery, k Keeper) ([]byte, sdk.Error) {
+	// unmarshal data into a query params object
+	var params types.QueryIsClaimantParams
+	err := types.ModuleCdc.UnmarshalJSON(req.Data, &params)
+	if err != nil {
+		return nil, sdk.ErrInternal(fmt.Sprintf("failed to parse params: %s", err))
+	}
+	claimant := types.Claimant{IsClaimant: k.IsClaimant(ctx, params.Address, params.Header)}
+	claimant.Mature = claimant.IsClaimant && k.ClaimIsMature(ctx, params.Header.SessionBlockHeight)
+	// marshal response data into amino-json
+	res, err := codec.MarshalJSONIndent(types.ModuleCdc, claimant)
+	if err != nil {
+		return nil, sdk.ErrInternal(sdk.AppendMsgToErr("failed to JSON marshal result: %s", err.Error()))
+	}
+	return res, nil
+}
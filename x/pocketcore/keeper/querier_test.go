@@ -1,10 +1,16 @@
 package keeper
 
 import (
+	"encoding/hex"
 	"testing"
 
+	"github.com/pokt-network/pocket-core/codec"
+	storeTypes "github.com/pokt-network/pocket-core/store/types"
+	sdk "github.com/pokt-network/pocket-core/types"
+	appsTypes "github.com/pokt-network/pocket-core/x/apps/types"
 	"github.com/pokt-network/pocket-core/x/pocketcore/types"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
 	abci "github.com/tendermint/tendermint/abci/types"
 )
 
@@ -35,3 +41,751 @@ func TestQueryParameters(t *testing.T) {
 	assert.Nil(t, er)
 	assert.Equal(t, params, p)
 }
+
+func TestQueryProofParameters(t *testing.T) {
+	ctx, _, _, _, k, _, _ := createTestInput(t, false)
+	ctx = ctx.WithBlockHeight(10)
+	p := types.DefaultParams()
+	p.ClaimSubmissionWindow = 4
+	p.ClaimExpiration = 30
+	p.MinimumNumberOfProofs = 7
+	p.MaxClaimsDeletedPerBlock = 100
+	p.MaxProofsPerAddressPerBlock = 5
+	k.SetParams(ctx, p)
+	bz, err := queryProofParameters(ctx, k)
+	assert.Nil(t, err)
+	var params types.ProofParams
+	er := makeTestCodec().UnmarshalJSON(bz, &params)
+	assert.Nil(t, er)
+	assert.Equal(t, types.ProofParams{
+		BlocksPerSession:            k.BlocksPerSession(ctx),
+		ClaimSubmissionWindow:       4,
+		ClaimExpiration:             30,
+		MinimumNumberOfProofs:       7,
+		MaxClaimsDeletedPerBlock:    100,
+		MaxProofsPerAddressPerBlock: 5,
+	}, params)
+	assert.NotZero(t, params.BlocksPerSession)
+}
+
+func TestQueryRelaysByChain(t *testing.T) {
+	ctx, _, _, _, k, _, _ := createTestInput(t, false)
+	addr := getRandomValidatorAddress()
+	k.SetClaims(ctx, []types.MsgClaim{createTestClaim(addr, "0001", 1, 10), createTestClaim(addr, "0002", 1, 5)})
+	bz, err := queryRelaysByChain(ctx, k)
+	assert.Nil(t, err)
+	var relays map[string]int64
+	er := makeTestCodec().UnmarshalJSON(bz, &relays)
+	assert.Nil(t, er)
+	assert.Equal(t, map[string]int64{"0001": 10, "0002": 5}, relays)
+}
+
+func TestQueryEstimateClaimReward(t *testing.T) {
+	ctx, vals, _, _, k, _, _ := createTestInput(t, false)
+	params := types.QueryEstimateClaimRewardParams{
+		Address:     vals[0].Address,
+		TotalRelays: 100,
+	}
+	bz, er := makeTestCodec().MarshalJSON(params)
+	assert.Nil(t, er)
+	res, err := queryEstimateClaimReward(ctx, abci.RequestQuery{Data: bz}, k)
+	assert.Nil(t, err)
+	var reward sdk.Coin
+	er = makeTestCodec().UnmarshalJSON(res, &reward)
+	assert.Nil(t, er)
+	assert.Equal(t, k.EstimateClaimReward(ctx, vals[0].Address, "", 100), reward)
+}
+
+func TestQueryRequiredProof(t *testing.T) {
+	ctx, _, _, _, k, keys, _ := createTestInput(t, false)
+	addr := getRandomValidatorAddress()
+	claim := createTestClaim(addr, "0001", 1, 10)
+	mockCtx := new(Ctx)
+	mockCtx.On("KVStore", k.storeKey).Return(ctx.KVStore(k.storeKey))
+	mockCtx.On("KVStore", keys[sdk.ParamsKey.Name()]).Return(ctx.KVStore(keys[sdk.ParamsKey.Name()]))
+	mockCtx.On("BlockHeight").Return(ctx.BlockHeight())
+	mockCtx.On("Logger").Return(ctx.Logger())
+	mockCtx.On("PrevCtx", claim.SessionHeader.SessionBlockHeight).Return(ctx, nil)
+	mockCtx.On("GetPrevBlockHash", int64(76)).Return(ctx.BlockHeader().LastBlockId.Hash, nil)
+	_, err := k.SetClaim(mockCtx, claim)
+	assert.Nil(t, err)
+	params := types.QueryRequiredProofParams{
+		Address: addr,
+		Header:  claim.SessionHeader,
+		Type:    "relay",
+	}
+	bz, er := makeTestCodec().MarshalJSON(params)
+	assert.Nil(t, er)
+	res, err := queryRequiredProof(mockCtx, abci.RequestQuery{Data: bz}, k)
+	assert.Nil(t, err)
+	var requiredProof types.RequiredProof
+	er = makeTestCodec().UnmarshalJSON(res, &requiredProof)
+	assert.Nil(t, er)
+	index, er := k.getPseudorandomIndex(mockCtx, claim.TotalProofs, claim.SessionHeader, ctx)
+	assert.Nil(t, er)
+	assert.Equal(t, index, requiredProof.Index)
+	assert.Equal(t, 4, requiredProof.LevelCount)
+	// no claim for a different address
+	params.Address = getRandomValidatorAddress()
+	bz, er = makeTestCodec().MarshalJSON(params)
+	assert.Nil(t, er)
+	_, err = queryRequiredProof(mockCtx, abci.RequestQuery{Data: bz}, k)
+	assert.NotNil(t, err)
+}
+
+func TestQueryProofBacklog(t *testing.T) {
+	ctx, _, _, _, k, _, _ := createTestInput(t, false)
+	node := newTestPocketNode(t)
+	addr := node.GetAddress()
+	types.GlobalPocketNodes[addr.String()] = node
+	t.Cleanup(func() { delete(types.GlobalPocketNodes, addr.String()) })
+	clientKey := getRandomPrivateKey()
+	addEvidence := func(sessionBlockHeight int64) types.SessionHeader {
+		header := types.SessionHeader{
+			ApplicationPubKey:  getRandomPubKey().RawString(),
+			Chain:              "0001",
+			SessionBlockHeight: sessionBlockHeight,
+		}
+		proof := createProof(getTestApplicationPrivateKey(), clientKey, node.PrivateKey.PublicKey(), "0001", 0)
+		types.SetProof(header, types.RelayEvidence, proof, sdk.NewInt(100000), node.EvidenceStore)
+		return header
+	}
+	// awaiting its claim
+	addEvidence(ctx.BlockHeight() - 1)
+	// claimed and in its waiting period
+	immature := createTestClaim(addr, "0001", ctx.BlockHeight()-1, 10)
+	immature.SessionHeader = addEvidence(ctx.BlockHeight() - 1)
+	// mature and awaiting the proof
+	claims := []types.MsgClaim{immature, createTestClaim(addr, "0001", 1, 10), createTestClaim(addr, "0001", 1, 20)}
+	k.SetClaims(ctx, claims)
+	// another address's claim
+	k.SetClaims(ctx, []types.MsgClaim{createTestClaim(getRandomValidatorAddress(), "0001", 1, 10)})
+	bz, er := makeTestCodec().MarshalJSON(types.QueryProofBacklogParams{Address: addr})
+	assert.Nil(t, er)
+	res, err := queryProofBacklog(ctx, abci.RequestQuery{Data: bz}, k)
+	assert.Nil(t, err)
+	var backlog types.ProofBacklog
+	er = makeTestCodec().UnmarshalJSON(res, &backlog)
+	assert.Nil(t, er)
+	assert.Equal(t, types.ProofBacklog{PendingClaims: 1, ImmatureClaims: 1, MatureUnproven: 2}, backlog)
+	// the evidence of an address not hosted here is unknown
+	delete(types.GlobalPocketNodes, addr.String())
+	backlog, er = k.GetProofBacklog(ctx, addr)
+	assert.Nil(t, er)
+	assert.Equal(t, types.ProofBacklog{PendingClaims: 0, ImmatureClaims: 1, MatureUnproven: 2}, backlog)
+}
+
+func TestQueryExpiringClaims(t *testing.T) {
+	ctx, _, _, _, k, _, _ := createTestInput(t, false)
+	addr := getRandomValidatorAddress()
+	blocksPerSession := k.BlocksPerSession(ctx)
+	// expires in exactly one session, in one session and a block, and in two sessions
+	var claims []types.MsgClaim
+	for _, blocks := range []int64{blocksPerSession, blocksPerSession + 1, 2 * blocksPerSession} {
+		claim := createTestClaim(addr, "0001", 1, 10)
+		claim.ExpirationHeight = ctx.BlockHeight() + blocks
+		claims = append(claims, claim)
+	}
+	k.SetClaims(ctx, claims)
+	// another address's claim
+	k.SetClaims(ctx, []types.MsgClaim{createTestClaim(getRandomValidatorAddress(), "0001", 1, 10)})
+	query := func(within int64) (expiring []types.MsgClaim, err sdk.Error) {
+		bz, er := makeTestCodec().MarshalJSON(types.QueryExpiringClaimsParams{Address: addr, Within: within})
+		assert.Nil(t, er)
+		res, err := queryExpiringClaims(ctx, abci.RequestQuery{Data: bz}, k)
+		if err != nil {
+			return nil, err
+		}
+		assert.Nil(t, makeTestCodec().UnmarshalJSON(res, &expiring))
+		return
+	}
+	expiring, err := query(0)
+	assert.Nil(t, err)
+	assert.Empty(t, expiring)
+	// the claim expiring on the boundary block is included
+	expiring, err = query(1)
+	assert.Nil(t, err)
+	assert.Len(t, expiring, 1)
+	assert.Equal(t, claims[0].ExpirationHeight, expiring[0].ExpirationHeight)
+	expiring, err = query(2)
+	assert.Nil(t, err)
+	assert.Len(t, expiring, 3)
+	_, err = query(-1)
+	assert.NotNil(t, err)
+}
+
+func TestQueryClaimsInHeightRange(t *testing.T) {
+	ctx, _, _, _, k, _, _ := createTestInput(t, false)
+	addr := getRandomValidatorAddress()
+	blocksPerSession := k.BlocksPerSession(ctx)
+	// a claim in each of five sessions
+	for i := int64(0); i < 5; i++ {
+		k.SetClaims(ctx, []types.MsgClaim{createTestClaim(addr, "0001", i*blocksPerSession+1, 10)})
+	}
+	// another address's claim in range
+	k.SetClaims(ctx, []types.MsgClaim{createTestClaim(getRandomValidatorAddress(), "0001", blocksPerSession+1, 10)})
+	query := func(start, end int64) (claims []types.MsgClaim, err sdk.Error) {
+		bz, er := makeTestCodec().MarshalJSON(types.QueryClaimsInHeightRangeParams{Address: addr, StartHeight: start, EndHeight: end})
+		assert.Nil(t, er)
+		res, err := queryClaimsInHeightRange(ctx, abci.RequestQuery{Data: bz}, k)
+		if err != nil {
+			return nil, err
+		}
+		assert.Nil(t, makeTestCodec().UnmarshalJSON(res, &claims))
+		return
+	}
+	// both bounds are inclusive
+	claims, err := query(blocksPerSession+1, 3*blocksPerSession+1)
+	assert.Nil(t, err)
+	assert.Len(t, claims, 3)
+	for _, claim := range claims {
+		assert.Equal(t, addr, claim.FromAddress)
+		assert.True(t, claim.SessionHeader.SessionBlockHeight >= blocksPerSession+1 && claim.SessionHeader.SessionBlockHeight <= 3*blocksPerSession+1)
+	}
+	// a single session
+	claims, err = query(1, 1)
+	assert.Nil(t, err)
+	assert.Len(t, claims, 1)
+	// no session in range
+	claims, err = query(2, blocksPerSession)
+	assert.Nil(t, err)
+	assert.Empty(t, claims)
+	// an inverted range
+	_, err = query(3*blocksPerSession+1, 1)
+	assert.NotNil(t, err)
+	_, er := k.GetClaimsInHeightRange(ctx, addr, 2, 1)
+	assert.NotNil(t, er)
+}
+
+func TestQueryServedChains(t *testing.T) {
+	ctx, _, _, _, k, _, _ := createTestInput(t, false)
+	addr := getRandomValidatorAddress()
+	query := func() (chains []string) {
+		bz, er := makeTestCodec().MarshalJSON(types.QueryServedChainsParams{Address: addr})
+		assert.Nil(t, er)
+		res, err := queryServedChains(ctx, abci.RequestQuery{Data: bz}, k)
+		assert.Nil(t, err)
+		assert.Nil(t, makeTestCodec().UnmarshalJSON(res, &chains))
+		return
+	}
+	// no claims
+	chains, err := k.GetServedChains(ctx, addr)
+	assert.Nil(t, err)
+	assert.NotNil(t, chains)
+	assert.Empty(t, chains)
+	assert.Empty(t, query())
+	// claims across overlapping chains and sessions
+	for i, chain := range []string{"0021", "0001", "0021", "0005", "0001"} {
+		k.SetClaims(ctx, []types.MsgClaim{createTestClaim(addr, chain, int64(i+1), 10)})
+	}
+	// another address's chain
+	k.SetClaims(ctx, []types.MsgClaim{createTestClaim(getRandomValidatorAddress(), "0009", 1, 10)})
+	assert.Equal(t, []string{"0001", "0005", "0021"}, query())
+}
+
+func TestQueryProofByHash(t *testing.T) {
+	ctx, _, _, _, k, _, _ := createTestInput(t, false)
+	ethereum := hex.EncodeToString([]byte{01})
+	header := types.SessionHeader{
+		ApplicationPubKey:  getTestApplication().PublicKey.RawString(),
+		Chain:              ethereum,
+		SessionBlockHeight: 1,
+	}
+	node := newTestPocketNode(t)
+	types.GlobalPocketNodes[node.GetAddress().String()] = node
+	t.Cleanup(func() { delete(types.GlobalPocketNodes, node.GetAddress().String()) })
+	proof := createProof(getTestApplicationPrivateKey(), getRandomPrivateKey(), node.PrivateKey.PublicKey(), ethereum, 0)
+	types.SetProof(header, types.RelayEvidence, proof, sdk.NewInt(100000), node.EvidenceStore)
+	query := func(address sdk.Address, hash string) (location types.ProofLocation, err sdk.Error) {
+		bz, er := makeTestCodec().MarshalJSON(types.QueryProofByHashParams{Address: address, Header: header, Type: "relay", Hash: hash})
+		assert.Nil(t, er)
+		res, err := queryProofByHash(ctx, abci.RequestQuery{Data: bz}, k)
+		if err != nil {
+			return location, err
+		}
+		assert.Nil(t, makeTestCodec().UnmarshalJSON(res, &location))
+		return
+	}
+	location, err := query(node.GetAddress(), proof.HashString())
+	assert.Nil(t, err)
+	assert.Equal(t, types.ProofLocation{Index: 0, Found: true}, location)
+	location, err = query(node.GetAddress(), hex.EncodeToString(types.Hash([]byte("bogus"))))
+	assert.Nil(t, err)
+	assert.False(t, location.Found)
+	_, err = query(node.GetAddress(), "not hex")
+	assert.NotNil(t, err)
+	// a node not hosted by this process
+	_, err = query(getRandomValidatorAddress(), proof.HashString())
+	assert.NotNil(t, err)
+}
+
+func TestQueryClaimTimeline(t *testing.T) {
+	ctx, _, _, _, k, _, _ := createTestInput(t, false)
+	addr := getRandomValidatorAddress()
+	blocksPerSession := k.BlocksPerSession(ctx)
+	window := k.ClaimSubmissionWindow(ctx) * blocksPerSession
+	expiration := k.ClaimExpiration(ctx) * blocksPerSession
+	query := func(ctx sdk.Ctx, header types.SessionHeader) (timeline types.ClaimTimeline) {
+		bz, er := makeTestCodec().MarshalJSON(types.QueryClaimTimelineParams{Address: addr, Header: header, Type: "relay"})
+		assert.Nil(t, er)
+		res, err := queryClaimTimeline(ctx, abci.RequestQuery{Data: bz}, k)
+		assert.Nil(t, err)
+		assert.Nil(t, makeTestCodec().UnmarshalJSON(res, &timeline))
+		return
+	}
+	header := types.SessionHeader{ApplicationPubKey: getRandomPubKey().RawString(), Chain: "0001", SessionBlockHeight: 1}
+	// the session is ongoing, so the claim is projected from the end of the session
+	assert.Equal(t, types.ClaimTimeline{
+		ClaimableNow:     false,
+		ProvableAtHeight: 1 + window + 1,
+		ExpiresAtHeight:  1 + blocksPerSession + expiration,
+	}, query(ctx.WithBlockHeight(1), header))
+	// the session is over, so it can be claimed now
+	height := 1 + blocksPerSession + 1
+	assert.Equal(t, types.ClaimTimeline{
+		ClaimableNow:     true,
+		ProvableAtHeight: 1 + window + 1,
+		ExpiresAtHeight:  height + expiration,
+	}, query(ctx.WithBlockHeight(height), header))
+	// too late to claim once it would be mature
+	assert.False(t, query(ctx.WithBlockHeight(1+window+1), header).ClaimableNow)
+	// a stored claim reports its own expiration height
+	claim := createTestClaim(addr, "0001", 1, 10)
+	claim.SessionHeader = header
+	k.SetClaims(ctx, []types.MsgClaim{claim})
+	assert.Equal(t, types.ClaimTimeline{
+		ClaimableNow:     false,
+		ProvableAtHeight: 1 + window + 1,
+		ExpiresAtHeight:  claim.ExpirationHeight,
+	}, query(ctx.WithBlockHeight(height), header))
+	_, err := queryClaimTimeline(ctx, abci.RequestQuery{Data: []byte("{}")}, k)
+	assert.NotNil(t, err)
+}
+
+func TestQueryClaimRoot(t *testing.T) {
+	ctx, _, _, _, k, _, _ := createTestInput(t, false)
+	addr := getRandomValidatorAddress()
+	claim := createTestClaim(addr, "0001", 1, 10)
+	k.SetClaims(ctx, []types.MsgClaim{claim})
+	query := func(header types.SessionHeader) ([]byte, sdk.Error) {
+		bz, er := makeTestCodec().MarshalJSON(types.QueryClaimRootParams{Address: addr, Header: header, Type: "relay"})
+		assert.Nil(t, er)
+		return queryClaimRoot(ctx, abci.RequestQuery{Data: bz}, k)
+	}
+	// a stored claim returns only its root
+	res, err := query(claim.SessionHeader)
+	assert.Nil(t, err)
+	var root types.HashRange
+	assert.Nil(t, makeTestCodec().UnmarshalJSON(res, &root))
+	assert.Equal(t, claim.MerkleRoot, root)
+	// an absent claim is not found
+	absent := claim.SessionHeader
+	absent.Chain = "0002"
+	_, err = query(absent)
+	assert.NotNil(t, err)
+	assert.Equal(t, sdk.CodeType(types.CodeClaimNotFoundError), err.Code())
+}
+
+func TestQueryValidateProof(t *testing.T) {
+	maxRelays := int64(5)
+	ctx, _, _, _, k, keys, _ := createTestInput(t, false)
+	types.ClearEvidence(types.GlobalEvidenceCache)
+	npk, header, _ := simulateRelays(t, k, &ctx, 8)
+	evidence, err := types.GetEvidence(header, types.RelayEvidence, sdk.NewInt(1000), types.GlobalEvidenceCache)
+	assert.Nil(t, err)
+	mockCtx := &Ctx{}
+	mockCtx.On("KVStore", k.storeKey).Return(ctx.KVStore(k.storeKey))
+	mockCtx.On("KVStore", keys[sdk.ParamsKey.Name()]).Return(ctx.KVStore(keys[sdk.ParamsKey.Name()]))
+	mockCtx.On("KVStore", keys[appsTypes.StoreKey]).Return(ctx.KVStore(keys[appsTypes.StoreKey]))
+	mockCtx.On("Logger").Return(ctx.Logger())
+	mockCtx.On("BlockHeight").Return(ctx.BlockHeight())
+	mockCtx.On("PrevCtx", header.SessionBlockHeight).Return(ctx, nil)
+	mockCtx.On("GetPrevBlockHash", int64(76)).Return(ctx.BlockHeader().LastBlockId.Hash, nil)
+	_, err = k.SetClaim(mockCtx, types.MsgClaim{
+		SessionHeader: header,
+		MerkleRoot:    evidence.GenerateMerkleRoot(0, maxRelays, types.GlobalEvidenceCache),
+		TotalProofs:   maxRelays,
+		FromAddress:   sdk.Address(npk.Address()),
+		EvidenceType:  types.RelayEvidence,
+	})
+	assert.Nil(t, err)
+	index, er := k.getPseudorandomIndex(mockCtx, maxRelays, header, mockCtx)
+	assert.Nil(t, er)
+	merkleProof, leaf := evidence.GenerateMerkleProof(0, int(index), maxRelays)
+	proof := types.MsgProof{MerkleProof: merkleProof, Leaf: leaf, EvidenceType: types.RelayEvidence}
+	query := func(proof types.MsgProof) (validation types.ProofValidation) {
+		// the leaf is an interface, so the params are marshalled with the module codec that registers it
+		bz, er := types.ModuleCdc.MarshalJSON(types.QueryValidateProofParams{Proof: proof})
+		assert.Nil(t, er)
+		res, err := queryValidateProof(mockCtx, abci.RequestQuery{Data: bz}, k)
+		assert.Nil(t, err)
+		assert.Nil(t, makeTestCodec().UnmarshalJSON(res, &validation))
+		return
+	}
+	// the proof of the stored claim validates
+	assert.Equal(t, types.ProofValidation{Valid: true, Servicer: sdk.Address(npk.Address())}, query(proof))
+	// a tampered proof returns the error the proof tx would fail with, without counting as a rejected proof
+	failures := proofFailureCount(t, types.ProofFailureMerkleMismatch)
+	tampered := proof
+	tampered.MerkleProof.HashRanges = make([]types.HashRange, len(proof.MerkleProof.HashRanges))
+	copy(tampered.MerkleProof.HashRanges, proof.MerkleProof.HashRanges)
+	tampered.MerkleProof.HashRanges[0].Hash = types.Hash([]byte("not the sibling"))
+	validation := query(tampered)
+	assert.False(t, validation.Valid)
+	assert.Equal(t, sdk.CodeType(types.CodeInvalidMerkleVerifyError), validation.Code)
+	assert.Equal(t, sdk.CodespaceType(types.ModuleName), validation.Codespace)
+	assert.Equal(t, failures, proofFailureCount(t, types.ProofFailureMerkleMismatch))
+	_, err = queryValidateProof(mockCtx, abci.RequestQuery{Data: []byte("{")}, k)
+	assert.NotNil(t, err)
+}
+
+func TestQueryVerifiedRelays(t *testing.T) {
+	ctx, vals, _, _, k, _, _ := createTestInput(t, false)
+	codec.UpgradeFeatureMap[codec.VerifiedRelaysKey] = 1
+	t.Cleanup(func() { delete(codec.UpgradeFeatureMap, codec.VerifiedRelaysKey) })
+	addr := vals[0].Address
+	claim := createTestClaim(addr, "0001", 1, 10)
+	_, err := k.SetClaim(ctx, claim)
+	assert.Nil(t, err)
+	_, er := k.ExecuteProof(ctx, types.MsgProof{Leaf: types.RelayProof{}, EvidenceType: types.RelayEvidence}, claim)
+	assert.Nil(t, er)
+	// a zero height queries the latest state
+	bz, e := makeTestCodec().MarshalJSON(types.QueryVerifiedRelaysParams{Address: addr})
+	assert.Nil(t, e)
+	res, er := queryVerifiedRelays(ctx, abci.RequestQuery{Data: bz}, k)
+	assert.Nil(t, er)
+	var total int64
+	assert.Nil(t, makeTestCodec().UnmarshalJSON(res, &total))
+	assert.Equal(t, int64(10), total)
+}
+
+func TestQueryClaimsByChain(t *testing.T) {
+	ctx, _, _, _, k, _, _ := createTestInput(t, false)
+	query := func(chain string, page, limit int) (claimsPage types.ClaimsPage) {
+		bz, er := makeTestCodec().MarshalJSON(types.QueryClaimsByChainParams{Chain: chain, Page: page, Limit: limit})
+		assert.Nil(t, er)
+		res, err := queryClaimsByChain(ctx, abci.RequestQuery{Data: bz}, k)
+		assert.Nil(t, err)
+		assert.Nil(t, makeTestCodec().UnmarshalJSON(res, &claimsPage))
+		return
+	}
+	// claims across chains and addresses
+	addrs := []sdk.Address{getRandomValidatorAddress(), getRandomValidatorAddress(), getRandomValidatorAddress()}
+	for i, addr := range addrs {
+		k.SetClaims(ctx, []types.MsgClaim{
+			createTestClaim(addr, "0001", int64(i+1), 10),
+			createTestClaim(addr, "0021", int64(i+1), 10),
+		})
+	}
+	k.SetClaims(ctx, []types.MsgClaim{createTestClaim(addrs[0], "0001", 5, 10)})
+	claims := k.GetClaimsByChainAll(ctx, "0001")
+	assert.Len(t, claims, 4)
+	for _, claim := range claims {
+		assert.Equal(t, "0001", claim.SessionHeader.Chain)
+	}
+	assert.Len(t, k.GetClaimsByChainAll(ctx, "0021"), 3)
+	// an unknown chain
+	assert.NotNil(t, k.GetClaimsByChainAll(ctx, "9999"))
+	assert.Empty(t, k.GetClaimsByChainAll(ctx, "9999"))
+	unknown := query("9999", 1, 0)
+	assert.Empty(t, unknown.Result)
+	assert.Equal(t, 1, unknown.Total)
+	// pagination
+	first, second := query("0001", 1, 3), query("0001", 2, 3)
+	assert.Len(t, first.Result, 3)
+	assert.Len(t, second.Result, 1)
+	assert.Equal(t, 2, first.Total)
+	assert.Equal(t, 2, second.Page)
+	assert.ElementsMatch(t, claims, append(first.Result, second.Result...))
+	assert.Empty(t, query("0001", 3, 3).Result)
+	assert.Len(t, query("0001", 1, 0).Result, 4)
+}
+
+func TestKeeper_GetProofModuleStats(t *testing.T) {
+	ctx, _, _, _, k, _, _ := createTestInput(t, false)
+	assert.Equal(t, types.ProofModuleStats{Height: ctx.BlockHeight()}, k.GetProofModuleStats(ctx))
+	addrs := []sdk.Address{getRandomValidatorAddress(), getRandomValidatorAddress(), getRandomValidatorAddress()}
+	challenge := createTestClaim(addrs[2], "0040", 1, 1)
+	challenge.EvidenceType = types.ChallengeEvidence
+	k.SetClaims(ctx, []types.MsgClaim{
+		createTestClaim(addrs[0], "0001", 1, 10),
+		createTestClaim(addrs[0], "0021", 1, 20),
+		createTestClaim(addrs[1], "0001", 1, 30),
+		challenge,
+	})
+	// a servicer with verified relays only, and one with both
+	verified := getRandomValidatorAddress()
+	k.addVerifiedRelays(ctx, verified, 7)
+	k.addVerifiedRelays(ctx, addrs[1], 5)
+	stats := k.GetProofModuleStats(ctx)
+	assert.Equal(t, ctx.BlockHeight(), stats.Height)
+	assert.Equal(t, int64(4), stats.TotalClaims)
+	assert.Equal(t, int64(60), stats.TotalRelays) // the challenge claim is not relays
+	assert.Equal(t, int64(12), stats.TotalVerifiedRelays)
+	assert.Equal(t, int64(4), stats.DistinctServicers)
+	assert.Equal(t, int64(3), stats.DistinctChains)
+	// the query is cached per block
+	query := func(ctx sdk.Ctx) (stats types.ProofModuleStats) {
+		res, err := queryProofModuleStats(ctx, k)
+		assert.Nil(t, err)
+		assert.Nil(t, makeTestCodec().UnmarshalJSON(res, &stats))
+		return
+	}
+	assert.Equal(t, stats, query(ctx))
+	k.SetClaims(ctx, []types.MsgClaim{createTestClaim(addrs[2], "0002", 1, 1)})
+	assert.Equal(t, stats, query(ctx))
+	next := query(ctx.WithBlockHeight(ctx.BlockHeight() + 1))
+	assert.Equal(t, int64(5), next.TotalClaims)
+	assert.Equal(t, int64(4), next.DistinctChains)
+}
+
+func TestQueryMerklePath(t *testing.T) {
+	ctx, _, _, _, k, _, _ := createTestInput(t, false)
+	ethereum := hex.EncodeToString([]byte{01})
+	header := types.SessionHeader{
+		ApplicationPubKey:  getTestApplication().PublicKey.RawString(),
+		Chain:              ethereum,
+		SessionBlockHeight: 1,
+	}
+	node := newTestPocketNode(t)
+	types.GlobalPocketNodes[node.GetAddress().String()] = node
+	t.Cleanup(func() { delete(types.GlobalPocketNodes, node.GetAddress().String()) })
+	clientKey := getRandomPrivateKey()
+	totalProofs := 7
+	for i := 0; i < totalProofs; i++ {
+		types.SetProof(header, types.RelayEvidence, createProof(getTestApplicationPrivateKey(), clientKey, node.PrivateKey.PublicKey(), ethereum, i), sdk.NewInt(100000), node.EvidenceStore)
+	}
+	evidence, er := types.GetEvidence(header, types.RelayEvidence, sdk.ZeroInt(), node.EvidenceStore)
+	assert.Nil(t, er)
+	claim := createTestClaim(node.GetAddress(), ethereum, 1, int64(totalProofs))
+	claim.SessionHeader = header
+	claim.MerkleRoot = evidence.GenerateMerkleRoot(header.SessionBlockHeight, int64(totalProofs), node.EvidenceStore)
+	_, err := k.SetClaim(ctx, claim)
+	assert.Nil(t, err)
+	query := func(address sdk.Address, index int64) (path types.MerklePath, err sdk.Error) {
+		bz, er := makeTestCodec().MarshalJSON(types.QueryMerklePathParams{Address: address, Header: header, Type: "relay", Index: index})
+		assert.Nil(t, er)
+		res, err := queryMerklePath(ctx, abci.RequestQuery{Data: bz}, k)
+		if err != nil {
+			return path, err
+		}
+		assert.Nil(t, makeTestCodec().UnmarshalJSON(res, &path))
+		return
+	}
+	for index := int64(0); index < int64(totalProofs); index++ {
+		path, err := query(node.GetAddress(), index)
+		assert.Nil(t, err)
+		assert.Equal(t, index, path.Index)
+		assert.Equal(t, claim.MerkleRoot, path.Root)
+		assert.False(t, path.SortedPair)
+		assert.Len(t, path.Path, 3)
+		// the path reconstructs the stored root from the leaf
+		_, leaf, er := evidence.GenerateMerkleProofForIndex(header.SessionBlockHeight, int(index), int64(totalProofs))
+		assert.Nil(t, er)
+		mProof := types.MerkleProof{TargetIndex: path.Index, Target: path.Target, HashRanges: path.Path}
+		isValid, _ := mProof.Validate(header.SessionBlockHeight, path.Root, leaf, len(path.Path))
+		assert.True(t, isValid, "the path of leaf %d should reconstruct the root", index)
+	}
+	// out of range
+	_, err = query(node.GetAddress(), int64(totalProofs))
+	assert.NotNil(t, err)
+	// no claim for the session
+	other := newTestPocketNode(t)
+	types.GlobalPocketNodes[other.GetAddress().String()] = other
+	t.Cleanup(func() { delete(types.GlobalPocketNodes, other.GetAddress().String()) })
+	_, err = query(other.GetAddress(), 0)
+	assert.NotNil(t, err)
+	// a node not hosted by this process
+	_, err = query(getRandomValidatorAddress(), 0)
+	assert.NotNil(t, err)
+}
+
+func TestQueryClaimsBySession(t *testing.T) {
+	ctx, _, _, _, k, _, _ := createTestInput(t, false)
+	addr, other := getRandomValidatorAddress(), getRandomValidatorAddress()
+	query := func(address sdk.Address) (sessions []types.SessionClaims) {
+		bz, er := makeTestCodec().MarshalJSON(types.QueryClaimsBySessionParams{Address: address})
+		assert.Nil(t, er)
+		res, err := queryClaimsBySession(ctx, abci.RequestQuery{Data: bz}, k)
+		assert.Nil(t, err)
+		assert.Nil(t, makeTestCodec().UnmarshalJSON(res, &sessions))
+		return
+	}
+	// no claims
+	bySession, err := k.GetClaimsBySession(ctx, addr)
+	assert.Nil(t, err)
+	assert.NotNil(t, bySession)
+	assert.Empty(t, bySession)
+	assert.Empty(t, query(addr))
+	k.SetClaims(ctx, []types.MsgClaim{
+		createTestClaim(addr, "0001", 5, 10),
+		createTestClaim(addr, "0001", 1, 10),
+		createTestClaim(addr, "0021", 1, 10),
+		createTestClaim(other, "0001", 1, 10),
+	})
+	bySession, err = k.GetClaimsBySession(ctx, addr)
+	assert.Nil(t, err)
+	assert.Len(t, bySession, 2)
+	assert.Len(t, bySession[1], 2)
+	assert.Len(t, bySession[5], 1)
+	for height, claims := range bySession {
+		for _, claim := range claims {
+			assert.Equal(t, height, claim.SessionHeader.SessionBlockHeight)
+			assert.Equal(t, addr, claim.FromAddress)
+		}
+	}
+	// ordered by session height
+	sessions := query(addr)
+	assert.Len(t, sessions, 2)
+	assert.Equal(t, int64(1), sessions[0].SessionBlockHeight)
+	assert.ElementsMatch(t, bySession[1], sessions[0].Claims)
+	assert.Equal(t, int64(5), sessions[1].SessionBlockHeight)
+	assert.Equal(t, bySession[5], sessions[1].Claims)
+}
+
+func TestQueryClaimExpiration(t *testing.T) {
+	ctx, _, _, _, k, _, _ := createTestInput(t, false)
+	claim := createTestClaim(getRandomValidatorAddress(), "0001", 1, 10)
+	claim.ExpirationHeight = 0
+	p := k.GetParams(ctx)
+	p.ClaimExpiration = 10
+	k.SetParams(ctx, p)
+	sessionCtx, _ := ctx.CacheContext()
+	k.SetParams(sessionCtx, p)
+	mockCtx := &Ctx{}
+	mockCtx.On("KVStore", mock.Anything).Return(func(key storeTypes.StoreKey) storeTypes.KVStore { return ctx.KVStore(key) })
+	mockCtx.On("BlockHeight").Return(ctx.BlockHeight())
+	mockCtx.On("PrevCtx", claim.SessionHeader.SessionBlockHeight).Return(sessionCtx, nil)
+	mockCtx.On("Logger").Return(ctx.Logger())
+	_, e := k.SetClaim(mockCtx, claim)
+	assert.Nil(t, e)
+	stored, found := k.GetClaim(ctx, claim.FromAddress, claim.SessionHeader, claim.EvidenceType)
+	assert.True(t, found)
+	blocksPerSession := k.BlocksPerSession(ctx)
+	query := func(params types.QueryClaimExpirationParams) (expiration types.ClaimExpiration, err sdk.Error) {
+		bz, er := makeTestCodec().MarshalJSON(params)
+		assert.Nil(t, er)
+		res, err := queryClaimExpiration(mockCtx, abci.RequestQuery{Data: bz}, k)
+		if err != nil {
+			return
+		}
+		assert.Nil(t, makeTestCodec().UnmarshalJSON(res, &expiration))
+		return
+	}
+	params := types.QueryClaimExpirationParams{Address: claim.FromAddress, Header: claim.SessionHeader, Type: "relay"}
+	// before a param change both heights agree
+	expiration, err := query(params)
+	assert.Nil(t, err)
+	assert.Equal(t, types.ClaimExpiration{StoredExpirationHeight: stored.ExpirationHeight, CurrentExpirationHeight: stored.ExpirationHeight}, expiration)
+	// governance raises ClaimExpiration: the stored height is fixed, the recomputed one follows the current params
+	p.ClaimExpiration = 100
+	k.SetParams(ctx, p)
+	expiration, err = query(params)
+	assert.Nil(t, err)
+	assert.Equal(t, stored.ExpirationHeight, expiration.StoredExpirationHeight)
+	assert.Equal(t, stored.ExpirationHeight+90*blocksPerSession, expiration.CurrentExpirationHeight)
+	assert.Equal(t, expiration.CurrentExpirationHeight, k.ClaimExpirationHeight(mockCtx, stored))
+	// an absent claim is not found
+	params.Header.Chain = "0002"
+	_, err = query(params)
+	assert.NotNil(t, err)
+	assert.Equal(t, sdk.CodeType(types.CodeClaimNotFoundError), err.Code())
+}
+
+func TestQueryImmatureClaims(t *testing.T) {
+	ctx, _, _, _, k, _, _ := createTestInput(t, false)
+	addr := getRandomValidatorAddress()
+	waitingPeriod := k.ClaimSubmissionWindow(ctx) * k.BlocksPerSession(ctx)
+	// a mature claim and two immature ones, the later session first
+	mature := createTestClaim(addr, "0001", ctx.BlockHeight()-waitingPeriod-1, 10)
+	later := createTestClaim(addr, "0001", ctx.BlockHeight(), 10)
+	earlier := createTestClaim(addr, "0001", ctx.BlockHeight()-k.BlocksPerSession(ctx), 10)
+	k.SetClaims(ctx, []types.MsgClaim{mature, later, earlier})
+	bz, er := makeTestCodec().MarshalJSON(types.QueryImmatureClaimsParams{Address: addr})
+	assert.Nil(t, er)
+	res, err := queryImmatureClaims(ctx, abci.RequestQuery{Data: bz}, k)
+	assert.Nil(t, err)
+	var immature []types.ImmatureClaim
+	assert.Nil(t, makeTestCodec().UnmarshalJSON(res, &immature))
+	// a claim is mature the block after its waiting period ends
+	assert.Equal(t, []types.ImmatureClaim{
+		{Claim: earlier, BlocksToMaturity: waitingPeriod - k.BlocksPerSession(ctx) + 1},
+		{Claim: later, BlocksToMaturity: waitingPeriod + 1},
+	}, immature)
+	assert.False(t, k.ClaimIsMature(ctx, earlier.SessionHeader.SessionBlockHeight))
+	assert.True(t, k.ClaimIsMature(ctx.WithBlockHeight(ctx.BlockHeight()+immature[0].BlocksToMaturity), earlier.SessionHeader.SessionBlockHeight))
+	// an address without claims has none
+	bz, er = makeTestCodec().MarshalJSON(types.QueryImmatureClaimsParams{Address: getRandomValidatorAddress()})
+	assert.Nil(t, er)
+	res, err = queryImmatureClaims(ctx, abci.RequestQuery{Data: bz}, k)
+	assert.Nil(t, err)
+	assert.Nil(t, makeTestCodec().UnmarshalJSON(res, &immature))
+	assert.Empty(t, immature)
+}
+
+func TestQueryCachedRelaysByChain(t *testing.T) {
+	// the query reads no state, but the test input sets up the service metrics evidence is recorded to
+	createTestInput(t, false)
+	ethereum, bitcoin := hex.EncodeToString([]byte{01}), hex.EncodeToString([]byte{02})
+	node := newTestPocketNode(t)
+	types.GlobalPocketNodes[node.GetAddress().String()] = node
+	t.Cleanup(func() { delete(types.GlobalPocketNodes, node.GetAddress().String()) })
+	clientKey := getRandomPrivateKey()
+	// two sessions of ethereum and one of bitcoin
+	seed := func(chain string, sessionHeight int64, relays int) {
+		appKey := getRandomPrivateKey()
+		header := types.SessionHeader{ApplicationPubKey: appKey.PublicKey().RawString(), Chain: chain, SessionBlockHeight: sessionHeight}
+		for i := 0; i < relays; i++ {
+			types.SetProof(header, types.RelayEvidence, createProof(appKey, clientKey, node.PrivateKey.PublicKey(), chain, i), sdk.NewInt(100000), node.EvidenceStore)
+		}
+	}
+	seed(ethereum, 1, 3)
+	seed(ethereum, 5, 4)
+	seed(bitcoin, 1, 2)
+	query := func(address sdk.Address) (relays map[string]int64, err sdk.Error) {
+		bz, er := makeTestCodec().MarshalJSON(types.QueryCachedRelaysByChainParams{Address: address})
+		assert.Nil(t, er)
+		res, err := queryCachedRelaysByChain(abci.RequestQuery{Data: bz})
+		if err != nil {
+			return nil, err
+		}
+		assert.Nil(t, makeTestCodec().UnmarshalJSON(res, &relays))
+		return
+	}
+	relays, err := query(node.GetAddress())
+	assert.Nil(t, err)
+	assert.Equal(t, map[string]int64{ethereum: 7, bitcoin: 2}, relays)
+	// the evidence cache is local, so a node not hosted by this process can't be queried
+	_, err = query(getRandomValidatorAddress())
+	assert.NotNil(t, err)
+	assert.Equal(t, sdk.CodeUnknownRequest, err.Code())
+}
+
+func TestQueryIsClaimant(t *testing.T) {
+	ctx, _, _, _, k, _, _ := createTestInput(t, false)
+	claimant, other := getRandomValidatorAddress(), getRandomValidatorAddress()
+	claim := createTestClaim(claimant, "0001", 1, 10)
+	k.SetClaims(ctx, []types.MsgClaim{claim})
+	query := func(ctx sdk.Ctx, address sdk.Address, header types.SessionHeader) (c types.Claimant) {
+		bz, er := makeTestCodec().MarshalJSON(types.QueryIsClaimantParams{Address: address, Header: header})
+		assert.Nil(t, er)
+		res, err := queryIsClaimant(ctx, abci.RequestQuery{Data: bz}, k)
+		assert.Nil(t, err)
+		assert.Nil(t, makeTestCodec().UnmarshalJSON(res, &c))
+		return
+	}
+	assert.True(t, k.IsClaimant(ctx, claimant, claim.SessionHeader))
+	// the claim can be proven once its waiting period passes
+	maturityHeight, err := k.ClaimMaturityHeight(ctx, 1)
+	assert.Nil(t, err)
+	immatureCtx := ctx.WithBlockHeight(maturityHeight - 1)
+	assert.Equal(t, types.Claimant{IsClaimant: true}, query(immatureCtx, claimant, claim.SessionHeader))
+	matureCtx := ctx.WithBlockHeight(maturityHeight)
+	assert.Equal(t, types.Claimant{IsClaimant: true, Mature: true}, query(matureCtx, claimant, claim.SessionHeader))
+	// another servicer of the session, or the claimant for another session, is not
+	assert.False(t, k.IsClaimant(ctx, other, claim.SessionHeader))
+	assert.Equal(t, types.Claimant{}, query(matureCtx, other, claim.SessionHeader))
+	otherSession := claim.SessionHeader
+	otherSession.SessionBlockHeight += k.BlocksPerSession(ctx)
+	assert.False(t, k.IsClaimant(ctx, claimant, otherSession))
+}
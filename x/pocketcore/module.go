@@ -98,8 +98,19 @@ func (am AppModule) NewQuerierHandler() sdk.Querier {
 // BeginBlock "BeginBlock" - Functionality that is called at the beginning of (every) block
 func (am AppModule) BeginBlock(ctx sdk.Ctx, req abci.RequestBeginBlock) {
 	ActivateAdditionalParameters(ctx, am)
+	// index the claims set before the maturity index was activated
+	if am.keeper.Cdc.IsOnNamedFeatureActivationHeight(ctx.BlockHeight(), codec.ClaimMaturityIndexKey) {
+		timeBeginBlockWork(types.BeginBlockSetClaimMaturityIndex, func() { am.keeper.SetClaimMaturityIndex(ctx) })
+	}
 	// delete the expired claims
-	am.keeper.DeleteExpiredClaims(ctx)
+	timeBeginBlockWork(types.BeginBlockDeleteExpiredClaims, func() { am.keeper.DeleteExpiredClaims(ctx) })
+}
+
+// "timeBeginBlockWork" - Runs a begin blocker operation and records how long it took in the service metrics
+func timeBeginBlockWork(operation string, work func()) {
+	now := time.Now()
+	work()
+	types.GlobalServiceMetric().AddBeginBlockTiming(operation, float64(time.Since(now).Microseconds())/1000)
 }
 
 // ActivateAdditionalParameters activate additional parameters on their respective upgrade heights
@@ -114,6 +125,8 @@ func ActivateAdditionalParameters(ctx sdk.Ctx, am AppModule) {
 
 // EndBlock "EndBlock" - Functionality that is called at the end of (every) block
 func (am AppModule) EndBlock(ctx sdk.Ctx, _ abci.RequestEndBlock) []abci.ValidatorUpdate {
+	// the proof verification caches only hold the leafs validated this block
+	am.keeper.ClearProofVerificationCache()
 	// get blocks per session
 	blocksPerSession := am.keeper.BlocksPerSession(ctx)
 
@@ -138,12 +151,21 @@ func (am AppModule) EndBlock(ctx sdk.Ctx, _ abci.RequestEndBlock) []abci.Validat
 		}
 
 		for _, node := range types.GlobalPocketNodes {
+			// a node whose key is not loaded is skipped until it is, instead of panicking on its address
+			if node.IsLocked() {
+				ctx.Logger().Error("skipping the claims and proofs of a pocket node without a loaded private key")
+				continue
+			}
 			address := node.GetAddress()
 			if (ctx.BlockHeight()+int64(address[0]))%blocksPerSession == 1 && ctx.BlockHeight() != 1 {
+				// drop the evidence that can no longer be claimed or proven
+				am.keeper.CompactEvidenceCache(ctx, node)
+				// keep the evidence within the node's max_stored_evidence
+				am.keeper.CapEvidenceCache(ctx, node)
 				// auto send the proofs
 				am.keeper.SendClaimTx(ctx, am.keeper, am.keeper.TmNode, node, ClaimTx)
 				// auto claim the proofs
-				am.keeper.SendProofTx(ctx, am.keeper.TmNode, node, ProofTx)
+				am.keeper.SendProofTx(ctx, am.keeper.TmNode, node, ProofTx, ProofBatchTx)
 				// clear session cache and db
 				types.ClearSessionCache(node.SessionStore)
 			}
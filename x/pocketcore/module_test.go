@@ -5,9 +5,13 @@ import (
 	"reflect"
 	"testing"
 
+	sdk "github.com/pokt-network/pocket-core/types"
 	"github.com/pokt-network/pocket-core/x/pocketcore/keeper"
 	"github.com/pokt-network/pocket-core/x/pocketcore/types"
+	stdPrometheus "github.com/prometheus/client_golang/prometheus"
 	"github.com/stretchr/testify/assert"
+	abci "github.com/tendermint/tendermint/abci/types"
+	"github.com/tendermint/tendermint/libs/log"
 )
 
 func TestAppModule_Name(t *testing.T) {
@@ -64,6 +68,36 @@ func TestAppModule_QuerierRoute(t *testing.T) {
 	assert.Equal(t, am.QuerierRoute(), types.ModuleName)
 }
 
+func TestAppModule_BeginBlockTiming(t *testing.T) {
+	ctx, _, _, k, _ := createTestInput(t, false)
+	types.InitConfig(nil, log.NewNopLogger(), sdk.DefaultTestingPocketConfig())
+	am := NewAppModule(k)
+	before := beginBlockSampleCount(t, types.BeginBlockDeleteExpiredClaims)
+	am.BeginBlock(ctx, abci.RequestBeginBlock{})
+	assert.Equal(t, before+1, beginBlockSampleCount(t, types.BeginBlockDeleteExpiredClaims))
+}
+
+func beginBlockSampleCount(t *testing.T, operation string) uint64 {
+	families, err := stdPrometheus.DefaultGatherer.Gather()
+	if err != nil {
+		t.Fatal(err)
+	}
+	name := types.ModuleName + "_" + types.ServiceMetricsNamespace + "_" + types.BeginBlockTimeName
+	for _, family := range families {
+		if family.GetName() != name {
+			continue
+		}
+		for _, m := range family.GetMetric() {
+			for _, l := range m.GetLabel() {
+				if l.GetName() == types.BeginBlockOpLabel && l.GetValue() == operation {
+					return m.GetHistogram().GetSampleCount()
+				}
+			}
+		}
+	}
+	return 0
+}
+
 //func TestAppModule_EndBlock(t *testing.T) {
 //	ctx, _, _, k, _ := createTestInput(t, false)
 //	am := NewAppModule(k)
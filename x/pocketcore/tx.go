@@ -1,12 +1,14 @@
 package pocketcore
 
 import (
+	"fmt"
 	"github.com/pokt-network/pocket-core/codec"
 	"github.com/pokt-network/pocket-core/crypto"
 	sdk "github.com/pokt-network/pocket-core/types"
 	"github.com/pokt-network/pocket-core/x/auth"
 	"github.com/pokt-network/pocket-core/x/auth/util"
 	"github.com/pokt-network/pocket-core/x/pocketcore/types"
+	tmtypes "github.com/tendermint/tendermint/types"
 )
 
 // "ClaimTx" - A transaction that sends the total number of proofs (claim), the merkle root (for data integrity), and the header (for identification)
@@ -23,11 +25,23 @@ func ClaimTx(kp crypto.PrivateKey, cliCtx util.CLIContext, txBuilder auth.TxBuil
 	if err != nil {
 		return nil, err
 	}
+	// a claim signed by a claimer other than the servicer is sent as a delegated claim, with the servicer's commitment
+	if !cliCtx.FromAddress.Equals(msg.FromAddress) {
+		delegated := types.MsgDelegatedClaim{Claim: msg, ClaimerAddress: cliCtx.FromAddress}
+		if cliCtx.Codec.IsAfterNamedFeatureActivationHeight(cliCtx.Height, codec.ClaimCommitmentKey) {
+			commitment, err := kp.Sign(msg.CommitmentBytes())
+			if err != nil {
+				return nil, err
+			}
+			delegated.Commitment = commitment
+		}
+		return broadcastDelegated(cliCtx, txBuilder, &delegated)
+	}
 	var legacyCodec bool
 	if cliCtx.Height < codec.GetCodecUpgradeHeight() {
 		legacyCodec = true
 	}
-	return util.CompleteAndBroadcastTxCLI(txBuilder, cliCtx, &msg, legacyCodec)
+	return completeAndSend(txBuilder, cliCtx, &msg, legacyCodec)
 }
 
 // "ProofTx" - A transaction to prove the claim that was previously sent (Merkle Proofs and leaf/cousin)
@@ -41,9 +55,70 @@ func ProofTx(cliCtx util.CLIContext, txBuilder auth.TxBuilder, merkleProof types
 	if err != nil {
 		return nil, err
 	}
+	// a proof signed by a claimer other than the servicer is sent as a delegated proof
+	if !cliCtx.FromAddress.Equals(msg.GetSigners()[0]) {
+		return broadcastDelegated(cliCtx, txBuilder, &types.MsgDelegatedProof{Proof: msg, ClaimerAddress: cliCtx.FromAddress})
+	}
 	var legacyCodec bool
 	if cliCtx.Height < codec.GetCodecUpgradeHeight() {
 		legacyCodec = true
 	}
-	return util.CompleteAndBroadcastTxCLI(txBuilder, cliCtx, &msg, legacyCodec)
+	return completeAndSend(txBuilder, cliCtx, &msg, legacyCodec)
+}
+
+// "DelegateClaimerTx" - A transaction that delegates the claims and proofs of the servicer to the claimer (an empty claimer revokes it)
+func DelegateClaimerTx(cliCtx util.CLIContext, txBuilder auth.TxBuilder, servicer, claimer sdk.Address) (*sdk.TxResponse, error) {
+	msg := types.MsgDelegateClaimer{
+		ServicerAddress: servicer,
+		ClaimerAddress:  claimer,
+	}
+	err := msg.ValidateBasic()
+	if err != nil {
+		return nil, err
+	}
+	return util.CompleteAndBroadcastTxCLI(txBuilder, cliCtx, &msg, false)
+}
+
+// "broadcastDelegated" - Broadcasts a delegated claim or proof (new messages, so never encoded with the legacy codec)
+func broadcastDelegated(cliCtx util.CLIContext, txBuilder auth.TxBuilder, msg sdk.ProtoMsg) (*sdk.TxResponse, error) {
+	if err := msg.ValidateBasic(); err != nil {
+		return nil, err
+	}
+	return completeAndSend(txBuilder, cliCtx, msg, false)
+}
+
+// "ProofBatchTx" - A transaction to prove multiple claims that were previously sent
+func ProofBatchTx(cliCtx util.CLIContext, txBuilder auth.TxBuilder, proofs []types.MsgProof, allOrNothing bool) (*sdk.TxResponse, error) {
+	msg := types.MsgProofBatch{
+		Proofs:       proofs,
+		AllOrNothing: allOrNothing,
+	}
+	err := msg.ValidateBasic()
+	if err != nil {
+		return nil, err
+	}
+	return completeAndSend(txBuilder, cliCtx, &msg, false)
+}
+
+// "completeAndSend" - Signs the auto tx and broadcasts it, or writes it to the offline queue instead if one is set; a
+// queued tx is not broadcast, so its response only carries the hash the relayer's broadcast will have
+func completeAndSend(txBuilder auth.TxBuilder, cliCtx util.CLIContext, msg sdk.ProtoMsg, legacyCodec bool) (*sdk.TxResponse, error) {
+	queue := types.GetOfflineQueue()
+	if queue == nil {
+		return util.CompleteAndBroadcastTxCLI(txBuilder, cliCtx, msg, legacyCodec)
+	}
+	var txBytes []byte
+	var err error
+	if cliCtx.PrivateKey != nil {
+		txBytes, err = txBuilder.BuildAndSign(cliCtx.FromAddress, cliCtx.PrivateKey, msg, legacyCodec)
+	} else {
+		txBytes, err = txBuilder.BuildAndSignWithKeyBase(cliCtx.FromAddress, cliCtx.Passphrase, msg, legacyCodec)
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err = queue.Enqueue(txBytes); err != nil {
+		return nil, err
+	}
+	return &sdk.TxResponse{TxHash: fmt.Sprintf("%X", tmtypes.Tx(txBytes).Hash())}, nil
 }
@@ -0,0 +1,68 @@
+package pocketcore
+
+import (
+	"bufio"
+	"encoding/hex"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/pokt-network/pocket-core/codec"
+	"github.com/pokt-network/pocket-core/crypto"
+	sdk "github.com/pokt-network/pocket-core/types"
+	"github.com/pokt-network/pocket-core/x/auth"
+	"github.com/pokt-network/pocket-core/x/auth/util"
+	"github.com/pokt-network/pocket-core/x/pocketcore/types"
+	"github.com/stretchr/testify/assert"
+	tmtypes "github.com/tendermint/tendermint/types"
+)
+
+func TestClaimTxOfflineQueue(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "offline_txs")
+	types.SetOfflineQueue(&types.FileOfflineQueue{Path: path})
+	t.Cleanup(func() { types.SetOfflineQueue(nil) })
+	cdc := makeTestCodec()
+	types.RegisterCodec(cdc)
+	key := crypto.GenerateEd25519PrivKey()
+	// no rpc client is set, so a broadcast would fail
+	cliCtx := util.NewCLIContext(nil, sdk.Address(key.PublicKey().Address()), "").WithCodec(cdc)
+	cliCtx.PrivateKey = key
+	cliCtx.Height = codec.GetCodecUpgradeHeight()
+	txBuilder := auth.NewTxBuilder(auth.DefaultTxEncoder(cdc), auth.DefaultTxDecoder(cdc), "test-chain", "", sdk.NewCoins(sdk.NewCoin(sdk.DefaultStakeDenom, sdk.NewInt(10000))))
+	header := types.SessionHeader{ApplicationPubKey: getRandomPubKey().RawString(), Chain: "0001", SessionBlockHeight: 1}
+	root := types.HashRange{Hash: types.Hash([]byte("root")), Range: types.Range{Upper: 100}}
+	var hashes []string
+	for i := 0; i < 2; i++ {
+		res, err := ClaimTx(key, cliCtx, txBuilder, header, 10, root, types.RelayEvidence)
+		assert.Nil(t, err)
+		assert.NotNil(t, res)
+		hashes = append(hashes, res.TxHash)
+	}
+	// each signed tx is written on its own line, and its hash is returned
+	f, err := os.Open(path)
+	assert.Nil(t, err)
+	defer f.Close()
+	scanner := bufio.NewScanner(f)
+	var lines int
+	for ; scanner.Scan(); lines++ {
+		txBytes, err := hex.DecodeString(scanner.Text())
+		assert.Nil(t, err)
+		assert.Equal(t, hashes[lines], fmt.Sprintf("%X", tmtypes.Tx(txBytes).Hash()))
+		tx, er := auth.DefaultTxDecoder(cdc)(txBytes, cliCtx.Height)
+		assert.Nil(t, er)
+		stdTx := tx.(auth.StdTx)
+		assert.Nil(t, stdTx.ValidateBasic())
+		claim, ok := stdTx.GetMsg().(*types.MsgClaim)
+		assert.True(t, ok)
+		assert.Equal(t, header, claim.SessionHeader)
+		signBytes, e := auth.StdSignBytes("test-chain", stdTx.GetEntropy(), stdTx.GetFee(), stdTx.GetMsg(), stdTx.GetMemo())
+		assert.Nil(t, e)
+		assert.True(t, key.PublicKey().VerifyBytes(signBytes, stdTx.GetSignature().Signature))
+	}
+	assert.Equal(t, 2, lines)
+	// without a queue the tx is broadcast
+	types.SetOfflineQueue(nil)
+	_, err = ClaimTx(key, cliCtx, txBuilder, header, 10, root, types.RelayEvidence)
+	assert.NotNil(t, err)
+}
@@ -1,6 +1,7 @@
 package types
 
 import (
+	"bytes"
 	"encoding/hex"
 	"errors"
 	"fmt"
@@ -26,6 +27,9 @@ type CacheStorage struct {
 	DB      db.DB      // persisted
 	l       sync.Mutex // lock
 	SealMap *sync.Map
+	// guards the evidence across a read-modify-write (see SetProof); the cached evidence shares its bloom filter and
+	// proofs with every copy read from the cache, so readers hold it while they inspect them
+	evidenceLock sync.RWMutex
 }
 
 type CacheObject interface {
@@ -40,6 +44,7 @@ type CacheObject interface {
 func (cs *CacheStorage) Init(dir, name string, options config.LevelDBOptions, maxEntries int, inMemoryDB bool) {
 	// init the lru cache with a max entries
 	cs.Cache = sdk.NewCache(maxEntries)
+	cs.SealMap = &sync.Map{}
 	// intialize the db
 	var err error
 	if inMemoryDB {
@@ -54,7 +59,6 @@ func (cs *CacheStorage) Init(dir, name string, options config.LevelDBOptions, ma
 		}
 		panic(err)
 	}
-	cs.SealMap = &sync.Map{}
 }
 
 // "Get" - Returns the value from a key
@@ -91,6 +95,8 @@ func DeleteEvidence(header SessionHeader, evidenceType EvidenceType, evidenceSto
 	if err != nil {
 		return err
 	}
+	evidenceStore.evidenceLock.Lock()
+	defer evidenceStore.evidenceLock.Unlock()
 	// delete from cache
 	evidenceStore.Delete(key)
 	evidenceStore.SealMap.Delete(header.HashString())
@@ -302,6 +308,44 @@ func SessionIterator(sessionStore *CacheStorage) SessionIt {
 
 // "GetEvidence" - Retrieves the GOBEvidence object from the storage
 func GetEvidence(header SessionHeader, evidenceType EvidenceType, max sdk.BigInt, storage *CacheStorage) (evidence Evidence, err error) {
+	evidence, unlock, err := lockEvidence(header, evidenceType, max, storage)
+	unlock()
+	return evidence, err
+}
+
+// "lockEvidence" - Retrieves the GOBEvidence object from the storage holding the evidence lock, released by the caller
+// with unlock once done with the evidence: the read lock, or the write lock if the evidence hit max and is sealed
+// (sealing writes the storage, so it is never done under the read lock)
+func lockEvidence(header SessionHeader, evidenceType EvidenceType, max sdk.BigInt, storage *CacheStorage) (evidence Evidence, unlock func(), err error) {
+	storage.evidenceLock.RLock()
+	evidence, seal, err := lookupEvidence(header, evidenceType, max, storage)
+	if err != nil || !seal {
+		return evidence, storage.evidenceLock.RUnlock, err
+	}
+	storage.evidenceLock.RUnlock()
+	storage.evidenceLock.Lock()
+	// the evidence may have changed while no lock was held, so it is read again
+	evidence, err = getEvidence(header, evidenceType, max, storage)
+	return evidence, storage.evidenceLock.Unlock, err
+}
+
+// "getEvidence" - Retrieves the GOBEvidence object from the storage, sealing it if it hit max; the caller holds the
+// evidence lock (the write lock if max is not zero)
+func getEvidence(header SessionHeader, evidenceType EvidenceType, max sdk.BigInt, storage *CacheStorage) (evidence Evidence, err error) {
+	evidence, seal, err := lookupEvidence(header, evidenceType, max, storage)
+	if err != nil || !seal {
+		return
+	}
+	evidence, ok := SealEvidence(evidence, storage)
+	if !ok {
+		err = fmt.Errorf("max relays is hit and could not seal evidence! GetEvidence() with header %v", header)
+	}
+	return
+}
+
+// "lookupEvidence" - Retrieves the GOBEvidence object from the storage without modifying it, and whether it hit max
+// and must be sealed; the caller holds the evidence lock
+func lookupEvidence(header SessionHeader, evidenceType EvidenceType, max sdk.BigInt, storage *CacheStorage) (evidence Evidence, seal bool, err error) {
 	// generate the key for the GOBEvidence
 	key, err := KeyForEvidence(header, evidenceType)
 	if err != nil {
@@ -310,7 +354,7 @@ func GetEvidence(header SessionHeader, evidenceType EvidenceType, max sdk.BigInt
 	// get the bytes from the storage
 	val, found := storage.Get(key, evidence)
 	if !found && max.Equal(sdk.ZeroInt()) {
-		return Evidence{}, fmt.Errorf("GOBEvidence not found")
+		return Evidence{}, false, fmt.Errorf("GOBEvidence not found")
 	}
 	if !found {
 		bloomFilter := bloom.NewWithEstimates(uint(sdk.NewUintFromBigInt(max.BigInt()).Uint64()), .01)
@@ -329,7 +373,7 @@ func GetEvidence(header SessionHeader, evidenceType EvidenceType, max sdk.BigInt
 			NumOfProofs:   0,
 			Proofs:        make([]Proof, 0),
 			EvidenceType:  evidenceType,
-		}, nil
+		}, false, nil
 	}
 	evidence, ok := val.(Evidence)
 	if !ok {
@@ -337,17 +381,10 @@ func GetEvidence(header SessionHeader, evidenceType EvidenceType, max sdk.BigInt
 		return
 	}
 	if storage.IsSealed(evidence) {
-		return evidence, nil
+		return evidence, false, nil
 	}
 	// if hit relay limit... Seal the evidence
-	if found && !max.Equal(sdk.ZeroInt()) && evidence.NumOfProofs >= max.Int64() {
-		evidence, ok = SealEvidence(evidence, storage)
-		if !ok {
-			err = fmt.Errorf("max relays is hit and could not seal evidence! GetEvidence() with header %v", header)
-			return
-		}
-	}
-	return
+	return evidence, !max.Equal(sdk.ZeroInt()) && evidence.NumOfProofs >= max.Int64(), nil
 }
 
 // "SetEvidence" - Sets an GOBEvidence object in the storage
@@ -405,25 +442,75 @@ func EvidenceIterator(evidenceStore *CacheStorage) EvidenceIt {
 	}
 }
 
+// "GetCachedRelaysByChain" - Returns the total relays per chain across the relay evidence held by the evidence store;
+// it only reads the node's own cache (not the world state), and evidence is held until its session is proven, so these
+// are the relays the node has served but not yet been rewarded for
+func GetCachedRelaysByChain(evidenceStore *CacheStorage) map[string]int64 {
+	relays := make(map[string]int64)
+	iter := EvidenceIterator(evidenceStore)
+	defer iter.Close()
+	for ; iter.Valid(); iter.Next() {
+		evidence := iter.Value()
+		if evidence.EvidenceType != RelayEvidence {
+			continue
+		}
+		relays[evidence.Chain] += evidence.NumOfProofs
+	}
+	return relays
+}
+
 // "GetProof" - Returns the Proof object from a specific piece of GOBEvidence at a certain index
-func GetProof(header SessionHeader, evidenceType EvidenceType, index int64, evidenceStore *CacheStorage) Proof {
+func GetProof(header SessionHeader, evidenceType EvidenceType, index int64, evidenceStore *CacheStorage) (proof Proof, found bool) {
+	evidenceStore.evidenceLock.RLock()
+	defer evidenceStore.evidenceLock.RUnlock()
 	// retrieve the GOBEvidence
-	evidence, err := GetEvidence(header, evidenceType, sdk.ZeroInt(), evidenceStore)
+	evidence, err := getEvidence(header, evidenceType, sdk.ZeroInt(), evidenceStore)
 	if err != nil {
-		return nil
+		return nil, false
 	}
 	// check for out of bounds
-	if evidence.NumOfProofs-1 < index || index < 0 {
-		return nil
+	if index < 0 || index >= int64(len(evidence.Proofs)) {
+		return nil, false
 	}
 	// return the propoer proof
-	return evidence.Proofs[index]
+	return evidence.Proofs[index], true
+}
+
+// "GetProofCount" - Returns the number of proofs held in the GOBEvidence (0 if the GOBEvidence is not found)
+func GetProofCount(header SessionHeader, evidenceType EvidenceType, evidenceStore *CacheStorage) int64 {
+	evidenceStore.evidenceLock.RLock()
+	defer evidenceStore.evidenceLock.RUnlock()
+	evidence, err := getEvidence(header, evidenceType, sdk.ZeroInt(), evidenceStore)
+	if err != nil {
+		return 0
+	}
+	return int64(len(evidence.Proofs))
+}
+
+// "FindProofByHash" - Returns the index of the proof with the (leaf) hash in the GOBEvidence, for diagnosing uncounted relays
+func FindProofByHash(header SessionHeader, evidenceType EvidenceType, leafHash []byte, evidenceStore *CacheStorage) (index int, found bool) {
+	evidenceStore.evidenceLock.RLock()
+	defer evidenceStore.evidenceLock.RUnlock()
+	evidence, err := getEvidence(header, evidenceType, sdk.ZeroInt(), evidenceStore)
+	if err != nil || !evidence.Bloom.Test(leafHash) {
+		return 0, false
+	}
+	// the bloom filter has false positives, so the proofs are scanned for the hash
+	for i, p := range evidence.Proofs {
+		if bytes.Equal(p.Hash(), leafHash) {
+			return i, true
+		}
+	}
+	return 0, false
 }
 
 // "SetProof" - Sets a proof object in the GOBEvidence, using the header and GOBEvidence type
+// The evidence is read, extended and written back under the evidence lock, so concurrent relays do not lose proofs
 func SetProof(header SessionHeader, evidenceType EvidenceType, p Proof, max sdk.BigInt, evidenceStore *CacheStorage) {
+	evidenceStore.evidenceLock.Lock()
+	defer evidenceStore.evidenceLock.Unlock()
 	// retireve the GOBEvidence
-	evidence, err := GetEvidence(header, evidenceType, max, evidenceStore)
+	evidence, err := getEvidence(header, evidenceType, max, evidenceStore)
 	// if not found generate the GOBEvidence object
 	if err != nil {
 		log.Fatalf("could not set proof object: %s", err.Error())
@@ -438,6 +525,17 @@ func IsUniqueProof(p Proof, evidence Evidence) bool {
 	return !evidence.Bloom.Test(p.Hash())
 }
 
+// "checkProof" - Returns whether the GOBEvidence is sealed, whether the proof is unique to it and its number of proofs,
+// under the evidence lock so a concurrent SetProof does not modify the bloom filter being tested
+func checkProof(h SessionHeader, et EvidenceType, p Proof, maxPossibleRelays sdk.BigInt, evidenceStore *CacheStorage) (sealed, unique bool, totalProofs int64) {
+	evidence, unlock, err := lockEvidence(h, et, maxPossibleRelays, evidenceStore)
+	defer unlock()
+	if err != nil {
+		log.Fatalf("could not get total proofs for GOBEvidence: %s", err.Error())
+	}
+	return evidenceStore.IsSealed(evidence), IsUniqueProof(p, evidence), evidence.NumOfProofs
+}
+
 // "GetTotalProofs" - Returns the total number of proofs for a piece of GOBEvidence
 func GetTotalProofs(h SessionHeader, et EvidenceType, maxPossibleRelays sdk.BigInt, evidenceStore *CacheStorage) (Evidence, int64) {
 	// retrieve the GOBEvidence
@@ -7,6 +7,7 @@ import (
 	"github.com/tendermint/tendermint/libs/log"
 	"os"
 	"reflect"
+	"sync"
 	"testing"
 )
 
@@ -77,7 +78,41 @@ func TestAllEvidence_AddGetEvidence(t *testing.T) {
 		Signature: "",
 	}
 	SetProof(header, RelayEvidence, proof, sdk.NewInt(100000), GlobalEvidenceCache)
-	assert.True(t, reflect.DeepEqual(GetProof(header, RelayEvidence, 0, GlobalEvidenceCache), proof))
+	p, found := GetProof(header, RelayEvidence, 0, GlobalEvidenceCache)
+	assert.True(t, found)
+	assert.True(t, reflect.DeepEqual(p, proof))
+}
+
+func TestCacheStorage_InMemory(t *testing.T) {
+	store := &CacheStorage{}
+	store.Init("", "", sdk.DefaultTestingPocketConfig().TendermintConfig.LevelDBOptions, 10, true)
+	servicerPubKey := getRandomPubKey().RawString()
+	ethereum := hex.EncodeToString([]byte{0001})
+	header := SessionHeader{
+		ApplicationPubKey:  getRandomPubKey().RawString(),
+		Chain:              ethereum,
+		SessionBlockHeight: 1,
+	}
+	proof := RelayProof{
+		RequestHash:        header.HashString(), // fake
+		SessionBlockHeight: 1,
+		ServicerPubKey:     servicerPubKey,
+		Blockchain:         ethereum,
+		Token:              AAT{Version: "0.0.1", ApplicationPublicKey: header.ApplicationPubKey, ClientPublicKey: getRandomPubKey().RawString()},
+	}
+	SetProof(header, RelayEvidence, proof, sdk.NewInt(100000), store)
+	p, found := GetProof(header, RelayEvidence, 0, store)
+	assert.True(t, found)
+	assert.True(t, reflect.DeepEqual(p, proof))
+	// the evidence is only in the store it was set in
+	_, err := GetEvidence(header, RelayEvidence, sdk.ZeroInt(), GlobalEvidenceCache)
+	assert.NotNil(t, err)
+	// an in memory store can be sealed like a persisted one
+	evidence, err := GetEvidence(header, RelayEvidence, sdk.ZeroInt(), store)
+	assert.Nil(t, err)
+	_, ok := SealEvidence(evidence, store)
+	assert.True(t, ok)
+	assert.True(t, store.IsSealed(evidence))
 }
 
 func TestAllEvidence_Iterator(t *testing.T) {
@@ -140,10 +175,165 @@ func TestAllEvidence_DeleteEvidence(t *testing.T) {
 		Signature: "",
 	}
 	SetProof(header, RelayEvidence, proof, sdk.NewInt(100000), GlobalEvidenceCache)
-	assert.True(t, reflect.DeepEqual(GetProof(header, RelayEvidence, 0, GlobalEvidenceCache), proof))
-	GetProof(header, RelayEvidence, 0, GlobalEvidenceCache)
+	p, found := GetProof(header, RelayEvidence, 0, GlobalEvidenceCache)
+	assert.True(t, found)
+	assert.True(t, reflect.DeepEqual(p, proof))
 	_ = DeleteEvidence(header, RelayEvidence, GlobalEvidenceCache)
-	assert.Empty(t, GetProof(header, RelayEvidence, 0, GlobalEvidenceCache))
+	p, found = GetProof(header, RelayEvidence, 0, GlobalEvidenceCache)
+	assert.False(t, found)
+	assert.Empty(t, p)
+}
+
+func TestAllEvidence_GetProofBounds(t *testing.T) {
+	store := &CacheStorage{}
+	store.Init("", "", sdk.DefaultTestingPocketConfig().TendermintConfig.LevelDBOptions, 10, true)
+	ethereum := hex.EncodeToString([]byte{0001})
+	header := SessionHeader{
+		ApplicationPubKey:  getRandomPubKey().RawString(),
+		Chain:              ethereum,
+		SessionBlockHeight: 1,
+	}
+	// absent evidence
+	assert.Equal(t, int64(0), GetProofCount(header, RelayEvidence, store))
+	_, found := GetProof(header, RelayEvidence, 0, store)
+	assert.False(t, found)
+	servicerPubKey := getRandomPubKey().RawString()
+	for i := int64(0); i < 3; i++ {
+		SetProof(header, RelayEvidence, RelayProof{
+			Entropy:            i,
+			RequestHash:        header.HashString(), // fake
+			SessionBlockHeight: 1,
+			ServicerPubKey:     servicerPubKey,
+			Blockchain:         ethereum,
+			Token:              AAT{Version: "0.0.1", ApplicationPublicKey: header.ApplicationPubKey, ClientPublicKey: getRandomPubKey().RawString()},
+		}, sdk.NewInt(100000), store)
+	}
+	assert.Equal(t, int64(3), GetProofCount(header, RelayEvidence, store))
+	for _, tt := range []struct {
+		index int64
+		found bool
+	}{{-1, false}, {0, true}, {2, true}, {3, false}} {
+		p, found := GetProof(header, RelayEvidence, tt.index, store)
+		assert.Equal(t, tt.found, found, tt.index)
+		if found {
+			assert.Equal(t, tt.index, p.(RelayProof).Entropy)
+		} else {
+			assert.Nil(t, p)
+		}
+	}
+	// another evidence type of the same session is absent
+	assert.Equal(t, int64(0), GetProofCount(header, ChallengeEvidence, store))
+}
+
+func TestAllEvidence_ConcurrentAccess(t *testing.T) {
+	store := &CacheStorage{}
+	store.Init("", "", sdk.DefaultTestingPocketConfig().TendermintConfig.LevelDBOptions, 10, true)
+	ethereum := hex.EncodeToString([]byte{0001})
+	header := SessionHeader{
+		ApplicationPubKey:  getRandomPubKey().RawString(),
+		Chain:              ethereum,
+		SessionBlockHeight: 1,
+	}
+	deleted := SessionHeader{
+		ApplicationPubKey:  getRandomPubKey().RawString(),
+		Chain:              ethereum,
+		SessionBlockHeight: 1,
+	}
+	servicerPubKey := getRandomPubKey().RawString()
+	newProof := func(h SessionHeader, entropy int64) RelayProof {
+		return RelayProof{
+			Entropy:            entropy,
+			RequestHash:        h.HashString(), // fake
+			SessionBlockHeight: 1,
+			ServicerPubKey:     servicerPubKey,
+			Blockchain:         ethereum,
+			Token:              AAT{Version: "0.0.1", ApplicationPublicKey: h.ApplicationPubKey, ClientPublicKey: servicerPubKey},
+		}
+	}
+	const writers, proofsPerWriter = 8, 50
+	max := sdk.NewInt(writers * proofsPerWriter * 2)
+	var wg sync.WaitGroup
+	for w := 0; w < writers; w++ {
+		wg.Add(3)
+		// relays being served
+		go func(w int) {
+			defer wg.Done()
+			for i := 0; i < proofsPerWriter; i++ {
+				p := newProof(header, int64(w*proofsPerWriter+i))
+				SetProof(header, RelayEvidence, p, max, store)
+				FindProofByHash(header, RelayEvidence, p.Hash(), store)
+			}
+		}(w)
+		// claims and proofs being built
+		go func() {
+			defer wg.Done()
+			for i := 0; i < proofsPerWriter; i++ {
+				GetProof(header, RelayEvidence, int64(i), store)
+				GetProofCount(header, RelayEvidence, store)
+				_, _, _ = checkProof(header, RelayEvidence, newProof(header, int64(i)), max, store)
+			}
+		}()
+		// evidence being deleted once claimed
+		go func(w int) {
+			defer wg.Done()
+			for i := 0; i < proofsPerWriter; i++ {
+				SetProof(deleted, RelayEvidence, newProof(deleted, int64(w*proofsPerWriter+i)), max, store)
+				assert.Nil(t, DeleteEvidence(deleted, RelayEvidence, store))
+			}
+		}(w)
+	}
+	wg.Wait()
+	// no proof was lost to a concurrent read-modify-write
+	assert.Equal(t, int64(writers*proofsPerWriter), GetProofCount(header, RelayEvidence, store))
+	for w := 0; w < writers; w++ {
+		_, found := FindProofByHash(header, RelayEvidence, newProof(header, int64(w*proofsPerWriter)).Hash(), store)
+		assert.True(t, found)
+	}
+}
+
+func TestAllEvidence_ConcurrentSeal(t *testing.T) {
+	store := &CacheStorage{}
+	store.Init("", "", sdk.DefaultTestingPocketConfig().TendermintConfig.LevelDBOptions, 10, true)
+	ethereum := hex.EncodeToString([]byte{0001})
+	header := SessionHeader{
+		ApplicationPubKey:  getRandomPubKey().RawString(),
+		Chain:              ethereum,
+		SessionBlockHeight: 1,
+	}
+	servicerPubKey := getRandomPubKey().RawString()
+	const proofs = 10
+	for i := 0; i < proofs; i++ {
+		SetProof(header, RelayEvidence, RelayProof{
+			Entropy:            int64(i),
+			RequestHash:        header.HashString(), // fake
+			SessionBlockHeight: 1,
+			ServicerPubKey:     servicerPubKey,
+			Blockchain:         ethereum,
+			Token:              AAT{Version: "0.0.1", ApplicationPublicKey: header.ApplicationPubKey, ClientPublicKey: servicerPubKey},
+		}, sdk.NewInt(proofs*2), store)
+	}
+	// the evidence hit max, so the readers seal it (under the write lock)
+	max := sdk.NewInt(proofs)
+	var wg sync.WaitGroup
+	for r := 0; r < 8; r++ {
+		wg.Add(2)
+		go func() {
+			defer wg.Done()
+			evidence, err := GetEvidence(header, RelayEvidence, max, store)
+			assert.Nil(t, err)
+			assert.Equal(t, int64(proofs), evidence.NumOfProofs)
+		}()
+		go func() {
+			defer wg.Done()
+			sealed, _, total := checkProof(header, RelayEvidence, RelayProof{}, max, store)
+			assert.True(t, sealed)
+			assert.Equal(t, int64(proofs), total)
+		}()
+	}
+	wg.Wait()
+	evidence, err := GetEvidence(header, RelayEvidence, sdk.ZeroInt(), store)
+	assert.Nil(t, err)
+	assert.True(t, store.IsSealed(evidence))
 }
 
 func TestAllEvidence_GetTotalProofs(t *testing.T) {
@@ -249,3 +439,38 @@ func NewTestSession(t *testing.T, chain string) Session {
 		SessionNodes: vals,
 	}
 }
+
+func TestFindProofByHash(t *testing.T) {
+	store := &CacheStorage{}
+	store.Init("", "", sdk.DefaultTestingPocketConfig().TendermintConfig.LevelDBOptions, 10, true)
+	ethereum := hex.EncodeToString([]byte{0001})
+	header := SessionHeader{
+		ApplicationPubKey:  getRandomPubKey().RawString(),
+		Chain:              ethereum,
+		SessionBlockHeight: 1,
+	}
+	servicerPubKey := getRandomPubKey().RawString()
+	var proofs []RelayProof
+	for i := 0; i < 3; i++ {
+		proof := RelayProof{
+			Entropy:            int64(i),
+			SessionBlockHeight: 1,
+			ServicerPubKey:     servicerPubKey,
+			Blockchain:         ethereum,
+			Token:              AAT{Version: "0.0.1", ApplicationPublicKey: header.ApplicationPubKey, ClientPublicKey: getRandomPubKey().RawString()},
+		}
+		SetProof(header, RelayEvidence, proof, sdk.NewInt(100000), store)
+		proofs = append(proofs, proof)
+	}
+	for i, proof := range proofs {
+		index, found := FindProofByHash(header, RelayEvidence, proof.Hash(), store)
+		assert.True(t, found)
+		assert.Equal(t, i, index)
+	}
+	// a bogus hash
+	_, found := FindProofByHash(header, RelayEvidence, Hash([]byte("bogus")), store)
+	assert.False(t, found)
+	// another evidence type of the session
+	_, found = FindProofByHash(header, ChallengeEvidence, proofs[0].Hash(), store)
+	assert.False(t, found)
+}
@@ -22,6 +22,10 @@ func RegisterCodec(cdc *codec.Codec) {
 	cdc.RegisterStructure(MsgClaim{}, "pocketcore/claim")
 	cdc.RegisterStructure(MsgProtoProof{}, "pocketcore/protoProof")
 	cdc.RegisterStructure(MsgProof{}, "pocketcore/proof")
+	cdc.RegisterStructure(MsgProofBatch{}, "pocketcore/proof_batch")
+	cdc.RegisterStructure(MsgDelegateClaimer{}, "pocketcore/delegate_claimer")
+	cdc.RegisterStructure(MsgDelegatedClaim{}, "pocketcore/delegated_claim")
+	cdc.RegisterStructure(MsgDelegatedProof{}, "pocketcore/delegated_proof")
 	cdc.RegisterStructure(Relay{}, "pocketcore/relay")
 	cdc.RegisterStructure(Session{}, "pocketcore/session")
 	cdc.RegisterStructure(RelayResponse{}, "pocketcore/relay_response")
@@ -34,7 +38,7 @@ func RegisterCodec(cdc *codec.Codec) {
 	cdc.RegisterStructure(nodesTypes.LegacyValidator{}, "pos/Validator") // todo does this really need to depend on nodes/types
 	cdc.RegisterInterface("x.pocketcore.Proof", (*Proof)(nil), &RelayProof{}, &ChallengeProofInvalidData{})
 	cdc.RegisterInterface("types.isProofI_Proof", (*isProofI_Proof)(nil))
-	cdc.RegisterImplementation((*sdk.ProtoMsg)(nil), &MsgClaim{}, &MsgProof{})
-	cdc.RegisterImplementation((*sdk.Msg)(nil), &MsgClaim{}, &MsgProof{})
+	cdc.RegisterImplementation((*sdk.ProtoMsg)(nil), &MsgClaim{}, &MsgProof{}, &MsgProofBatch{}, &MsgDelegateClaimer{}, &MsgDelegatedClaim{}, &MsgDelegatedProof{})
+	cdc.RegisterImplementation((*sdk.Msg)(nil), &MsgClaim{}, &MsgProof{}, &MsgProofBatch{}, &MsgDelegateClaimer{}, &MsgDelegatedClaim{}, &MsgDelegatedProof{})
 	ModuleCdc = cdc
 }
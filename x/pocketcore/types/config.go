@@ -35,6 +35,9 @@ func InitConfig(chains *HostedBlockchains, logger log.Logger, c types.Config) {
 		GlobalTenderMintConfig.NodeKey = types.DefaultPVSNameLean
 	}
 	SetRPCTimeout(c.PocketConfig.RPCTimeout)
+	if c.PocketConfig.OfflineTxQueue != "" {
+		SetOfflineQueue(&FileOfflineQueue{Path: c.PocketConfig.OfflineTxQueue})
+	}
 }
 
 func ConvertEvidenceToProto(config types.Config) error {
@@ -8,6 +8,7 @@ import (
 	"github.com/tendermint/tendermint/crypto/tmhash"
 	"github.com/tendermint/tendermint/privval"
 	_ "golang.org/x/crypto/sha3"
+	"math"
 	"math/big"
 )
 
@@ -131,3 +132,24 @@ func PseudorandomSelection(max sdk.BigInt, hash []byte) (index sdk.BigInt) {
 	// mod the selection
 	return intHash.Mod(max)
 }
+
+// "UniformPseudorandomSelection" - Selects an index in [0, max) with the same probability for every index.
+// The first 8 bytes of the hash are read as a big endian uint64 like PseudorandomSelection, but values in the
+// incomplete last block of the uint64 range (the 2^64 mod max largest values, which would favor the low indexes
+// after the modulo) are rejected, and the hash is re-hashed until a value below that block is found.
+// There is no index to select if max is not positive, so 0 is returned
+func UniformPseudorandomSelection(max int64, hash []byte) (index int64) {
+	if max <= 0 {
+		return 0
+	}
+	n := uint64(max)
+	// 2^64 mod n, the size of the incomplete block
+	remainder := (math.MaxUint64%n + 1) % n
+	for {
+		v := new(big.Int).SetBytes(hash[:8]).Uint64()
+		if remainder == 0 || v <= math.MaxUint64-remainder {
+			return int64(v % n)
+		}
+		hash = Hash(hash)
+	}
+}
@@ -2,6 +2,7 @@ package types
 
 import (
 	"encoding/base64"
+	"encoding/binary"
 	"encoding/hex"
 	"github.com/stretchr/testify/assert"
 	"github.com/tendermint/tendermint/crypto/secp256k1"
@@ -162,3 +163,33 @@ func TestHash(t *testing.T) {
 		})
 	}
 }
+
+func TestUniformPseudorandomSelection(t *testing.T) {
+	// 2^64 mod 3 = 1, so the largest uint64 is in the incomplete block and is rejected
+	hash := make([]byte, HashLength)
+	binary.BigEndian.PutUint64(hash, ^uint64(0))
+	rehash := Hash(hash)
+	assert.Equal(t, int64(binary.BigEndian.Uint64(rehash[:8])%3), UniformPseudorandomSelection(3, hash))
+	// the value below it is accepted
+	binary.BigEndian.PutUint64(hash, ^uint64(0)-1)
+	assert.Equal(t, int64((^uint64(0)-1)%3), UniformPseudorandomSelection(3, hash))
+	// a power of two has no incomplete block
+	binary.BigEndian.PutUint64(hash, ^uint64(0))
+	assert.Equal(t, int64(7), UniformPseudorandomSelection(8, hash))
+	// there is nothing to select from an empty or negative range
+	assert.Equal(t, int64(0), UniformPseudorandomSelection(0, hash))
+	assert.Equal(t, int64(0), UniformPseudorandomSelection(-1, hash))
+	// near uniform across many seeds
+	const max, seeds = 7, 70000
+	counts := make([]int, max)
+	seed := make([]byte, 8)
+	for i := 0; i < seeds; i++ {
+		binary.BigEndian.PutUint64(seed, uint64(i))
+		index := UniformPseudorandomSelection(max, Hash(seed))
+		assert.True(t, index >= 0 && index < max)
+		counts[index]++
+	}
+	for index, count := range counts {
+		assert.InDelta(t, seeds/max, count, seeds/max*0.05, index)
+	}
+}
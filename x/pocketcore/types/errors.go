@@ -94,6 +94,26 @@ const (
 	CodeInvalidExpirationHeightErr       = 88
 	CodeInvalidMerkleRangeError          = 89
 	CodeEvidenceSealed                   = 90
+	CodeEmptyProofBatchError             = 91
+	CodeMismatchedBatchSignersError      = 92
+	CodeNoValidProofsInBatchError        = 93
+	CodeClaimHeaderCollisionError        = 94
+	CodeMerkleIndexOutOfRangeError       = 95
+	CodeProofLimitExceededError          = 96
+	CodeMismatchedEvidenceError          = 97
+	CodeMalformedProofError              = 98
+	CodeInvalidProofIndexError           = 99
+	CodeInvalidProofLevelCountError      = 100
+	CodeClaimServicerMismatchError       = 101
+	CodeProofClaimHeaderMismatchError    = 102
+	CodeUnauthorizedClaimerError         = 103
+	CodeSelfDelegatedClaimerError        = 104
+	CodeClaimExceedsMaxRelaysError       = 105
+	CodeInvalidClaimCommitmentError      = 106
+	CodeFutureSessionHeightError         = 107
+	CodeEmptyMerkleRootError             = 108
+	CodeSelfSignedProofError             = 109
+	CodeProofBatchTooLargeError          = 110
 )
 
 var (
@@ -186,8 +206,108 @@ var (
 	InvalidExpirationHeightErr       = errors.New("the expiration height included in the claim message is invalid (should not be set)")
 	InvalidMerkleRangeError          = errors.New("the merkle hash range is invalid")
 	SealedEvidenceError              = errors.New("the evidence is sealed, either max relays reached or claim already submitted")
+	EmptyProofBatchError             = errors.New("the proof batch is empty")
+	MismatchedBatchSignersError      = errors.New("the proofs in the batch are not all signed by the same servicer")
+	NoValidProofsInBatchError        = errors.New("none of the proofs in the batch are valid")
+	ClaimHeaderCollisionError        = errors.New("a claim for a different session header is already stored under this claim key")
+	MerkleIndexOutOfRangeError       = errors.New("the merkle proof index is out of range for the evidence")
+	ProofLimitExceededError          = errors.New("the address has reached the maximum number of proofs per block")
+	MismatchedEvidenceError          = errors.New("the evidences are not for the same session header and evidence type")
+	MalformedProofError              = errors.New("the proof is malformed")
+	InvalidProofIndexError           = errors.New("the merkle proof target index is not the required pseudorandom index")
+	InvalidProofLevelCountError      = errors.New("the number of merkle proof levels does not match the claim's total proofs")
+	ClaimServicerMismatchError       = errors.New("the claim's from address does not match the servicer of its evidence")
+	ProofClaimHeaderMismatchError    = errors.New("the session header of the proof's leaf does not match the claim's session header")
+	UnauthorizedClaimerError         = errors.New("the signer is not the claimer the servicer delegated to")
+	SelfDelegatedClaimerError        = errors.New("the servicer cannot delegate claiming to itself")
+	ClaimExceedsMaxRelaysError       = errors.New("the claim's total proofs exceed the max relays per session")
+	InvalidClaimCommitmentError      = errors.New("the claim commitment is not the servicer's signature of the claim")
+	FutureSessionHeightError         = errors.New("the claim's session block height is in the future")
+	EmptyMerkleRootError             = errors.New("the claim's merkle root hash is empty or all zero")
+	SelfSignedProofError             = errors.New("the relay of the proof is signed by the servicer's own key")
+	ProofBatchTooLargeError          = errors.New("the proof batch has more than the max proof batch size proofs")
 )
 
+func NewInvalidProofIndexError(codespace sdk.CodespaceType, index, required int64) sdk.Error {
+	return sdk.NewError(codespace, CodeInvalidProofIndexError, fmt.Sprintf("%s: index %d, required %d", InvalidProofIndexError.Error(), index, required))
+}
+
+func NewInvalidProofLevelCountError(codespace sdk.CodespaceType, levelCount, required int) sdk.Error {
+	return sdk.NewError(codespace, CodeInvalidProofLevelCountError, fmt.Sprintf("%s: levels %d, required %d", InvalidProofLevelCountError.Error(), levelCount, required))
+}
+
+func NewClaimServicerMismatchError(codespace sdk.CodespaceType, from, servicer sdk.Address) sdk.Error {
+	return sdk.NewError(codespace, CodeClaimServicerMismatchError, fmt.Sprintf("%s: from %s, servicer %s", ClaimServicerMismatchError.Error(), from.String(), servicer.String()))
+}
+
+func NewProofClaimHeaderMismatchError(codespace sdk.CodespaceType) sdk.Error {
+	return sdk.NewError(codespace, CodeProofClaimHeaderMismatchError, ProofClaimHeaderMismatchError.Error())
+}
+
+func NewUnauthorizedClaimerError(codespace sdk.CodespaceType, servicer, signer sdk.Address) sdk.Error {
+	return sdk.NewError(codespace, CodeUnauthorizedClaimerError, fmt.Sprintf("%s: servicer %s, signer %s", UnauthorizedClaimerError.Error(), servicer.String(), signer.String()))
+}
+
+func NewSelfDelegatedClaimerError(codespace sdk.CodespaceType) sdk.Error {
+	return sdk.NewError(codespace, CodeSelfDelegatedClaimerError, SelfDelegatedClaimerError.Error())
+}
+
+func NewClaimExceedsMaxRelaysError(codespace sdk.CodespaceType, totalProofs, maxRelays int64) sdk.Error {
+	return sdk.NewError(codespace, CodeClaimExceedsMaxRelaysError, fmt.Sprintf("%s: total proofs %d, max relays %d", ClaimExceedsMaxRelaysError.Error(), totalProofs, maxRelays))
+}
+
+func NewInvalidClaimCommitmentError(codespace sdk.CodespaceType, reason string) sdk.Error {
+	return sdk.NewError(codespace, CodeInvalidClaimCommitmentError, fmt.Sprintf("%s: %s", InvalidClaimCommitmentError.Error(), reason))
+}
+
+func NewFutureSessionHeightError(codespace sdk.CodespaceType, sessionBlockHeight, height int64) sdk.Error {
+	return sdk.NewError(codespace, CodeFutureSessionHeightError, fmt.Sprintf("%s: session height %d, current height %d", FutureSessionHeightError.Error(), sessionBlockHeight, height))
+}
+
+func NewEmptyMerkleRootError(codespace sdk.CodespaceType) sdk.Error {
+	return sdk.NewError(codespace, CodeEmptyMerkleRootError, EmptyMerkleRootError.Error())
+}
+
+func NewSelfSignedProofError(codespace sdk.CodespaceType) sdk.Error {
+	return sdk.NewError(codespace, CodeSelfSignedProofError, SelfSignedProofError.Error())
+}
+
+func NewProofBatchTooLargeError(codespace sdk.CodespaceType, size int) sdk.Error {
+	return sdk.NewError(codespace, CodeProofBatchTooLargeError, fmt.Sprintf("%s: %d proofs, max %d", ProofBatchTooLargeError.Error(), size, MaxProofBatchSize))
+}
+
+func NewMalformedProofError(codespace sdk.CodespaceType, reason string) sdk.Error {
+	return sdk.NewError(codespace, CodeMalformedProofError, fmt.Sprintf("%s: %s", MalformedProofError.Error(), reason))
+}
+
+func NewMismatchedEvidenceError(codespace sdk.CodespaceType) sdk.Error {
+	return sdk.NewError(codespace, CodeMismatchedEvidenceError, MismatchedEvidenceError.Error())
+}
+
+func NewProofLimitExceededError(codespace sdk.CodespaceType, max int64) sdk.Error {
+	return sdk.NewError(codespace, CodeProofLimitExceededError, fmt.Sprintf("%s (%d)", ProofLimitExceededError.Error(), max))
+}
+
+func NewMerkleIndexOutOfRangeError(codespace sdk.CodespaceType, index int, numOfLeafs int) sdk.Error {
+	return sdk.NewError(codespace, CodeMerkleIndexOutOfRangeError, fmt.Sprintf("%s: index %d, number of leafs %d", MerkleIndexOutOfRangeError.Error(), index, numOfLeafs))
+}
+
+func NewClaimHeaderCollisionError(codespace sdk.CodespaceType) sdk.Error {
+	return sdk.NewError(codespace, CodeClaimHeaderCollisionError, ClaimHeaderCollisionError.Error())
+}
+
+func NewEmptyProofBatchError(codespace sdk.CodespaceType) sdk.Error {
+	return sdk.NewError(codespace, CodeEmptyProofBatchError, EmptyProofBatchError.Error())
+}
+
+func NewMismatchedBatchSignersError(codespace sdk.CodespaceType) sdk.Error {
+	return sdk.NewError(codespace, CodeMismatchedBatchSignersError, MismatchedBatchSignersError.Error())
+}
+
+func NewNoValidProofsInBatchError(codespace sdk.CodespaceType) sdk.Error {
+	return sdk.NewError(codespace, CodeNoValidProofsInBatchError, NoValidProofsInBatchError.Error())
+}
+
 func NewSealedEvidenceError(codespace sdk.CodespaceType) sdk.Error {
 	return sdk.NewError(codespace, CodeEvidenceSealed, SealedEvidenceError.Error())
 }
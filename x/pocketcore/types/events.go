@@ -1,7 +1,16 @@
 package types
 
 const (
-	EventTypeClaim        = MsgClaimName // an event for emitting a claim message
-	EventTypeProof        = MsgProofName // an event for emitting a proof message
-	AttributeKeyValidator = "validator"  // a validator attribute
+	EventTypeClaim            = MsgClaimName           // an event for emitting a claim message
+	EventTypeProof            = MsgProofName           // an event for emitting a proof message
+	EventTypeDelegateClaimer  = MsgDelegateClaimerName // an event for emitting a delegate claimer message
+	AttributeKeyValidator     = "validator"            // a validator attribute
+	AttributeKeyClaimer       = "claimer"              // a claimer attribute
+	EventTypeProofVerified    = "proof_verified"       // an event for a verified relay proof (the claim is rewarded)
+	AttributeKeyServicer      = "servicer"             // a servicer attribute
+	AttributeKeyChain         = "chain"                // a blockchain attribute
+	AttributeKeySessionHeight = "session_height"       // a session block height attribute
+	AttributeKeyTotalRelays   = "total_relays"         // a total relays attribute
+	AttributeKeyProofIndex    = "proof_index"          // a proven leaf index attribute
+	EventTypeEvidenceEvicted  = "cache_evicted"        // a node local event for evidence evicted from the evidence cache (logged)
 )
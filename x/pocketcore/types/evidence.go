@@ -1,10 +1,13 @@
 package types
 
 import (
+	"bytes"
+	"compress/flate"
 	"fmt"
 	"github.com/pokt-network/pocket-core/codec"
 	"github.com/pokt-network/pocket-core/types"
 	"github.com/willf/bloom"
+	"io/ioutil"
 	"strings"
 )
 
@@ -17,6 +20,13 @@ type Evidence struct {
 	EvidenceType  EvidenceType             `json:"evidence_type"`
 }
 
+// "EvidenceCacheDiff" - The sessions a node holds evidence for but has no claim of in the world state, and the sessions
+// it has a claim of but no evidence for (so the claim can't be proven); see Keeper.DiffEvidenceCache
+type EvidenceCacheDiff struct {
+	OnlyLocal []SessionHeader `json:"only_local"`
+	OnlyChain []SessionHeader `json:"only_chain"`
+}
+
 func (e Evidence) IsSealable() bool {
 	return true
 }
@@ -59,6 +69,49 @@ func (e *Evidence) GenerateMerkleProof(height int64, index int, maxRelays int64)
 	return
 }
 
+// "GenerateMerkleProofForIndex" - Generates the merkle Proof for the leaf at any index of the (sorted) merkle tree;
+// unlike GenerateMerkleProof the index is bounds checked and the evidence is left untouched
+func (e Evidence) GenerateMerkleProofForIndex(height int64, index int, maxRelays int64) (proof MerkleProof, leaf Proof, err types.Error) {
+	numOfLeafs := len(e.Proofs)
+	if int64(numOfLeafs) > maxRelays {
+		numOfLeafs = int(maxRelays)
+	}
+	if index < 0 || index >= numOfLeafs {
+		return MerkleProof{}, nil, NewMerkleIndexOutOfRangeError(ModuleName, index, numOfLeafs)
+	}
+	// copy the leafs because sorting the merkle tree manipulates the slice
+	proofs := make([]Proof, numOfLeafs)
+	copy(proofs, e.Proofs)
+	proof, leaf = GenerateProofs(height, proofs, index)
+	return proof, leaf, nil
+}
+
+// "MergeEvidence" - Merges two partial evidences of the same session (e.g. relays served by different processes) into one;
+// a proof found in both is only kept once
+func MergeEvidence(a, b Evidence) (Evidence, types.Error) {
+	if a.SessionHeader.Canonical() != b.SessionHeader.Canonical() || a.EvidenceType != b.EvidenceType {
+		return Evidence{}, NewMismatchedEvidenceError(ModuleName)
+	}
+	merged := Evidence{
+		Bloom:         *a.Bloom.Copy(),
+		SessionHeader: a.SessionHeader,
+		Proofs:        make(Proofs, 0, len(a.Proofs)+len(b.Proofs)),
+		EvidenceType:  a.EvidenceType,
+	}
+	seen := make(map[string]struct{}, len(a.Proofs)+len(b.Proofs))
+	for _, proofs := range []Proofs{a.Proofs, b.Proofs} {
+		for _, p := range proofs {
+			hash := p.HashString()
+			if _, found := seen[hash]; found {
+				continue
+			}
+			seen[hash] = struct{}{}
+			merged.AddProof(p)
+		}
+	}
+	return merged, nil
+}
+
 // "Evidence" - A proof of work/burn for nodes.
 type evidence struct {
 	BloomBytes    []byte                   `json:"bloom_bytes"`
@@ -190,15 +243,30 @@ func (pe *ProtoEvidence) FromProto() (Evidence, error) {
 		EvidenceType:  pe.EvidenceType}, nil
 }
 
+// compressedEvidenceFlag prefixes the deflated evidence records; a protobuf message never starts with a 0x00 tag
+// (field number 0 is invalid), so records written before compression was enabled are still read as is
+const compressedEvidenceFlag = byte(0x00)
+
 func (e Evidence) MarshalObject() ([]byte, error) {
 	pe, err := e.ToProto()
 	if err != nil {
 		return nil, err
 	}
-	return ModuleCdc.ProtoMarshalBinaryBare(pe)
+	bz, err := ModuleCdc.ProtoMarshalBinaryBare(pe)
+	if err != nil || !GlobalPocketConfig.CompressEvidence {
+		return bz, err
+	}
+	return compressEvidence(bz)
 }
 
 func (e Evidence) UnmarshalObject(b []byte) (CacheObject, error) {
+	if len(b) != 0 && b[0] == compressedEvidenceFlag {
+		var err error
+		b, err = decompressEvidence(b)
+		if err != nil {
+			return Evidence{}, fmt.Errorf("could not unmarshal into ProtoEvidence from cache, inflate: %s", err.Error())
+		}
+	}
 	pe := ProtoEvidence{}
 	err := ModuleCdc.ProtoUnmarshalBinaryBare(b, &pe)
 	if err != nil {
@@ -207,6 +275,29 @@ func (e Evidence) UnmarshalObject(b []byte) (CacheObject, error) {
 	return pe.FromProto()
 }
 
+// "compressEvidence" - Deflates a marshalled evidence record behind the compressed flag
+func compressEvidence(bz []byte) ([]byte, error) {
+	buf := bytes.NewBuffer([]byte{compressedEvidenceFlag})
+	w, err := flate.NewWriter(buf, flate.DefaultCompression)
+	if err != nil {
+		return nil, err
+	}
+	if _, err = w.Write(bz); err != nil {
+		return nil, err
+	}
+	if err = w.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// "decompressEvidence" - Inflates a record written by compressEvidence
+func decompressEvidence(bz []byte) ([]byte, error) {
+	r := flate.NewReader(bytes.NewReader(bz[1:]))
+	defer r.Close()
+	return ioutil.ReadAll(r)
+}
+
 func (e Evidence) Key() ([]byte, error) {
 	return KeyForEvidence(e.SessionHeader, e.EvidenceType)
 }
@@ -0,0 +1,138 @@
+package types
+
+import (
+	"encoding/hex"
+	"testing"
+
+	sdk "github.com/pokt-network/pocket-core/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/willf/bloom"
+)
+
+func TestMergeEvidence(t *testing.T) {
+	ethereum := hex.EncodeToString([]byte{01})
+	appPubKey := getRandomPubKey().RawString()
+	header := SessionHeader{
+		ApplicationPubKey:  appPubKey,
+		Chain:              ethereum,
+		SessionBlockHeight: 1,
+	}
+	servicerPubKey := getRandomPubKey().RawString()
+	newEvidence := func(header SessionHeader, entropies ...int64) Evidence {
+		e := Evidence{Bloom: *bloom.New(10000, 4), SessionHeader: header, EvidenceType: RelayEvidence}
+		for _, entropy := range entropies {
+			e.AddProof(RelayProof{
+				Entropy:            entropy,
+				SessionBlockHeight: header.SessionBlockHeight,
+				ServicerPubKey:     servicerPubKey,
+				Blockchain:         header.Chain,
+				Token:              AAT{Version: "0.0.1", ApplicationPublicKey: appPubKey},
+			})
+		}
+		return e
+	}
+	a := newEvidence(header, 1, 2, 3)
+	b := newEvidence(header, 3, 4)
+	merged, err := MergeEvidence(a, b)
+	assert.Nil(t, err)
+	assert.Equal(t, header, merged.SessionHeader)
+	assert.Equal(t, RelayEvidence, merged.EvidenceType)
+	// the proof served by both is kept once
+	assert.Equal(t, int64(4), merged.NumOfProofs)
+	assert.Len(t, merged.Proofs, 4)
+	for _, p := range append(a.Proofs, b.Proofs...) {
+		assert.Contains(t, merged.Proofs, p)
+		assert.True(t, merged.Bloom.Test(p.Hash()))
+	}
+	// the partial evidences are left untouched
+	assert.Equal(t, int64(3), a.NumOfProofs)
+	assert.False(t, a.Bloom.Test(b.Proofs[1].Hash()))
+	// a different session
+	otherHeader := header
+	otherHeader.SessionBlockHeight = 5
+	_, err = MergeEvidence(a, newEvidence(otherHeader, 4))
+	assert.NotNil(t, err)
+	assert.Equal(t, sdk.CodeType(CodeMismatchedEvidenceError), err.Code())
+	// a different evidence type
+	challenges := newEvidence(header)
+	challenges.EvidenceType = ChallengeEvidence
+	_, err = MergeEvidence(a, challenges)
+	assert.NotNil(t, err)
+	assert.Equal(t, sdk.CodeType(CodeMismatchedEvidenceError), err.Code())
+}
+
+// "newTestEvidence" - Returns relay evidence for a session with a proof per relay, shaped like the evidence of a busy session
+func newTestEvidence(relays int) Evidence {
+	appPubKey := getRandomPubKey().RawString()
+	servicerPubKey := getRandomPubKey().RawString()
+	clientPubKey := getRandomPubKey().RawString()
+	// one token signs every relay of the session
+	appSignature := hex.EncodeToString(append(Hash([]byte(appPubKey)), Hash([]byte(clientPubKey))...))
+	header := SessionHeader{
+		ApplicationPubKey:  appPubKey,
+		Chain:              hex.EncodeToString([]byte{01}),
+		SessionBlockHeight: 1,
+	}
+	e := Evidence{Bloom: *bloom.New(uint(relays)*10, 4), SessionHeader: header, EvidenceType: RelayEvidence}
+	for i := 0; i < relays; i++ {
+		e.AddProof(RelayProof{
+			RequestHash:        hex.EncodeToString(Hash([]byte{byte(i), byte(i >> 8)})),
+			Entropy:            int64(i),
+			SessionBlockHeight: header.SessionBlockHeight,
+			ServicerPubKey:     servicerPubKey,
+			Blockchain:         header.Chain,
+			Token:              AAT{Version: "0.0.1", ApplicationPublicKey: appPubKey, ClientPublicKey: clientPubKey, ApplicationSignature: appSignature},
+			Signature:          hex.EncodeToString(append(Hash([]byte{byte(i)}), Hash([]byte{byte(i >> 8)})...)),
+		})
+	}
+	return e
+}
+
+func TestEvidence_MarshalObjectCompressed(t *testing.T) {
+	t.Cleanup(func() { GlobalPocketConfig.CompressEvidence = false })
+	evidence := newTestEvidence(100)
+	// legacy uncompressed record
+	GlobalPocketConfig.CompressEvidence = false
+	legacy, err := evidence.MarshalObject()
+	assert.Nil(t, err)
+	assert.NotEqual(t, compressedEvidenceFlag, legacy[0])
+	// compressed record
+	GlobalPocketConfig.CompressEvidence = true
+	compressed, err := evidence.MarshalObject()
+	assert.Nil(t, err)
+	assert.Equal(t, compressedEvidenceFlag, compressed[0])
+	assert.Less(t, len(compressed), len(legacy))
+	// both decode the same way whatever the current setting
+	for _, compress := range []bool{false, true} {
+		GlobalPocketConfig.CompressEvidence = compress
+		for _, bz := range [][]byte{legacy, compressed} {
+			res, err := Evidence{}.UnmarshalObject(bz)
+			assert.Nil(t, err)
+			e := res.(Evidence)
+			assert.Equal(t, evidence.SessionHeader, e.SessionHeader)
+			assert.Equal(t, evidence.NumOfProofs, e.NumOfProofs)
+			assert.Len(t, e.Proofs, len(evidence.Proofs))
+			for i, p := range e.Proofs {
+				assert.Equal(t, evidence.Proofs[i].Hash(), p.Hash())
+			}
+			assert.True(t, e.Bloom.Test(evidence.Proofs[0].Hash()))
+		}
+	}
+	// a corrupted compressed record
+	_, err = Evidence{}.UnmarshalObject(compressed[:len(compressed)/2])
+	assert.NotNil(t, err)
+}
+
+func BenchmarkEvidence_MarshalObjectCompressed(b *testing.B) {
+	evidence := newTestEvidence(1000)
+	legacy, _ := evidence.MarshalObject()
+	GlobalPocketConfig.CompressEvidence = true
+	defer func() { GlobalPocketConfig.CompressEvidence = false }()
+	var compressed []byte
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		compressed, _ = evidence.MarshalObject()
+	}
+	b.ReportMetric(float64(len(legacy)), "legacy-bytes")
+	b.ReportMetric(float64(len(compressed)), "compressed-bytes")
+}
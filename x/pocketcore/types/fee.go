@@ -3,12 +3,22 @@ package types
 const (
 	ClaimFee = 10000 // fee for claim message (in uPOKT)
 	ProofFee = 10000 // fee for proof message (in uPOKT)
+	// fee per proof of a proof batch message (in uPOKT), so a batch costs as much as its proofs sent alone
+	ProofBatchFee = ProofFee
+	// fees for the delegated claiming messages (in uPOKT), a delegated claim or proof costs as much as the message it wraps
+	DelegateClaimerFee = 10000
+	DelegatedClaimFee  = ClaimFee
+	DelegatedProofFee  = ProofFee
 )
 
 var (
 	// map of message name to fee value
 	PocketFeeMap = map[string]int64{
-		MsgClaimName: ClaimFee,
-		MsgProofName: ProofFee,
+		MsgClaimName:           ClaimFee,
+		MsgProofName:           ProofFee,
+		MsgProofBatchName:      ProofBatchFee,
+		MsgDelegateClaimerName: DelegateClaimerFee,
+		MsgDelegatedClaimName:  DelegatedClaimFee,
+		MsgDelegatedProofName:  DelegatedProofFee,
 	}
 )
@@ -6,6 +6,16 @@ type GenesisState struct {
 	Claims []MsgClaim `json:"claims"`               // outstanding claims
 }
 
+// "ProofGenesisVersion" - The version of the proof genesis fragment written by ExportProofGenesis
+const ProofGenesisVersion = 1
+
+// "ProofGenesisState" - A versioned fragment of the proof state (the outstanding claims) for migrations and forks;
+// evidence is node local and verified claims are deleted, so the claims are the whole proof state
+type ProofGenesisState struct {
+	Version int        `json:"version"`
+	Claims  []MsgClaim `json:"claims"` // outstanding claims, in store key order
+}
+
 // "ValidateGenesis" - Returns an error on an invalid genesis object
 func ValidateGenesis(gs GenesisState) error {
 	// validate the params
@@ -1,6 +1,8 @@
 package types
 
 import (
+	"encoding/binary"
+
 	sdk "github.com/pokt-network/pocket-core/types"
 )
 
@@ -13,8 +15,45 @@ const (
 var (
 	ClaimLen = len(ClaimKey)
 	ClaimKey = []byte{0x02} // key for pending claims
+	// key for the claim the expired claims sweep resumes from
+	ExpiredClaimsCursorKey = []byte{0x03}
+	ProofCountKey          = []byte{0x04} // transient key for the number of proofs each address submitted this block
+	// key for the index of the claims by address and session height (a claim matures a fixed number of blocks after its session)
+	ClaimMaturityIndexKey = []byte{0x05}
+	// key for the number of relay proofs verified per application (rotates the proof index of its later sessions)
+	AppProofRotationKey = []byte{0x06}
+	ClaimerKey          = []byte{0x07} // key for the claimer each servicer delegated its claims and proofs to
+	// key for the total relays verified (proven and rewarded) per servicer
+	VerifiedRelaysKey = []byte{0x08}
+	// key for the leaf index each matured claim must be proven with
+	ProofIndexKey = []byte{0x09}
 )
 
+// "KeyForProofIndex" - Generates the key for the persisted proof index of the claim stored at the claim key
+func KeyForProofIndex(claimKey []byte) []byte {
+	return append(append([]byte{}, ProofIndexKey...), claimKey[ClaimLen:]...)
+}
+
+// "KeyForVerifiedRelays" - Generates the key for the total relays verified for the servicer
+func KeyForVerifiedRelays(addr sdk.Address) []byte {
+	return append(append([]byte{}, VerifiedRelaysKey...), addr.Bytes()...)
+}
+
+// "KeyForClaimer" - Generates the key for the claimer the servicer delegated to
+func KeyForClaimer(servicer sdk.Address) []byte {
+	return append(append([]byte{}, ClaimerKey...), servicer.Bytes()...)
+}
+
+// "KeyForProofCount" - Generates the key for the number of proofs the address submitted this block
+func KeyForProofCount(addr sdk.Address) []byte {
+	return append(append([]byte{}, ProofCountKey...), addr.Bytes()...)
+}
+
+// "KeyForAppProofRotation" - Generates the key for the number of relay proofs verified for the application
+func KeyForAppProofRotation(appPubKey string) []byte {
+	return append(append([]byte{}, AppProofRotationKey...), []byte(appPubKey)...)
+}
+
 // "KeyForClaim" - Generates the key for the claim object for the state store
 func KeyForClaim(ctx sdk.Ctx, addr sdk.Address, header SessionHeader, evidenceType EvidenceType) ([]byte, error) {
 	// validat the header
@@ -34,7 +73,7 @@ func KeyForClaim(ctx sdk.Ctx, addr sdk.Address, header SessionHeader, evidenceTy
 		return nil, err
 	}
 	// return the key bz
-	return append(append(append(ClaimKey, addr.Bytes()...), header.Hash()...), et), nil
+	return append(append(append(ClaimKey, addr.Bytes()...), header.KeyHash()...), et), nil
 }
 
 // "KeyForClaims" - Generates the key for the claims object
@@ -47,6 +86,34 @@ func KeyForClaims(addr sdk.Address) ([]byte, error) {
 	return append(ClaimKey, addr.Bytes()...), nil
 }
 
+// "KeyForClaimMaturityIndex" - Generates the key for the maturity index entry of the claim object
+func KeyForClaimMaturityIndex(ctx sdk.Ctx, addr sdk.Address, header SessionHeader, evidenceType EvidenceType) ([]byte, error) {
+	// generate the claim key (validates the header, address and evidence type)
+	claimKey, err := KeyForClaim(ctx, addr, header, evidenceType)
+	if err != nil {
+		return nil, err
+	}
+	prefix, err := KeyForClaimMaturityIndexes(addr, header.SessionBlockHeight)
+	if err != nil {
+		return nil, err
+	}
+	// return the key bz: the index prefix followed by the header hash and evidence type of the claim key
+	return append(prefix, claimKey[ClaimLen+len(addr):]...), nil
+}
+
+// "KeyForClaimMaturityIndexes" - Generates the key for the maturity index entries of the address's claims at the session height
+func KeyForClaimMaturityIndexes(addr sdk.Address, sessionBlockHeight int64) ([]byte, error) {
+	// verify the address
+	if err := AddressVerification(addr.String()); err != nil {
+		return nil, err
+	}
+	// big endian so the entries are iterated by session height
+	height := make([]byte, 8)
+	binary.BigEndian.PutUint64(height, uint64(sessionBlockHeight))
+	// return the key bz
+	return append(append(append([]byte{}, ClaimMaturityIndexKey...), addr.Bytes()...), height...), nil
+}
+
 // "KeyForEvidence" - Generates the key for GOBEvidence
 func KeyForEvidence(header SessionHeader, evidenceType EvidenceType) ([]byte, error) {
 	// validate the GOBEvidence type
@@ -57,5 +124,5 @@ func KeyForEvidence(header SessionHeader, evidenceType EvidenceType) ([]byte, er
 	if err != nil {
 		return nil, err
 	}
-	return append(header.Hash(), et), nil
+	return append(header.KeyHash(), et), nil
 }
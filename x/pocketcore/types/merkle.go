@@ -5,7 +5,9 @@ import (
 	"encoding/binary"
 	"sort"
 	"strconv"
+	"unsafe"
 
+	"github.com/pokt-network/pocket-core/codec"
 	"golang.org/x/crypto/blake2b"
 )
 
@@ -48,11 +50,23 @@ func (hr HashRange) isValidRange() bool {
 	return true
 }
 
+// "IsZero" - Returns whether the hash is empty or all zero bytes (no tree of relay proofs hashes to it)
+func (hr HashRange) IsZero() bool {
+	for _, b := range hr.Hash {
+		if b != 0 {
+			return false
+		}
+	}
+	return true
+}
+
 func (hr HashRange) Equal(hr2 HashRange) bool {
 	return bytes.Equal(hr.Hash, hr2.Hash) && hr.Range.Lower == hr2.Range.Lower && hr.Range.Upper == hr2.Range.Upper
 }
 
 // "Validate" - Verifies the Proof from the leaf/cousin node data, the merkle root, and the Proof object
+// NOTE: the final leaf of an odd number of proofs is paired with the padding sentinel (see paddingHashRange), so its
+// first sibling is verified exactly like any other sibling; no special casing is needed for the odd leaf
 func (mp MerkleProof) Validate(height int64, root HashRange, leaf Proof, numOfLevels int) (isValid bool, isReplayAttack bool) {
 	// ensure root lower is zero
 	if root.Range.Lower != 0 {
@@ -151,7 +165,7 @@ func GenerateProofs(height int64, p []Proof, index int) (mProof MerkleProof, lea
 func merkleProof(height int64, data []HashRange, index int, p *MerkleProof) MerkleProof {
 	if index%2 == 1 { // odd index so sibling to the left
 		p.HashRanges = append(p.HashRanges, data[index-1])
-	} else { // even index so sibling to the right
+	} else { // even index so sibling to the right (the padding sentinel if this is the final leaf of an odd set)
 		p.HashRanges = append(p.HashRanges, data[index+1])
 	}
 	data, atRoot := levelUp(height, data)
@@ -162,8 +176,21 @@ func merkleProof(height int64, data []HashRange, index int, p *MerkleProof) Merk
 	return *p
 }
 
+// "IsSortedPairMerkle" - Returns true if the merkle tree of a session at this height is built in sorted-pair mode
+// The mode is recorded by the session block height of the claim: claims of sessions that started at or after the
+// SortedPairMerkleKey activation height are generated and validated with sorted siblings, older claims keep the
+// positional encoding they were committed with
+func IsSortedPairMerkle(height int64) bool {
+	return ModuleCdc.IsAfterNamedFeatureActivationHeight(height, codec.SortedPairMerkleKey)
+}
+
 // "newParentHash" - Compute the merkleHash of the parent by hashing the hashes, sum and parent
+// NOTE: in sorted-pair mode the child hashes are ordered bytewise before combining, so the parent hash does not depend
+// on which side the sibling sits; the range still does, as the ranges are adjacent by construction
 func parentHash(height int64, hash1, hash2 []byte, r Range, index1, index2 uint64) []byte {
+	if IsSortedPairMerkle(height) && bytes.Compare(hash1, hash2) > 0 {
+		hash1, hash2 = hash2, hash1
+	}
 	if ModuleCdc.IsAfterCodecUpgrade(height) {
 		return merkleHash(MultiAppend(make([]byte, MerkleHashLength*2+32), hash1, hash2, uint64ToBytes(index1, index2), r.Bytes()))
 	}
@@ -176,6 +203,18 @@ func merkleHash(data []byte) []byte {
 	return hash[:]
 }
 
+// "EstimateMerkleMemory" - Returns an estimate of the bytes allocated to build the merkle tree of totalRelays leafs: the
+// leaf level is padded to a power of two, each node holds a hash range and its hash, and the proofs are sorted in a copy
+func EstimateMerkleMemory(totalRelays int64) int {
+	if totalRelays <= 0 {
+		return 0
+	}
+	leafs := int(nextPowerOfTwo(uint(totalRelays)))
+	hashRangeSize := int(unsafe.Sizeof(HashRange{}))
+	proofSize := int(unsafe.Sizeof(Proof(nil)))
+	return leafs*(hashRangeSize+MerkleHashLength) + (leafs-1)*MerkleHashLength + int(totalRelays)*proofSize
+}
+
 // "GenerateRoot" - generates the merkle root from leaf node data
 func GenerateRoot(height int64, data []Proof) (r HashRange, sortedData []Proof) {
 	// structure the leafs
@@ -253,10 +292,7 @@ func sortAndStructure(proofs []Proof) (d []HashRange, sortedProofs []Proof) { //
 	hashRanges = append(hashRanges, padding...)
 	// add padding to the end of the hashRange
 	for i := numberOfProofs; i < int(properLength); i++ {
-		hashRanges[i] = HashRange{
-			Hash:  merkleHash([]byte(strconv.Itoa(i))),
-			Range: Range{Lower: lower, Upper: lower + 1},
-		}
+		hashRanges[i] = paddingHashRange(i, lower)
 		lower = hashRanges[i].Range.Upper
 	}
 	return hashRanges, proofs
@@ -292,15 +328,22 @@ func structureProofs(proofs []Proof) (d []HashRange, sortedProofs []Proof) {
 	hashRanges = append(hashRanges, padding...)
 	// add padding to the end of the hashRange
 	for i := numberOfProofs; i < int(properLength); i++ {
-		hashRanges[i] = HashRange{
-			Hash:  merkleHash([]byte(strconv.Itoa(i))),
-			Range: Range{Lower: lower, Upper: lower + 1},
-		}
+		hashRanges[i] = paddingHashRange(i, lower)
 		lower = hashRanges[i].Range.Upper
 	}
 	return hashRanges, proofs
 }
 
+// "paddingHashRange" - Returns the sentinel leaf placed at a padding index of the tree
+// When the number of proofs is odd, the final real leaf has no real cousin; it is always paired with the sentinel
+// at index numberOfProofs, whose range starts where the final leaf ends (lower) and spans exactly one unit
+func paddingHashRange(index int, lower uint64) HashRange {
+	return HashRange{
+		Hash:  merkleHash([]byte(strconv.Itoa(index))),
+		Range: Range{Lower: lower, Upper: lower + 1},
+	}
+}
+
 func MultiAppend(dest []byte, s ...[]byte) []byte {
 	i := 0
 	for _, v := range s {
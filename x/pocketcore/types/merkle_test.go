@@ -10,6 +10,8 @@ import (
 	"testing"
 	"time"
 
+	"github.com/pokt-network/pocket-core/codec"
+	sdk "github.com/pokt-network/pocket-core/types"
 	"github.com/stretchr/testify/assert"
 	"github.com/willf/bloom"
 )
@@ -216,6 +218,71 @@ func TestEvidence_GenerateMerkleProof(t *testing.T) {
 	assert.Equal(t, proof.Target.Hash, merkleHash(leaf.Bytes()))
 }
 
+func TestEvidence_GenerateMerkleProofForIndex(t *testing.T) {
+	appPrivateKey := GetRandomPrivateKey()
+	appPubKey := appPrivateKey.PublicKey().RawString()
+	clientPrivateKey := GetRandomPrivateKey()
+	nodePubKey := getRandomPubKey()
+	ethereum := hex.EncodeToString([]byte{01})
+	validAAT := AAT{
+		Version:              "0.0.1",
+		ApplicationPublicKey: appPubKey,
+		ClientPublicKey:      clientPrivateKey.PublicKey().RawString(),
+		ApplicationSignature: "",
+	}
+	appSig, er := appPrivateKey.Sign(validAAT.Hash())
+	if er != nil {
+		t.Fatalf(er.Error())
+	}
+	validAAT.ApplicationSignature = hex.EncodeToString(appSig)
+	i := Evidence{
+		Bloom: *bloom.New(10000, 4),
+		SessionHeader: SessionHeader{
+			ApplicationPubKey:  appPubKey,
+			Chain:              ethereum,
+			SessionBlockHeight: 1,
+		},
+		EvidenceType: RelayEvidence,
+	}
+	for _, entropy := range []int64{3238283, 34939492, 12383, 96384, 96384812} {
+		i.AddProof(RelayProof{
+			Entropy:            entropy,
+			SessionBlockHeight: 1,
+			ServicerPubKey:     nodePubKey.RawString(),
+			RequestHash:        validAAT.HashString(), // fake
+			Blockchain:         ethereum,
+			Token:              validAAT,
+			Signature:          "",
+		})
+	}
+	original := make(Proofs, len(i.Proofs))
+	copy(original, i.Proofs)
+	root, _ := GenerateRoot(0, append([]Proof{}, i.Proofs...))
+	// first, middle and last leaf
+	for _, index := range []int{0, 2, 4} {
+		proof, leaf, err := i.GenerateMerkleProofForIndex(0, index, 5)
+		assert.Nil(t, err)
+		assert.Equal(t, int64(index), proof.TargetIndex)
+		assert.Contains(t, i.Proofs, leaf)
+		isValid, _ := proof.Validate(0, root, leaf, len(proof.HashRanges))
+		assert.True(t, isValid)
+	}
+	// the evidence itself is not sorted or truncated
+	_, _, err := i.GenerateMerkleProofForIndex(0, 1, 3)
+	assert.Nil(t, err)
+	assert.Equal(t, original, i.Proofs)
+	// out of range
+	for _, tc := range []struct {
+		index     int
+		maxRelays int64
+	}{{-1, 5}, {5, 5}, {3, 3}} {
+		_, leaf, err := i.GenerateMerkleProofForIndex(0, tc.index, tc.maxRelays)
+		assert.NotNil(t, err)
+		assert.Nil(t, leaf)
+		assert.Equal(t, sdk.CodeType(CodeMerkleIndexOutOfRangeError), err.Code())
+	}
+}
+
 func TestEvidence_VerifyMerkleProof(t *testing.T) {
 	appPrivateKey := GetRandomPrivateKey()
 	appPubKey := appPrivateKey.PublicKey().RawString()
@@ -534,3 +601,112 @@ func Benchmark_sortAndStructure(b *testing.B) {
 		})
 	}
 }
+
+func TestEvidence_GenerateMerkleProofOddLastLeaf(t *testing.T) {
+	appPrivateKey := GetRandomPrivateKey()
+	clientPrivateKey := GetRandomPrivateKey()
+	nodePubKey := getRandomPubKey()
+	ethereum := hex.EncodeToString([]byte{01})
+	validAAT := AAT{
+		Version:              "0.0.1",
+		ApplicationPublicKey: appPrivateKey.PublicKey().RawString(),
+		ClientPublicKey:      clientPrivateKey.PublicKey().RawString(),
+		ApplicationSignature: "",
+	}
+	appSig, er := appPrivateKey.Sign(validAAT.Hash())
+	if er != nil {
+		t.Fatalf(er.Error())
+	}
+	validAAT.ApplicationSignature = hex.EncodeToString(appSig)
+	for _, totalRelays := range []int{5, 7, 9, 13} {
+		proofs := make([]Proof, totalRelays)
+		for j := 0; j < totalRelays; j++ {
+			proofs[j] = RelayProof{
+				Entropy:            int64(j + 1),
+				SessionBlockHeight: 1,
+				ServicerPubKey:     nodePubKey.RawString(),
+				RequestHash:        validAAT.HashString(), // fake
+				Blockchain:         ethereum,
+				Token:              validAAT,
+				Signature:          "",
+			}
+		}
+		root, sorted := GenerateRoot(0, proofs)
+		// the final leaf of an odd set has no real cousin
+		index := totalRelays - 1
+		mProof, leaf := GenerateProofs(0, sorted, index)
+		assert.Equal(t, sorted[index], leaf)
+		// the first sibling is the padding sentinel that starts where the final leaf ends
+		assert.Equal(t, paddingHashRange(totalRelays, mProof.Target.Range.Upper), mProof.HashRanges[0])
+		isValid, isReplayAttack := mProof.Validate(0, root, leaf, len(mProof.HashRanges))
+		assert.True(t, isValid, "odd last leaf proof should validate for %d relays", totalRelays)
+		assert.False(t, isReplayAttack)
+	}
+}
+
+func TestEvidence_GenerateMerkleProofSortedPair(t *testing.T) {
+	codec.UpgradeFeatureMap[codec.SortedPairMerkleKey] = 10
+	t.Cleanup(func() {
+		delete(codec.UpgradeFeatureMap, codec.SortedPairMerkleKey)
+	})
+	appPrivateKey := GetRandomPrivateKey()
+	clientPrivateKey := GetRandomPrivateKey()
+	nodePubKey := getRandomPubKey()
+	ethereum := hex.EncodeToString([]byte{01})
+	validAAT := AAT{
+		Version:              "0.0.1",
+		ApplicationPublicKey: appPrivateKey.PublicKey().RawString(),
+		ClientPublicKey:      clientPrivateKey.PublicKey().RawString(),
+		ApplicationSignature: "",
+	}
+	appSig, er := appPrivateKey.Sign(validAAT.Hash())
+	if er != nil {
+		t.Fatalf(er.Error())
+	}
+	validAAT.ApplicationSignature = hex.EncodeToString(appSig)
+	assert.False(t, IsSortedPairMerkle(9))
+	assert.True(t, IsSortedPairMerkle(10))
+	// in sorted-pair mode the parent hash does not depend on the order of the children
+	left, right := merkleHash([]byte("left")), merkleHash([]byte("right"))
+	r := Range{Lower: 0, Upper: 2}
+	assert.Equal(t, parentHash(10, left, right, r, 0, 1), parentHash(10, right, left, r, 0, 1))
+	assert.NotEqual(t, parentHash(9, left, right, r, 0, 1), parentHash(9, right, left, r, 0, 1))
+	for _, totalRelays := range []int{2, 5, 8, 13} {
+		proofs := make([]Proof, totalRelays)
+		for j := 0; j < totalRelays; j++ {
+			proofs[j] = RelayProof{
+				Entropy:            int64(j + 1),
+				SessionBlockHeight: 1,
+				ServicerPubKey:     nodePubKey.RawString(),
+				RequestHash:        validAAT.HashString(), // fake
+				Blockchain:         ethereum,
+				Token:              validAAT,
+				Signature:          "",
+			}
+		}
+		positionalRoot, _ := GenerateRoot(9, proofs)
+		root, sorted := GenerateRoot(10, proofs)
+		// the ranges are the same in both modes
+		assert.Equal(t, positionalRoot.Range, root.Range)
+		for index := 0; index < totalRelays; index++ {
+			mProof, leaf := GenerateProofs(10, sorted, index)
+			isValid, isReplayAttack := mProof.Validate(10, root, leaf, len(mProof.HashRanges))
+			assert.True(t, isValid, "sorted-pair proof should validate for index %d of %d relays", index, totalRelays)
+			assert.False(t, isReplayAttack)
+		}
+	}
+}
+
+func TestEstimateMerkleMemory(t *testing.T) {
+	assert.Zero(t, EstimateMerkleMemory(0))
+	assert.Zero(t, EstimateMerkleMemory(-1))
+	previous := 0
+	for _, totalRelays := range []int64{1, 2, 10, 100, 1000, 10000} {
+		estimate := EstimateMerkleMemory(totalRelays)
+		assert.Greater(t, estimate, previous, "estimate should grow with the relay count (%d relays)", totalRelays)
+		previous = estimate
+	}
+	// the tree size is linear in the (padded) leaf count
+	small, large := EstimateMerkleMemory(1024), EstimateMerkleMemory(2048)
+	assert.InDelta(t, 2*small, large, float64(small)/100)
+}
@@ -35,6 +35,37 @@ const (
 	AvgClaimTimeHelp        = "the average time in ms to generate the work needed for claim tx:"
 	AvgProofTimeName        = "avg_proof_time_for_"
 	AvgProofTimeHelp        = "the average time in ms to generate the work needed for claim tx:"
+	ProofFailureCountName   = "proof_validation_failures"
+	ProofFailureCountHelp   = "the number of proofs that failed validation, by reason"
+	ProofFailureReasonLabel = "reason"
+	BeginBlockTimeName      = "begin_block_time"
+	BeginBlockTimeHelp      = "the time in ms the begin blocker spends on claim and proof work, by operation"
+	BeginBlockOpLabel       = "operation"
+)
+
+// the claim and proof work of the begin blocker (used as the BeginBlockOpLabel value)
+const (
+	BeginBlockSetClaimMaturityIndex = "set_claim_maturity_index"
+	BeginBlockDeleteExpiredClaims   = "delete_expired_claims"
+)
+
+// reasons a proof fails validation (used as the ProofFailureReasonLabel value)
+const (
+	ProofFailureMalformed       = "malformed_proof"
+	ProofFailureClaimNotFound   = "claim_not_found"
+	ProofFailureHeaderMismatch  = "claim_header_mismatch"
+	ProofFailureLevelCount      = "invalid_level_count"
+	ProofFailureIndexOutOfRange = "index_out_of_range"
+	ProofFailureRootRange       = "merkle_root_range_mismatch"
+	ProofFailureEmptyRoot       = "empty_merkle_root"
+	ProofFailureSessionCtx      = "session_context"
+	ProofFailurePseudorandomIdx = "pseudorandom_index"
+	ProofFailureIndexMismatch   = "index_mismatch"
+	ProofFailureMerkleMismatch  = "merkle_mismatch"
+	ProofFailureReplayAttack    = "replay_attack"
+	ProofFailureAppNotFound     = "app_not_found"
+	ProofFailureInvalidLeaf     = "invalid_leaf"
+	ProofFailureSelfSigned      = "self_signed"
 )
 
 type ServiceMetrics struct {
@@ -42,6 +73,8 @@ type ServiceMetrics struct {
 	tmLogger        log.Logger
 	ServiceMetric   `json:"accumulated_service_metrics"` // total metrics
 	NonNativeChains map[string]ServiceMetric             `json:"individual_service_metrics"` // metrics per chain
+	ProofFailures   metrics.Counter                      `json:"proof_validation_failures"`  // failed proof validations by reason
+	BeginBlockTime  metrics.Histogram                    `json:"begin_block_time"`           // begin blocker claim and proof work by operation
 	prometheusSrv   *http.Server
 }
 
@@ -262,6 +295,28 @@ func (sm *ServiceMetrics) AddUPOKTEarnedFor(networkID string, upoktEarned float6
 	sm.NonNativeChains[networkID] = nnc
 }
 
+// "AddProofValidationFailure" - Counts a rejected proof by reason; proofs are validated by the consensus handler,
+// which also runs where the service metrics were never started (e.g. unit tests and state tooling), so nil is a no-op
+func (sm *ServiceMetrics) AddProofValidationFailure(reason string) {
+	if sm == nil {
+		return
+	}
+	sm.l.Lock()
+	defer sm.l.Unlock()
+	sm.ProofFailures.With(ProofFailureReasonLabel, reason).Add(1)
+}
+
+// "AddBeginBlockTiming" - Observes the time in ms of a begin blocker operation; like proof validation failures, this
+// runs where the service metrics may never have been started, so nil is a no-op
+func (sm *ServiceMetrics) AddBeginBlockTiming(operation string, time float64) {
+	if sm == nil {
+		return
+	}
+	sm.l.Lock()
+	defer sm.l.Unlock()
+	sm.BeginBlockTime.With(BeginBlockOpLabel, operation).Observe(time)
+}
+
 func KeyForServiceMetrics() []byte {
 	return []byte(ServiceMetricsKey)
 }
@@ -270,6 +325,19 @@ func NewServiceMetrics(hostedBlockchains *HostedBlockchains, logger log.Logger)
 	serviceMetrics := ServiceMetrics{
 		ServiceMetric:   NewServiceMetricsFor("all"),
 		NonNativeChains: make(map[string]ServiceMetric),
+		ProofFailures: prometheus.NewCounterFrom(stdPrometheus.CounterOpts{
+			Namespace: ModuleName,
+			Subsystem: ServiceMetricsNamespace,
+			Name:      ProofFailureCountName,
+			Help:      ProofFailureCountHelp,
+		}, []string{ProofFailureReasonLabel}),
+		BeginBlockTime: prometheus.NewHistogramFrom(stdPrometheus.HistogramOpts{
+			Namespace: ModuleName,
+			Subsystem: ServiceMetricsNamespace,
+			Name:      BeginBlockTimeName,
+			Help:      BeginBlockTimeHelp,
+			Buckets:   stdPrometheus.ExponentialBuckets(0.1, 2, 16),
+		}, []string{BeginBlockOpLabel}),
 	}
 	if hostedBlockchains != nil {
 		for _, hb := range hostedBlockchains.M {
@@ -0,0 +1,1073 @@
+// Code generated by protoc-gen-gogo. DO NOT EDIT.
+// source: x/pocketcore/msg.proto
+
+package types
+
+import (
+	fmt "fmt"
+	_ "github.com/gogo/protobuf/gogoproto"
+	proto "github.com/gogo/protobuf/proto"
+	github_com_pokt_network_pocket_core_types "github.com/pokt-network/pocket-core/types"
+	io "io"
+	math "math"
+	math_bits "math/bits"
+)
+
+// Reference imports to suppress errors if they are not otherwise used.
+var _ = proto.Marshal
+var _ = fmt.Errorf
+var _ = math.Inf
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the proto package it is being compiled against.
+// A compilation error at this line likely means your copy of the
+// proto package needs to be updated.
+const _ = proto.GoGoProtoPackageIsVersion3 // please upgrade the proto package
+
+// MsgProtoProofBatch is the encoding of MsgProofBatch
+type MsgProtoProofBatch struct {
+	Proofs       []MsgProtoProof `protobuf:"bytes,1,rep,name=proofs,proto3" json:"proofs"`
+	AllOrNothing bool            `protobuf:"varint,2,opt,name=allOrNothing,proto3" json:"all_or_nothing"`
+}
+
+func (m *MsgProtoProofBatch) Reset()         { *m = MsgProtoProofBatch{} }
+func (m *MsgProtoProofBatch) String() string { return proto.CompactTextString(m) }
+func (*MsgProtoProofBatch) ProtoMessage()    {}
+func (*MsgProtoProofBatch) Descriptor() ([]byte, []int) {
+	return fileDescriptor_fd930ef1a3715a16, []int{0}
+}
+func (m *MsgProtoProofBatch) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
+}
+func (m *MsgProtoProofBatch) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	if deterministic {
+		return xxx_messageInfo_MsgProtoProofBatch.Marshal(b, m, deterministic)
+	} else {
+		b = b[:cap(b)]
+		n, err := m.MarshalToSizedBuffer(b)
+		if err != nil {
+			return nil, err
+		}
+		return b[:n], nil
+	}
+}
+func (m *MsgProtoProofBatch) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_MsgProtoProofBatch.Merge(m, src)
+}
+func (m *MsgProtoProofBatch) XXX_Size() int {
+	return m.Size()
+}
+func (m *MsgProtoProofBatch) XXX_DiscardUnknown() {
+	xxx_messageInfo_MsgProtoProofBatch.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_MsgProtoProofBatch proto.InternalMessageInfo
+
+func (*MsgProtoProofBatch) XXX_MessageName() string {
+	return "x.pocketcore.MsgProtoProofBatch"
+}
+
+// MsgDelegateClaimer authorizes (or with an empty claimer revokes) a claimer to send the claims and proofs of a servicer
+type MsgDelegateClaimer struct {
+	ServicerAddress github_com_pokt_network_pocket_core_types.Address `protobuf:"bytes,1,opt,name=servicerAddress,proto3,casttype=github.com/pokt-network/pocket-core/types.Address" json:"servicer_address"`
+	ClaimerAddress  github_com_pokt_network_pocket_core_types.Address `protobuf:"bytes,2,opt,name=claimerAddress,proto3,casttype=github.com/pokt-network/pocket-core/types.Address" json:"claimer_address"`
+}
+
+func (m *MsgDelegateClaimer) Reset()         { *m = MsgDelegateClaimer{} }
+func (m *MsgDelegateClaimer) String() string { return proto.CompactTextString(m) }
+func (*MsgDelegateClaimer) ProtoMessage()    {}
+func (*MsgDelegateClaimer) Descriptor() ([]byte, []int) {
+	return fileDescriptor_fd930ef1a3715a16, []int{1}
+}
+func (m *MsgDelegateClaimer) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
+}
+func (m *MsgDelegateClaimer) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	if deterministic {
+		return xxx_messageInfo_MsgDelegateClaimer.Marshal(b, m, deterministic)
+	} else {
+		b = b[:cap(b)]
+		n, err := m.MarshalToSizedBuffer(b)
+		if err != nil {
+			return nil, err
+		}
+		return b[:n], nil
+	}
+}
+func (m *MsgDelegateClaimer) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_MsgDelegateClaimer.Merge(m, src)
+}
+func (m *MsgDelegateClaimer) XXX_Size() int {
+	return m.Size()
+}
+func (m *MsgDelegateClaimer) XXX_DiscardUnknown() {
+	xxx_messageInfo_MsgDelegateClaimer.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_MsgDelegateClaimer proto.InternalMessageInfo
+
+func (*MsgDelegateClaimer) XXX_MessageName() string {
+	return "x.pocketcore.MsgDelegateClaimer"
+}
+
+// MsgDelegatedClaim is a claim of a servicer sent (and signed) by the claimer it delegated to
+type MsgDelegatedClaim struct {
+	Claim          MsgClaim                                          `protobuf:"bytes,1,opt,name=claim,proto3" json:"claim"`
+	ClaimerAddress github_com_pokt_network_pocket_core_types.Address `protobuf:"bytes,2,opt,name=claimerAddress,proto3,casttype=github.com/pokt-network/pocket-core/types.Address" json:"claimer_address"`
+	// optional: the servicer's signature of the claim's commitment (see MsgClaim.CommitmentBytes), so the claimer can't
+	// pair the servicer's merkle root with another relay count
+	Commitment []byte `protobuf:"bytes,3,opt,name=commitment,proto3" json:"commitment,omitempty"`
+}
+
+func (m *MsgDelegatedClaim) Reset()         { *m = MsgDelegatedClaim{} }
+func (m *MsgDelegatedClaim) String() string { return proto.CompactTextString(m) }
+func (*MsgDelegatedClaim) ProtoMessage()    {}
+func (*MsgDelegatedClaim) Descriptor() ([]byte, []int) {
+	return fileDescriptor_fd930ef1a3715a16, []int{2}
+}
+func (m *MsgDelegatedClaim) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
+}
+func (m *MsgDelegatedClaim) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	if deterministic {
+		return xxx_messageInfo_MsgDelegatedClaim.Marshal(b, m, deterministic)
+	} else {
+		b = b[:cap(b)]
+		n, err := m.MarshalToSizedBuffer(b)
+		if err != nil {
+			return nil, err
+		}
+		return b[:n], nil
+	}
+}
+func (m *MsgDelegatedClaim) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_MsgDelegatedClaim.Merge(m, src)
+}
+func (m *MsgDelegatedClaim) XXX_Size() int {
+	return m.Size()
+}
+func (m *MsgDelegatedClaim) XXX_DiscardUnknown() {
+	xxx_messageInfo_MsgDelegatedClaim.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_MsgDelegatedClaim proto.InternalMessageInfo
+
+func (*MsgDelegatedClaim) XXX_MessageName() string {
+	return "x.pocketcore.MsgDelegatedClaim"
+}
+
+// MsgProtoDelegatedProof is the encoding of MsgDelegatedProof
+type MsgProtoDelegatedProof struct {
+	Proof          MsgProtoProof                                     `protobuf:"bytes,1,opt,name=proof,proto3" json:"proof"`
+	ClaimerAddress github_com_pokt_network_pocket_core_types.Address `protobuf:"bytes,2,opt,name=claimerAddress,proto3,casttype=github.com/pokt-network/pocket-core/types.Address" json:"claimer_address"`
+}
+
+func (m *MsgProtoDelegatedProof) Reset()         { *m = MsgProtoDelegatedProof{} }
+func (m *MsgProtoDelegatedProof) String() string { return proto.CompactTextString(m) }
+func (*MsgProtoDelegatedProof) ProtoMessage()    {}
+func (*MsgProtoDelegatedProof) Descriptor() ([]byte, []int) {
+	return fileDescriptor_fd930ef1a3715a16, []int{3}
+}
+func (m *MsgProtoDelegatedProof) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
+}
+func (m *MsgProtoDelegatedProof) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	if deterministic {
+		return xxx_messageInfo_MsgProtoDelegatedProof.Marshal(b, m, deterministic)
+	} else {
+		b = b[:cap(b)]
+		n, err := m.MarshalToSizedBuffer(b)
+		if err != nil {
+			return nil, err
+		}
+		return b[:n], nil
+	}
+}
+func (m *MsgProtoDelegatedProof) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_MsgProtoDelegatedProof.Merge(m, src)
+}
+func (m *MsgProtoDelegatedProof) XXX_Size() int {
+	return m.Size()
+}
+func (m *MsgProtoDelegatedProof) XXX_DiscardUnknown() {
+	xxx_messageInfo_MsgProtoDelegatedProof.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_MsgProtoDelegatedProof proto.InternalMessageInfo
+
+func (*MsgProtoDelegatedProof) XXX_MessageName() string {
+	return "x.pocketcore.MsgProtoDelegatedProof"
+}
+func init() {
+	proto.RegisterType((*MsgProtoProofBatch)(nil), "x.pocketcore.MsgProtoProofBatch")
+	proto.RegisterType((*MsgDelegateClaimer)(nil), "x.pocketcore.MsgDelegateClaimer")
+	proto.RegisterType((*MsgDelegatedClaim)(nil), "x.pocketcore.MsgDelegatedClaim")
+	proto.RegisterType((*MsgProtoDelegatedProof)(nil), "x.pocketcore.MsgProtoDelegatedProof")
+}
+
+func init() { proto.RegisterFile("x/pocketcore/msg.proto", fileDescriptor_fd930ef1a3715a16) }
+
+var fileDescriptor_fd930ef1a3715a16 = []byte{
+	// 455 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xc4, 0x53, 0x3f, 0x8f, 0xd3, 0x30,
+	0x1c, 0x8d, 0x73, 0xba, 0xd3, 0xc9, 0x57, 0x7a, 0x60, 0x9d, 0xaa, 0x70, 0x48, 0xf1, 0xe9, 0xa6,
+	0x0e, 0x5c, 0x22, 0x8a, 0x54, 0x21, 0x58, 0x20, 0x65, 0x80, 0x01, 0xa8, 0x32, 0xb2, 0x54, 0x69,
+	0x6a, 0xd2, 0xa8, 0x71, 0x1c, 0x39, 0x06, 0xda, 0x0f, 0x00, 0xea, 0xc8, 0xce, 0x82, 0xf8, 0x34,
+	0x1d, 0x3b, 0x21, 0xa6, 0x08, 0xb5, 0x5b, 0x3e, 0x02, 0x13, 0x8a, 0x9d, 0xb6, 0x49, 0x91, 0x10,
+	0x12, 0x12, 0x37, 0xf9, 0xcf, 0x7b, 0xcf, 0xcf, 0x7e, 0xbf, 0x9f, 0x61, 0x6b, 0x6a, 0x27, 0xcc,
+	0x9f, 0x10, 0xe1, 0x33, 0x4e, 0x6c, 0x9a, 0x06, 0x56, 0xc2, 0x99, 0x60, 0xa8, 0x31, 0xb5, 0x76,
+	0xfb, 0xe7, 0x67, 0x01, 0x0b, 0x98, 0x04, 0xec, 0x62, 0xa6, 0x38, 0xe7, 0xb7, 0x6b, 0x5a, 0x35,
+	0x55, 0xd0, 0xe5, 0x67, 0x00, 0xd1, 0x8b, 0x34, 0xe8, 0x17, 0x8b, 0x3e, 0x67, 0xec, 0x8d, 0xe3,
+	0x09, 0x7f, 0x8c, 0x7a, 0xf0, 0x28, 0x29, 0x56, 0xa9, 0x01, 0x2e, 0x0e, 0xda, 0x27, 0x9d, 0x3b,
+	0x56, 0xd5, 0xc6, 0xaa, 0x2b, 0x9a, 0x8b, 0x0c, 0x6b, 0x79, 0x86, 0x4b, 0x89, 0x5b, 0x8e, 0xa8,
+	0x0b, 0x1b, 0x5e, 0x14, 0xbd, 0xe2, 0x2f, 0x99, 0x18, 0x87, 0x71, 0x60, 0xe8, 0x17, 0xa0, 0x7d,
+	0xec, 0xa0, 0x3c, 0xc3, 0x4d, 0x2f, 0x8a, 0x06, 0x8c, 0x0f, 0x62, 0x85, 0xb8, 0x35, 0xde, 0xc3,
+	0xe3, 0xf9, 0x17, 0xac, 0xcd, 0xbf, 0x62, 0x70, 0xf9, 0x41, 0x97, 0xb7, 0x7b, 0x4a, 0x22, 0x12,
+	0x78, 0x82, 0xf4, 0x22, 0x2f, 0xa4, 0x84, 0xa3, 0x14, 0x9e, 0xa6, 0x84, 0xbf, 0x0b, 0x7d, 0xc2,
+	0x9f, 0x8c, 0x46, 0x9c, 0xa4, 0xc5, 0x35, 0x41, 0xbb, 0xe1, 0x3c, 0xcf, 0x33, 0x7c, 0x73, 0x03,
+	0x0d, 0x3c, 0x85, 0xfd, 0xcc, 0xf0, 0xbd, 0x20, 0x14, 0xe3, 0xb7, 0x43, 0xcb, 0x67, 0xd4, 0x4e,
+	0xd8, 0x44, 0x5c, 0xc5, 0x44, 0xbc, 0x67, 0x7c, 0x52, 0x66, 0x71, 0x25, 0x73, 0x11, 0xb3, 0x84,
+	0xa4, 0x56, 0x79, 0xa0, 0xbb, 0xef, 0x80, 0x12, 0xd8, 0xf4, 0x95, 0xff, 0xc6, 0x53, 0x97, 0x9e,
+	0xcf, 0xf2, 0x0c, 0x9f, 0x96, 0xc8, 0xbf, 0x59, 0xee, 0x9d, 0x5f, 0xc9, 0xe1, 0xa3, 0x0e, 0x6f,
+	0x55, 0x72, 0x18, 0xc9, 0x20, 0xd0, 0x23, 0x78, 0x28, 0x15, 0xf2, 0xf1, 0x27, 0x9d, 0xd6, 0x6f,
+	0x35, 0x92, 0x34, 0xe7, 0x46, 0x59, 0x1e, 0x45, 0x76, 0xd5, 0xf0, 0xff, 0x9f, 0x83, 0x1e, 0x40,
+	0xe8, 0x33, 0x4a, 0x43, 0x41, 0x49, 0x2c, 0x8c, 0x03, 0xe9, 0x66, 0xe4, 0x19, 0x3e, 0xdb, 0xed,
+	0xde, 0x65, 0x34, 0x14, 0x84, 0x26, 0x62, 0xe6, 0x56, 0xb8, 0x95, 0x20, 0xbe, 0x01, 0xd8, 0xda,
+	0x34, 0xdf, 0x36, 0x0d, 0xd9, 0x85, 0xe8, 0x31, 0x3c, 0x94, 0x7d, 0x57, 0xa6, 0xf1, 0xc7, 0x8e,
+	0xdd, 0x46, 0x22, 0x15, 0xae, 0x1a, 0xae, 0xb3, 0xc2, 0x4e, 0x7f, 0xb1, 0x32, 0xc1, 0x72, 0x65,
+	0x82, 0x1f, 0x2b, 0x13, 0x7c, 0x5a, 0x9b, 0xda, 0x72, 0x6d, 0x6a, 0xdf, 0xd7, 0xa6, 0xf6, 0xba,
+	0xfb, 0x37, 0x36, 0xb5, 0x0f, 0x2e, 0x3d, 0x87, 0x47, 0xf2, 0x83, 0xdf, 0xff, 0x35, 0x00, 0xba,
+	0x5e, 0x14, 0x5e, 0x39, 0x04, 0x00, 0x00,
+}
+
+func (m *MsgProtoProofBatch) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *MsgProtoProofBatch) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *MsgProtoProofBatch) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
+	var l int
+	_ = l
+	if m.AllOrNothing {
+		i--
+		if m.AllOrNothing {
+			dAtA[i] = 1
+		} else {
+			dAtA[i] = 0
+		}
+		i--
+		dAtA[i] = 0x10
+	}
+	if len(m.Proofs) > 0 {
+		for iNdEx := len(m.Proofs) - 1; iNdEx >= 0; iNdEx-- {
+			{
+				size, err := m.Proofs[iNdEx].MarshalToSizedBuffer(dAtA[:i])
+				if err != nil {
+					return 0, err
+				}
+				i -= size
+				i = encodeVarintMsg(dAtA, i, uint64(size))
+			}
+			i--
+			dAtA[i] = 0xa
+		}
+	}
+	return len(dAtA) - i, nil
+}
+
+func (m *MsgDelegateClaimer) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *MsgDelegateClaimer) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *MsgDelegateClaimer) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
+	var l int
+	_ = l
+	if len(m.ClaimerAddress) > 0 {
+		i -= len(m.ClaimerAddress)
+		copy(dAtA[i:], m.ClaimerAddress)
+		i = encodeVarintMsg(dAtA, i, uint64(len(m.ClaimerAddress)))
+		i--
+		dAtA[i] = 0x12
+	}
+	if len(m.ServicerAddress) > 0 {
+		i -= len(m.ServicerAddress)
+		copy(dAtA[i:], m.ServicerAddress)
+		i = encodeVarintMsg(dAtA, i, uint64(len(m.ServicerAddress)))
+		i--
+		dAtA[i] = 0xa
+	}
+	return len(dAtA) - i, nil
+}
+
+func (m *MsgDelegatedClaim) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *MsgDelegatedClaim) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *MsgDelegatedClaim) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
+	var l int
+	_ = l
+	if len(m.Commitment) > 0 {
+		i -= len(m.Commitment)
+		copy(dAtA[i:], m.Commitment)
+		i = encodeVarintMsg(dAtA, i, uint64(len(m.Commitment)))
+		i--
+		dAtA[i] = 0x1a
+	}
+	if len(m.ClaimerAddress) > 0 {
+		i -= len(m.ClaimerAddress)
+		copy(dAtA[i:], m.ClaimerAddress)
+		i = encodeVarintMsg(dAtA, i, uint64(len(m.ClaimerAddress)))
+		i--
+		dAtA[i] = 0x12
+	}
+	{
+		size, err := m.Claim.MarshalToSizedBuffer(dAtA[:i])
+		if err != nil {
+			return 0, err
+		}
+		i -= size
+		i = encodeVarintMsg(dAtA, i, uint64(size))
+	}
+	i--
+	dAtA[i] = 0xa
+	return len(dAtA) - i, nil
+}
+
+func (m *MsgProtoDelegatedProof) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *MsgProtoDelegatedProof) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *MsgProtoDelegatedProof) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
+	var l int
+	_ = l
+	if len(m.ClaimerAddress) > 0 {
+		i -= len(m.ClaimerAddress)
+		copy(dAtA[i:], m.ClaimerAddress)
+		i = encodeVarintMsg(dAtA, i, uint64(len(m.ClaimerAddress)))
+		i--
+		dAtA[i] = 0x12
+	}
+	{
+		size, err := m.Proof.MarshalToSizedBuffer(dAtA[:i])
+		if err != nil {
+			return 0, err
+		}
+		i -= size
+		i = encodeVarintMsg(dAtA, i, uint64(size))
+	}
+	i--
+	dAtA[i] = 0xa
+	return len(dAtA) - i, nil
+}
+
+func encodeVarintMsg(dAtA []byte, offset int, v uint64) int {
+	offset -= sovMsg(v)
+	base := offset
+	for v >= 1<<7 {
+		dAtA[offset] = uint8(v&0x7f | 0x80)
+		v >>= 7
+		offset++
+	}
+	dAtA[offset] = uint8(v)
+	return base
+}
+func (m *MsgProtoProofBatch) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	if len(m.Proofs) > 0 {
+		for _, e := range m.Proofs {
+			l = e.Size()
+			n += 1 + l + sovMsg(uint64(l))
+		}
+	}
+	if m.AllOrNothing {
+		n += 2
+	}
+	return n
+}
+
+func (m *MsgDelegateClaimer) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	l = len(m.ServicerAddress)
+	if l > 0 {
+		n += 1 + l + sovMsg(uint64(l))
+	}
+	l = len(m.ClaimerAddress)
+	if l > 0 {
+		n += 1 + l + sovMsg(uint64(l))
+	}
+	return n
+}
+
+func (m *MsgDelegatedClaim) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	l = m.Claim.Size()
+	n += 1 + l + sovMsg(uint64(l))
+	l = len(m.ClaimerAddress)
+	if l > 0 {
+		n += 1 + l + sovMsg(uint64(l))
+	}
+	l = len(m.Commitment)
+	if l > 0 {
+		n += 1 + l + sovMsg(uint64(l))
+	}
+	return n
+}
+
+func (m *MsgProtoDelegatedProof) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	l = m.Proof.Size()
+	n += 1 + l + sovMsg(uint64(l))
+	l = len(m.ClaimerAddress)
+	if l > 0 {
+		n += 1 + l + sovMsg(uint64(l))
+	}
+	return n
+}
+
+func sovMsg(x uint64) (n int) {
+	return (math_bits.Len64(x|1) + 6) / 7
+}
+func sozMsg(x uint64) (n int) {
+	return sovMsg(uint64((x << 1) ^ uint64((int64(x) >> 63))))
+}
+func (m *MsgProtoProofBatch) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowMsg
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= uint64(b&0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: MsgProtoProofBatch: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: MsgProtoProofBatch: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Proofs", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowMsg
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthMsg
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthMsg
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Proofs = append(m.Proofs, MsgProtoProof{})
+			if err := m.Proofs[len(m.Proofs)-1].Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		case 2:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field AllOrNothing", wireType)
+			}
+			var v int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowMsg
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				v |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			m.AllOrNothing = bool(v != 0)
+		default:
+			iNdEx = preIndex
+			skippy, err := skipMsg(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if (skippy < 0) || (iNdEx+skippy) < 0 {
+				return ErrInvalidLengthMsg
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *MsgDelegateClaimer) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowMsg
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= uint64(b&0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: MsgDelegateClaimer: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: MsgDelegateClaimer: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field ServicerAddress", wireType)
+			}
+			var byteLen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowMsg
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				byteLen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if byteLen < 0 {
+				return ErrInvalidLengthMsg
+			}
+			postIndex := iNdEx + byteLen
+			if postIndex < 0 {
+				return ErrInvalidLengthMsg
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.ServicerAddress = append(m.ServicerAddress[:0], dAtA[iNdEx:postIndex]...)
+			if m.ServicerAddress == nil {
+				m.ServicerAddress = []byte{}
+			}
+			iNdEx = postIndex
+		case 2:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field ClaimerAddress", wireType)
+			}
+			var byteLen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowMsg
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				byteLen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if byteLen < 0 {
+				return ErrInvalidLengthMsg
+			}
+			postIndex := iNdEx + byteLen
+			if postIndex < 0 {
+				return ErrInvalidLengthMsg
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.ClaimerAddress = append(m.ClaimerAddress[:0], dAtA[iNdEx:postIndex]...)
+			if m.ClaimerAddress == nil {
+				m.ClaimerAddress = []byte{}
+			}
+			iNdEx = postIndex
+		default:
+			iNdEx = preIndex
+			skippy, err := skipMsg(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if (skippy < 0) || (iNdEx+skippy) < 0 {
+				return ErrInvalidLengthMsg
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *MsgDelegatedClaim) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowMsg
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= uint64(b&0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: MsgDelegatedClaim: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: MsgDelegatedClaim: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Claim", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowMsg
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthMsg
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthMsg
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if err := m.Claim.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		case 2:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field ClaimerAddress", wireType)
+			}
+			var byteLen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowMsg
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				byteLen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if byteLen < 0 {
+				return ErrInvalidLengthMsg
+			}
+			postIndex := iNdEx + byteLen
+			if postIndex < 0 {
+				return ErrInvalidLengthMsg
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.ClaimerAddress = append(m.ClaimerAddress[:0], dAtA[iNdEx:postIndex]...)
+			if m.ClaimerAddress == nil {
+				m.ClaimerAddress = []byte{}
+			}
+			iNdEx = postIndex
+		case 3:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Commitment", wireType)
+			}
+			var byteLen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowMsg
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				byteLen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if byteLen < 0 {
+				return ErrInvalidLengthMsg
+			}
+			postIndex := iNdEx + byteLen
+			if postIndex < 0 {
+				return ErrInvalidLengthMsg
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Commitment = append(m.Commitment[:0], dAtA[iNdEx:postIndex]...)
+			if m.Commitment == nil {
+				m.Commitment = []byte{}
+			}
+			iNdEx = postIndex
+		default:
+			iNdEx = preIndex
+			skippy, err := skipMsg(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if (skippy < 0) || (iNdEx+skippy) < 0 {
+				return ErrInvalidLengthMsg
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *MsgProtoDelegatedProof) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowMsg
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= uint64(b&0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: MsgProtoDelegatedProof: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: MsgProtoDelegatedProof: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Proof", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowMsg
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthMsg
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthMsg
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if err := m.Proof.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		case 2:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field ClaimerAddress", wireType)
+			}
+			var byteLen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowMsg
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				byteLen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if byteLen < 0 {
+				return ErrInvalidLengthMsg
+			}
+			postIndex := iNdEx + byteLen
+			if postIndex < 0 {
+				return ErrInvalidLengthMsg
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.ClaimerAddress = append(m.ClaimerAddress[:0], dAtA[iNdEx:postIndex]...)
+			if m.ClaimerAddress == nil {
+				m.ClaimerAddress = []byte{}
+			}
+			iNdEx = postIndex
+		default:
+			iNdEx = preIndex
+			skippy, err := skipMsg(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if (skippy < 0) || (iNdEx+skippy) < 0 {
+				return ErrInvalidLengthMsg
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func skipMsg(dAtA []byte) (n int, err error) {
+	l := len(dAtA)
+	iNdEx := 0
+	depth := 0
+	for iNdEx < l {
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return 0, ErrIntOverflowMsg
+			}
+			if iNdEx >= l {
+				return 0, io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= (uint64(b) & 0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		wireType := int(wire & 0x7)
+		switch wireType {
+		case 0:
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return 0, ErrIntOverflowMsg
+				}
+				if iNdEx >= l {
+					return 0, io.ErrUnexpectedEOF
+				}
+				iNdEx++
+				if dAtA[iNdEx-1] < 0x80 {
+					break
+				}
+			}
+		case 1:
+			iNdEx += 8
+		case 2:
+			var length int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return 0, ErrIntOverflowMsg
+				}
+				if iNdEx >= l {
+					return 0, io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				length |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if length < 0 {
+				return 0, ErrInvalidLengthMsg
+			}
+			iNdEx += length
+		case 3:
+			depth++
+		case 4:
+			if depth == 0 {
+				return 0, ErrUnexpectedEndOfGroupMsg
+			}
+			depth--
+		case 5:
+			iNdEx += 4
+		default:
+			return 0, fmt.Errorf("proto: illegal wireType %d", wireType)
+		}
+		if iNdEx < 0 {
+			return 0, ErrInvalidLengthMsg
+		}
+		if depth == 0 {
+			return iNdEx, nil
+		}
+	}
+	return 0, io.ErrUnexpectedEOF
+}
+
+var (
+	ErrInvalidLengthMsg        = fmt.Errorf("proto: negative length found during unmarshaling")
+	ErrIntOverflowMsg          = fmt.Errorf("proto: integer overflow")
+	ErrUnexpectedEndOfGroupMsg = fmt.Errorf("proto: unexpected end of group")
+)
@@ -13,8 +13,18 @@ const (
 	RouterKey    = ModuleName // router name is module name
 	MsgClaimName = "claim"    // name for the claim message
 	MsgProofName = "proof"    // name for the proof message
+	// name for the proof batch message
+	MsgProofBatchName = "proof_batch"
+	// names for the delegated claiming messages
+	MsgDelegateClaimerName = "delegate_claimer"
+	MsgDelegatedClaimName  = "delegated_claim"
+	MsgDelegatedProofName  = "delegated_proof"
 )
 
+// MaxProofBatchSize is the max number of proofs of a proof batch message, so one tx cannot carry an unbounded number of
+// proof verifications
+const MaxProofBatchSize = 32
+
 // "GetFee" - Returns the fee (sdk.BigInt) of the messgae type
 func (msg MsgClaim) GetFee() sdk.BigInt {
 	return sdk.NewInt(PocketFeeMap[msg.Type()])
@@ -129,11 +139,7 @@ func (msg *MsgProof) Unmarshal(data []byte) error {
 	if err != nil {
 		return err
 	}
-	*msg = MsgProof{
-		MerkleProof:  m.MerkleProof,
-		Leaf:         m.Leaf.FromProto(),
-		EvidenceType: m.EvidenceType,
-	}
+	*msg = m.FromProto()
 	return nil
 }
 
@@ -158,6 +164,14 @@ func (msg MsgProof) ToProto() MsgProtoProof {
 	}
 }
 
+func (m MsgProtoProof) FromProto() MsgProof {
+	return MsgProof{
+		MerkleProof:  m.MerkleProof,
+		Leaf:         m.Leaf.FromProto(),
+		EvidenceType: m.EvidenceType,
+	}
+}
+
 // "GetFee" - Returns the fee (sdk.BigInt) of the messgae type
 func (msg MsgProof) GetFee() sdk.BigInt {
 	return sdk.NewInt(PocketFeeMap[msg.Type()])
@@ -171,6 +185,10 @@ func (msg MsgProof) Type() string { return MsgProofName }
 
 // "ValidateBasic" - Storeless validity check for proof message
 func (msg MsgProof) ValidateBasic() sdk.Error {
+	// the leaf is dereferenced below and by GetSigners
+	if msg.Leaf == nil {
+		return NewMalformedProofError(ModuleName, "the leaf is empty")
+	}
 	// verify valid number of levels for merkle proofs
 	if len(msg.MerkleProof.HashRanges) < 3 {
 		return NewInvalidLeafCousinProofsComboError(ModuleName)
@@ -207,3 +225,324 @@ func (msg MsgProof) GetRecipient() sdk.Address {
 func (msg MsgProof) GetLeaf() Proof {
 	return msg.Leaf
 }
+
+// ---------------------------------------------------------------------------------------------------------------------
+// "MsgProofBatch" - Proves multiple previous claims of the same servicer in a single transaction
+type MsgProofBatch struct {
+	Proofs       []MsgProof `json:"proofs"`         // the proofs, each validated against its own claim
+	AllOrNothing bool       `json:"all_or_nothing"` // if true a single invalid proof rejects the whole batch, else only that proof
+}
+
+var _ codec.ProtoMarshaler = &MsgProofBatch{}
+
+func (msg *MsgProofBatch) Marshal() ([]byte, error) {
+	m := msg.ToProto()
+	return m.Marshal()
+}
+
+func (msg *MsgProofBatch) MarshalTo(data []byte) (n int, err error) {
+	m := msg.ToProto()
+	return m.MarshalTo(data)
+}
+
+func (msg *MsgProofBatch) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	m := msg.ToProto()
+	return m.MarshalToSizedBuffer(dAtA)
+}
+
+func (msg *MsgProofBatch) Size() int {
+	m := msg.ToProto()
+	return m.Size()
+}
+
+func (msg *MsgProofBatch) Unmarshal(data []byte) error {
+	var m MsgProtoProofBatch
+	err := m.Unmarshal(data)
+	if err != nil {
+		return err
+	}
+	*msg = MsgProofBatch{AllOrNothing: m.AllOrNothing}
+	for _, p := range m.Proofs {
+		msg.Proofs = append(msg.Proofs, p.FromProto())
+	}
+	return nil
+}
+
+func (msg *MsgProofBatch) Reset() {
+	*msg = MsgProofBatch{}
+}
+
+func (msg *MsgProofBatch) ProtoMessage() {}
+
+// "XXX_MessageName" - Names the message for the interface registry (the type url must not collide with MsgProof's)
+func (*MsgProofBatch) XXX_MessageName() string {
+	return "x.pocketcore.MsgProofBatch"
+}
+
+func (msg MsgProofBatch) String() string {
+	return fmt.Sprintf("Proofs: %v\nAllOrNothing: %t\n", msg.Proofs, msg.AllOrNothing)
+}
+
+func (msg MsgProofBatch) ToProto() MsgProtoProofBatch {
+	m := MsgProtoProofBatch{AllOrNothing: msg.AllOrNothing}
+	for _, p := range msg.Proofs {
+		m.Proofs = append(m.Proofs, p.ToProto())
+	}
+	return m
+}
+
+// "GetFee" - Returns the fee (sdk.BigInt) of the messgae type, charged per proof of the batch
+func (msg MsgProofBatch) GetFee() sdk.BigInt {
+	return sdk.NewInt(PocketFeeMap[msg.Type()] * int64(len(msg.Proofs)))
+}
+
+// "Route" - Returns module router key
+func (msg MsgProofBatch) Route() string { return RouterKey }
+
+// "Type" - Returns message name
+func (msg MsgProofBatch) Type() string { return MsgProofBatchName }
+
+// "ValidateBasic" - Storeless validity check for proof batch message
+func (msg MsgProofBatch) ValidateBasic() sdk.Error {
+	if len(msg.Proofs) == 0 {
+		return NewEmptyProofBatchError(ModuleName)
+	}
+	if len(msg.Proofs) > MaxProofBatchSize {
+		return NewProofBatchTooLargeError(ModuleName, len(msg.Proofs))
+	}
+	signer := msg.Proofs[0].GetSigners()[0]
+	for _, proof := range msg.Proofs {
+		// validate each proof like a standalone proof message
+		if err := proof.ValidateBasic(); err != nil {
+			return err
+		}
+		// the batch is signed once, so every proof must belong to the same servicer
+		if !proof.GetSigners()[0].Equals(signer) {
+			return NewMismatchedBatchSignersError(ModuleName)
+		}
+	}
+	return nil
+}
+
+// "GetSignBytes" - Encodes the message for signing
+func (msg MsgProofBatch) GetSignBytes() []byte {
+	return sdk.MustSortJSON(ModuleCdc.MustMarshalJSON(msg))
+}
+
+// "GetSigners" - Defines whose signature is required
+func (msg MsgProofBatch) GetSigners() []sdk.Address {
+	if len(msg.Proofs) == 0 {
+		return nil
+	}
+	return msg.Proofs[0].GetSigners()
+}
+
+// "GetRecipient" - Returns the recipient of the message
+func (msg MsgProofBatch) GetRecipient() sdk.Address {
+	return nil
+}
+
+// ---------------------------------------------------------------------------------------------------------------------
+// "MsgDelegateClaimer" - Authorizes (or with an empty claimer revokes) a claimer to send the claims and proofs of a servicer
+// (generated from msg.proto)
+
+// "GetFee" - Returns the fee (sdk.BigInt) of the messgae type
+func (msg MsgDelegateClaimer) GetFee() sdk.BigInt {
+	return sdk.NewInt(PocketFeeMap[msg.Type()])
+}
+
+// "Route" - Returns module router key
+func (msg MsgDelegateClaimer) Route() string { return RouterKey }
+
+// "Type" - Returns message name
+func (msg MsgDelegateClaimer) Type() string { return MsgDelegateClaimerName }
+
+// "ValidateBasic" - Storeless validity check for delegate claimer message
+func (msg MsgDelegateClaimer) ValidateBasic() sdk.Error {
+	if err := AddressVerification(msg.ServicerAddress.String()); err != nil {
+		return NewInvalidHashError(ModuleName, err, msg.ServicerAddress.String())
+	}
+	// an empty claimer revokes the delegation
+	if msg.ClaimerAddress.Empty() {
+		return nil
+	}
+	if err := AddressVerification(msg.ClaimerAddress.String()); err != nil {
+		return NewInvalidHashError(ModuleName, err, msg.ClaimerAddress.String())
+	}
+	if msg.ClaimerAddress.Equals(msg.ServicerAddress) {
+		return NewSelfDelegatedClaimerError(ModuleName)
+	}
+	return nil
+}
+
+// "GetSignBytes" - Encodes the message for signing
+func (msg MsgDelegateClaimer) GetSignBytes() []byte {
+	return sdk.MustSortJSON(ModuleCdc.MustMarshalJSON(msg))
+}
+
+// "GetSigners" - Defines whose signature is required
+func (msg MsgDelegateClaimer) GetSigners() []sdk.Address {
+	return []sdk.Address{msg.ServicerAddress}
+}
+
+// "GetRecipient" - Returns the recipient of the message
+func (msg MsgDelegateClaimer) GetRecipient() sdk.Address {
+	return nil
+}
+
+// ---------------------------------------------------------------------------------------------------------------------
+// "MsgDelegatedClaim" - A claim of a servicer sent (and signed) by the claimer it delegated to (generated from msg.proto)
+
+// "GetFee" - Returns the fee (sdk.BigInt) of the messgae type
+func (msg MsgDelegatedClaim) GetFee() sdk.BigInt {
+	return sdk.NewInt(PocketFeeMap[msg.Type()])
+}
+
+// "Route" - Returns module router key
+func (msg MsgDelegatedClaim) Route() string { return RouterKey }
+
+// "Type" - Returns message name
+func (msg MsgDelegatedClaim) Type() string { return MsgDelegatedClaimName }
+
+// "ValidateBasic" - Storeless validity check for delegated claim message
+func (msg MsgDelegatedClaim) ValidateBasic() sdk.Error {
+	if err := msg.Claim.ValidateBasic(); err != nil {
+		return err
+	}
+	if err := AddressVerification(msg.ClaimerAddress.String()); err != nil {
+		return NewInvalidHashError(ModuleName, err, msg.ClaimerAddress.String())
+	}
+	return nil
+}
+
+// "GetSignBytes" - Encodes the message for signing
+func (msg MsgDelegatedClaim) GetSignBytes() []byte {
+	return sdk.MustSortJSON(ModuleCdc.MustMarshalJSON(msg))
+}
+
+// "GetSigners" - Defines whose signature is required (the claimer's, the handler checks it is the servicer's delegate)
+func (msg MsgDelegatedClaim) GetSigners() []sdk.Address {
+	return []sdk.Address{msg.ClaimerAddress}
+}
+
+// "GetRecipient" - Returns the recipient of the message
+func (msg MsgDelegatedClaim) GetRecipient() sdk.Address {
+	return nil
+}
+
+// "claimCommitment" - The fields of a claim the servicer's commitment signs
+type claimCommitment struct {
+	MerkleRoot    HashRange     `json:"merkle_root"`
+	TotalProofs   int64         `json:"total_proofs"`
+	SessionHeader SessionHeader `json:"header"`
+}
+
+// "CommitmentBytes" - Returns the bytes the servicer signs to commit to the claim's merkle root, relay count and session
+func (msg MsgClaim) CommitmentBytes() []byte {
+	return sdk.MustSortJSON(ModuleCdc.MustMarshalJSON(claimCommitment{
+		MerkleRoot:    msg.MerkleRoot,
+		TotalProofs:   msg.TotalProofs,
+		SessionHeader: msg.SessionHeader,
+	}))
+}
+
+// ---------------------------------------------------------------------------------------------------------------------
+// "MsgDelegatedProof" - A proof of a servicer sent (and signed) by the claimer it delegated to
+type MsgDelegatedProof struct {
+	Proof          MsgProof    `json:"proof"`           // the proof, whose leaf is signed for the servicer
+	ClaimerAddress sdk.Address `json:"claimer_address"` // the claimer, who signs the message
+}
+
+var _ codec.ProtoMarshaler = &MsgDelegatedProof{}
+
+func (msg *MsgDelegatedProof) Marshal() ([]byte, error) {
+	m := msg.ToProto()
+	return m.Marshal()
+}
+
+func (msg *MsgDelegatedProof) MarshalTo(data []byte) (n int, err error) {
+	m := msg.ToProto()
+	return m.MarshalTo(data)
+}
+
+func (msg *MsgDelegatedProof) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	m := msg.ToProto()
+	return m.MarshalToSizedBuffer(dAtA)
+}
+
+func (msg *MsgDelegatedProof) Size() int {
+	m := msg.ToProto()
+	return m.Size()
+}
+
+func (msg *MsgDelegatedProof) Unmarshal(data []byte) error {
+	var m MsgProtoDelegatedProof
+	err := m.Unmarshal(data)
+	if err != nil {
+		return err
+	}
+	*msg = MsgDelegatedProof{
+		Proof:          m.Proof.FromProto(),
+		ClaimerAddress: m.ClaimerAddress,
+	}
+	return nil
+}
+
+func (msg *MsgDelegatedProof) Reset() {
+	*msg = MsgDelegatedProof{}
+}
+
+func (msg *MsgDelegatedProof) ProtoMessage() {}
+
+// "XXX_MessageName" - Names the message for the interface registry (the type url must not collide with MsgProof's)
+func (*MsgDelegatedProof) XXX_MessageName() string {
+	return "x.pocketcore.MsgDelegatedProof"
+}
+
+func (msg MsgDelegatedProof) String() string {
+	return fmt.Sprintf("Proof: %v\nClaimerAddress: %s\n", msg.Proof, msg.ClaimerAddress)
+}
+
+func (msg MsgDelegatedProof) ToProto() MsgProtoDelegatedProof {
+	return MsgProtoDelegatedProof{
+		Proof:          msg.Proof.ToProto(),
+		ClaimerAddress: msg.ClaimerAddress,
+	}
+}
+
+// "GetFee" - Returns the fee (sdk.BigInt) of the messgae type
+func (msg MsgDelegatedProof) GetFee() sdk.BigInt {
+	return sdk.NewInt(PocketFeeMap[msg.Type()])
+}
+
+// "Route" - Returns module router key
+func (msg MsgDelegatedProof) Route() string { return RouterKey }
+
+// "Type" - Returns message name
+func (msg MsgDelegatedProof) Type() string { return MsgDelegatedProofName }
+
+// "ValidateBasic" - Storeless validity check for delegated proof message
+func (msg MsgDelegatedProof) ValidateBasic() sdk.Error {
+	if err := msg.Proof.ValidateBasic(); err != nil {
+		return err
+	}
+	if err := AddressVerification(msg.ClaimerAddress.String()); err != nil {
+		return NewInvalidHashError(ModuleName, err, msg.ClaimerAddress.String())
+	}
+	return nil
+}
+
+// "GetSignBytes" - Encodes the message for signing
+func (msg MsgDelegatedProof) GetSignBytes() []byte {
+	return sdk.MustSortJSON(ModuleCdc.MustMarshalJSON(msg))
+}
+
+// "GetSigners" - Defines whose signature is required (the claimer's, the handler checks it is the servicer's delegate)
+func (msg MsgDelegatedProof) GetSigners() []sdk.Address {
+	return []sdk.Address{msg.ClaimerAddress}
+}
+
+// "GetRecipient" - Returns the recipient of the message
+func (msg MsgDelegatedProof) GetRecipient() sdk.Address {
+	return nil
+}
@@ -298,6 +298,11 @@ func TestMsgProof_ValidateBasic(t *testing.T) {
 			msg:      invalidProofMsgBlkchn,
 			hasError: true,
 		},
+		{
+			name:     "Invalid Proof Message, empty leaf",
+			msg:      MsgProof{MerkleProof: validProofMessage.MerkleProof, EvidenceType: RelayEvidence},
+			hasError: true,
+		},
 		{
 			name:     "Valid Proof Message",
 			msg:      validProofMessage,
@@ -317,3 +322,210 @@ func TestMsgProof_GetSignBytes(t *testing.T) {
 		MsgProof{}.GetSignBytes()
 	})
 }
+
+func newTestMsgProof(t *testing.T, servicerPubKey string, entropy int64) MsgProof {
+	clientPrivKey := GetRandomPrivateKey()
+	appPrivKey := GetRandomPrivateKey()
+	leaf := RelayProof{
+		Entropy:            entropy,
+		SessionBlockHeight: 1,
+		ServicerPubKey:     servicerPubKey,
+		Blockchain:         hex.EncodeToString([]byte{01}),
+		RequestHash:        servicerPubKey, // fake
+		Token: AAT{
+			Version:              "0.0.1",
+			ApplicationPublicKey: appPrivKey.PublicKey().RawString(),
+			ClientPublicKey:      clientPrivKey.PublicKey().RawString(),
+		},
+	}
+	appSig, err := appPrivKey.Sign(leaf.Token.Hash())
+	if err != nil {
+		t.Fatalf(err.Error())
+	}
+	leaf.Token.ApplicationSignature = hex.EncodeToString(appSig)
+	clientSig, err := clientPrivKey.Sign(leaf.Hash())
+	if err != nil {
+		t.Fatalf(err.Error())
+	}
+	leaf.Signature = hex.EncodeToString(clientSig)
+	return MsgProof{
+		MerkleProof: MerkleProof{
+			TargetIndex: 0,
+			HashRanges: []HashRange{
+				{Hash: merkleHash([]byte("fake1")), Range: Range{0, 1}},
+				{Hash: merkleHash([]byte("fake2")), Range: Range{1, 2}},
+				{Hash: merkleHash([]byte("fake3")), Range: Range{2, 3}},
+			},
+			Target: HashRange{Hash: merkleHash([]byte("fake4")), Range: Range{3, 4}},
+		},
+		Leaf:         leaf,
+		EvidenceType: RelayEvidence,
+	}
+}
+
+func TestMsgProofBatch_Route(t *testing.T) {
+	assert.Equal(t, MsgProofBatch{}.Route(), RouterKey)
+}
+
+func TestMsgProofBatch_Type(t *testing.T) {
+	assert.Equal(t, MsgProofBatch{}.Type(), MsgProofBatchName)
+}
+
+func TestMsgProofBatch_GetSigners(t *testing.T) {
+	pk := getRandomPubKey()
+	batch := MsgProofBatch{Proofs: []MsgProof{newTestMsgProof(t, pk.RawString(), 1), newTestMsgProof(t, pk.RawString(), 2)}}
+	assert.Equal(t, []types.Address{types.Address(pk.Address())}, batch.GetSigners())
+	assert.Nil(t, MsgProofBatch{}.GetSigners())
+}
+
+func TestMsgProofBatch_GetFee(t *testing.T) {
+	servicer := getRandomPubKey().RawString()
+	batch := MsgProofBatch{Proofs: []MsgProof{newTestMsgProof(t, servicer, 1), newTestMsgProof(t, servicer, 2), newTestMsgProof(t, servicer, 3)}}
+	// each proof of the batch costs as much as a proof sent alone
+	assert.Equal(t, MsgProof{}.GetFee().MulRaw(3), batch.GetFee())
+}
+
+func TestMsgProofBatch_ValidateBasic(t *testing.T) {
+	servicer := getRandomPubKey().RawString()
+	valid := MsgProofBatch{Proofs: []MsgProof{newTestMsgProof(t, servicer, 1), newTestMsgProof(t, servicer, 2)}}
+	invalidProof := newTestMsgProof(t, servicer, 3)
+	invalidProof.MerkleProof.HashRanges = []HashRange{}
+	var full, tooLarge MsgProofBatch
+	for i := 0; i <= MaxProofBatchSize; i++ {
+		tooLarge.Proofs = append(tooLarge.Proofs, newTestMsgProof(t, servicer, int64(i+1)))
+	}
+	full.Proofs = tooLarge.Proofs[:MaxProofBatchSize]
+	tests := []struct {
+		name string
+		msg  MsgProofBatch
+		err  types.Error
+	}{
+		{
+			name: "Invalid Proof Batch, empty",
+			msg:  MsgProofBatch{},
+			err:  NewEmptyProofBatchError(ModuleName),
+		},
+		{
+			name: "Invalid Proof Batch, too large",
+			msg:  tooLarge,
+			err:  NewProofBatchTooLargeError(ModuleName, MaxProofBatchSize+1),
+		},
+		{
+			name: "Valid Proof Batch, max size",
+			msg:  full,
+			err:  nil,
+		},
+		{
+			name: "Invalid Proof Batch, mismatched signers",
+			msg:  MsgProofBatch{Proofs: []MsgProof{newTestMsgProof(t, servicer, 1), newTestMsgProof(t, getRandomPubKey().RawString(), 2)}},
+			err:  NewMismatchedBatchSignersError(ModuleName),
+		},
+		{
+			name: "Invalid Proof Batch, invalid proof",
+			msg:  MsgProofBatch{Proofs: []MsgProof{newTestMsgProof(t, servicer, 1), invalidProof}},
+			err:  NewInvalidLeafCousinProofsComboError(ModuleName),
+		},
+		{
+			name: "Valid Proof Batch",
+			msg:  valid,
+			err:  nil,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.err, tt.msg.ValidateBasic())
+		})
+	}
+}
+
+func TestMsgProofBatch_Marshal(t *testing.T) {
+	servicer := getRandomPubKey().RawString()
+	for _, batch := range []MsgProofBatch{
+		{Proofs: []MsgProof{newTestMsgProof(t, servicer, 1)}},
+		{Proofs: []MsgProof{newTestMsgProof(t, servicer, 1), newTestMsgProof(t, servicer, 2)}, AllOrNothing: true},
+	} {
+		bz, err := batch.Marshal()
+		assert.Nil(t, err)
+		assert.Len(t, bz, batch.Size())
+		var decoded MsgProofBatch
+		assert.Nil(t, decoded.Unmarshal(bz))
+		// the leaves decode as pointers (see MsgProof.Unmarshal), so compare them by hash and the batch by its encoding
+		assert.Equal(t, batch.AllOrNothing, decoded.AllOrNothing)
+		assert.Len(t, decoded.Proofs, len(batch.Proofs))
+		for i := range decoded.Proofs {
+			assert.Equal(t, batch.Proofs[i].MerkleProof, decoded.Proofs[i].MerkleProof)
+			assert.Equal(t, batch.Proofs[i].Leaf.Hash(), decoded.Proofs[i].Leaf.Hash())
+		}
+		rebz, err := decoded.Marshal()
+		assert.Nil(t, err)
+		assert.Equal(t, bz, rebz)
+	}
+	// truncated bytes error instead of panicking
+	batch := MsgProofBatch{Proofs: []MsgProof{newTestMsgProof(t, servicer, 1)}}
+	bz, err := batch.Marshal()
+	assert.Nil(t, err)
+	var decoded MsgProofBatch
+	assert.NotNil(t, decoded.Unmarshal(bz[:len(bz)-1]))
+}
+
+func TestMsgDelegateClaimer_ValidateBasic(t *testing.T) {
+	servicer := getRandomValidatorAddress()
+	assert.Nil(t, MsgDelegateClaimer{ServicerAddress: servicer, ClaimerAddress: getRandomValidatorAddress()}.ValidateBasic())
+	// an empty claimer revokes the delegation
+	assert.Nil(t, MsgDelegateClaimer{ServicerAddress: servicer}.ValidateBasic())
+	assert.NotNil(t, MsgDelegateClaimer{ClaimerAddress: servicer}.ValidateBasic())
+	err := MsgDelegateClaimer{ServicerAddress: servicer, ClaimerAddress: servicer}.ValidateBasic()
+	assert.Equal(t, types.CodeType(CodeSelfDelegatedClaimerError), err.Code())
+}
+
+func TestMsgDelegated_GetSigners(t *testing.T) {
+	servicer, claimer := getRandomValidatorAddress(), getRandomValidatorAddress()
+	assert.Equal(t, []types.Address{servicer}, MsgDelegateClaimer{ServicerAddress: servicer, ClaimerAddress: claimer}.GetSigners())
+	assert.Equal(t, []types.Address{claimer}, MsgDelegatedClaim{Claim: MsgClaim{FromAddress: servicer}, ClaimerAddress: claimer}.GetSigners())
+	proof := newTestMsgProof(t, getRandomPubKey().RawString(), 1)
+	assert.Equal(t, []types.Address{claimer}, MsgDelegatedProof{Proof: proof, ClaimerAddress: claimer}.GetSigners())
+}
+
+func TestMsgDelegated_Marshal(t *testing.T) {
+	servicer, claimer := getRandomValidatorAddress(), getRandomValidatorAddress()
+	for _, msg := range []MsgDelegateClaimer{{ServicerAddress: servicer, ClaimerAddress: claimer}, {ServicerAddress: servicer}} {
+		bz, err := msg.Marshal()
+		assert.Nil(t, err)
+		assert.Len(t, bz, msg.Size())
+		var decoded MsgDelegateClaimer
+		assert.Nil(t, decoded.Unmarshal(bz))
+		assert.True(t, msg.ServicerAddress.Equals(decoded.ServicerAddress))
+		assert.True(t, msg.ClaimerAddress.Equals(decoded.ClaimerAddress))
+	}
+	claim := MsgDelegatedClaim{
+		Claim: MsgClaim{
+			SessionHeader: SessionHeader{ApplicationPubKey: getRandomPubKey().RawString(), Chain: "0001", SessionBlockHeight: 1},
+			MerkleRoot:    HashRange{Hash: Hash([]byte("fake")), Range: Range{Upper: 10}},
+			TotalProofs:   10,
+			FromAddress:   servicer,
+			EvidenceType:  RelayEvidence,
+		},
+		ClaimerAddress: claimer,
+		Commitment:     []byte("commitment"),
+	}
+	bz, err := claim.Marshal()
+	assert.Nil(t, err)
+	assert.Len(t, bz, claim.Size())
+	var decodedClaim MsgDelegatedClaim
+	assert.Nil(t, decodedClaim.Unmarshal(bz))
+	assert.Equal(t, claim.Claim.SessionHeader, decodedClaim.Claim.SessionHeader)
+	assert.True(t, claim.Claim.FromAddress.Equals(decodedClaim.Claim.FromAddress))
+	assert.True(t, claim.ClaimerAddress.Equals(decodedClaim.ClaimerAddress))
+	assert.Equal(t, claim.Commitment, decodedClaim.Commitment)
+	proof := MsgDelegatedProof{Proof: newTestMsgProof(t, getRandomPubKey().RawString(), 1), ClaimerAddress: claimer}
+	bz, err = proof.Marshal()
+	assert.Nil(t, err)
+	assert.Len(t, bz, proof.Size())
+	var decodedProof MsgDelegatedProof
+	assert.Nil(t, decodedProof.Unmarshal(bz))
+	// the leaf decodes as a pointer (see MsgProof.Unmarshal), so compare it by hash
+	assert.Equal(t, proof.Proof.Leaf.Hash(), decodedProof.Proof.Leaf.Hash())
+	assert.True(t, proof.ClaimerAddress.Equals(decodedProof.ClaimerAddress))
+	// truncated bytes error instead of panicking
+	assert.NotNil(t, decodedProof.Unmarshal(bz[:len(bz)-1]))
+}
@@ -0,0 +1,54 @@
+package types
+
+import (
+	"encoding/hex"
+	"fmt"
+	"os"
+	"sync"
+)
+
+// OfflineQueue receives the signed auto claim and proof txs of a node that does not broadcast them itself (e.g. an
+// air-gapped node), for a separate relayer to submit
+type OfflineQueue interface {
+	Enqueue(txBytes []byte) error
+}
+
+// FileOfflineQueue appends each signed tx to the file at Path, hex encoded on its own line
+type FileOfflineQueue struct {
+	l    sync.Mutex
+	Path string
+}
+
+// "Enqueue" - Appends the signed tx to the file, creating it if needed
+func (q *FileOfflineQueue) Enqueue(txBytes []byte) error {
+	q.l.Lock()
+	defer q.l.Unlock()
+	f, err := os.OpenFile(q.Path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0600)
+	if err != nil {
+		return fmt.Errorf("unable to open the offline tx queue: %s", err.Error())
+	}
+	if _, err = fmt.Fprintln(f, hex.EncodeToString(txBytes)); err != nil {
+		_ = f.Close()
+		return fmt.Errorf("unable to write to the offline tx queue: %s", err.Error())
+	}
+	return f.Close()
+}
+
+var (
+	offlineQueueLock   sync.RWMutex
+	globalOfflineQueue OfflineQueue
+)
+
+// "SetOfflineQueue" - Sets the queue the auto txs are written to instead of being broadcast (nil broadcasts them)
+func SetOfflineQueue(q OfflineQueue) {
+	offlineQueueLock.Lock()
+	defer offlineQueueLock.Unlock()
+	globalOfflineQueue = q
+}
+
+// "GetOfflineQueue" - Returns the queue the auto txs are written to, or nil if they are broadcast
+func GetOfflineQueue() OfflineQueue {
+	offlineQueueLock.RLock()
+	defer offlineQueueLock.RUnlock()
+	return globalOfflineQueue
+}
@@ -18,31 +18,50 @@ const (
 	DefaultReplayAttackBurnMultiplier = int64(3)       // default replay attack burn multiplier
 	DefaultMinimumNumberOfProofs      = int64(5)       // default minimum number of proofs
 	DefaultBlockByteSize              = int64(4000000) // default block size in bytes
+	RelayRewardMultiplierBasis        = int64(10000)   // the relay reward multiplier that rewards the relays as is
 
 )
 
 var (
-	DefaultSupportedBlockchains   = []string{"0001"}
-	KeySessionNodeCount           = []byte("SessionNodeCount")
-	KeyClaimSubmissionWindow      = []byte("ClaimSubmissionWindow")
-	KeySupportedBlockchains       = []byte("SupportedBlockchains")
-	KeyClaimExpiration            = []byte("ClaimExpiration")
-	KeyReplayAttackBurnMultiplier = []byte("ReplayAttackBurnMultiplier")
-	KeyMinimumNumberOfProofs      = []byte("MinimumNumberOfProofs")
-	KeyBlockByteSize              = []byte("BlockByteSize")
+	DefaultSupportedBlockchains    = []string{"0001"}
+	KeySessionNodeCount            = []byte("SessionNodeCount")
+	KeyClaimSubmissionWindow       = []byte("ClaimSubmissionWindow")
+	KeySupportedBlockchains        = []byte("SupportedBlockchains")
+	KeyClaimExpiration             = []byte("ClaimExpiration")
+	KeyReplayAttackBurnMultiplier  = []byte("ReplayAttackBurnMultiplier")
+	KeyMinimumNumberOfProofs       = []byte("MinimumNumberOfProofs")
+	KeyBlockByteSize               = []byte("BlockByteSize")
+	KeyMaxClaimsDeletedPerBlock    = []byte("MaxClaimsDeletedPerBlock")
+	KeyMaxProofsPerAddressPerBlock = []byte("MaxProofsPerAddressPerBlock")
+	KeyMaxRelaysPerSession         = []byte("MaxRelaysPerSession")
+	KeyRelayRewardMultipliers      = []byte("RelayRewardMultipliers")
+	KeyRejectSelfSignedProofs      = []byte("RejectSelfSignedProofs")
 )
 
 var _ types.ParamSet = (*Params)(nil)
 
+// "RelayRewardMultiplier" - The relays of the chain are rewarded times Multiplier / RelayRewardMultiplierBasis
+type RelayRewardMultiplier struct {
+	Chain      string `json:"chain"`
+	Multiplier int64  `json:"multiplier"` // in basis points
+}
+
 // "Params" - defines the governance set, high level settings for pocketcore module
 type Params struct {
-	SessionNodeCount           int64    `json:"session_node_count"`
-	ClaimSubmissionWindow      int64    `json:"proof_waiting_period"`
-	SupportedBlockchains       []string `json:"supported_blockchains"`
-	ClaimExpiration            int64    `json:"claim_expiration"` // per session
-	ReplayAttackBurnMultiplier int64    `json:"replay_attack_burn_multiplier"`
-	MinimumNumberOfProofs      int64    `json:"minimum_number_of_proofs"`
-	BlockByteSize              int64    `json:"block_byte_size,omitempty"`
+	SessionNodeCount            int64    `json:"session_node_count"`
+	ClaimSubmissionWindow       int64    `json:"proof_waiting_period"`
+	SupportedBlockchains        []string `json:"supported_blockchains"`
+	ClaimExpiration             int64    `json:"claim_expiration"` // per session
+	ReplayAttackBurnMultiplier  int64    `json:"replay_attack_burn_multiplier"`
+	MinimumNumberOfProofs       int64    `json:"minimum_number_of_proofs"`
+	BlockByteSize               int64    `json:"block_byte_size,omitempty"`
+	MaxClaimsDeletedPerBlock    int64    `json:"max_claims_deleted_per_block,omitempty"`     // 0 is unlimited
+	MaxProofsPerAddressPerBlock int64    `json:"max_proofs_per_address_per_block,omitempty"` // 0 is unlimited
+	MaxRelaysPerSession         int64    `json:"max_relays_per_session,omitempty"`           // 0 is unlimited
+	// the relay reward multipliers, sorted by chain; the relays of a chain that is not listed are rewarded as is
+	RelayRewardMultipliers []RelayRewardMultiplier `json:"relay_reward_multipliers,omitempty"`
+	// reject the relay proofs whose relay is signed by the servicer's own key (see RelayProof.IsSelfSigned)
+	RejectSelfSignedProofs bool `json:"reject_self_signed_proofs,omitempty"`
 }
 
 // "ParamSetPairs" - returns an kv params object
@@ -56,6 +75,11 @@ func (p *Params) ParamSetPairs() types.ParamSetPairs {
 		{Key: KeyReplayAttackBurnMultiplier, Value: p.ReplayAttackBurnMultiplier},
 		{Key: KeyMinimumNumberOfProofs, Value: p.MinimumNumberOfProofs},
 		{Key: KeyBlockByteSize, Value: p.BlockByteSize},
+		{Key: KeyMaxClaimsDeletedPerBlock, Value: p.MaxClaimsDeletedPerBlock},
+		{Key: KeyMaxProofsPerAddressPerBlock, Value: p.MaxProofsPerAddressPerBlock},
+		{Key: KeyMaxRelaysPerSession, Value: p.MaxRelaysPerSession},
+		{Key: KeyRelayRewardMultipliers, Value: p.RelayRewardMultipliers},
+		{Key: KeyRejectSelfSignedProofs, Value: p.RejectSelfSignedProofs},
 	}
 }
 
@@ -98,6 +122,35 @@ func (p Params) Validate() error {
 	if p.ClaimExpiration < p.ClaimSubmissionWindow {
 		return errors.New("unverified Proof expiration is far too short, must be greater than Proof waiting period")
 	}
+	// ensure max claims deleted per block is not negative
+	if p.MaxClaimsDeletedPerBlock < 0 {
+		return errors.New("invalid max claims deleted per block")
+	}
+	// ensure max proofs per address per block is not negative
+	if p.MaxProofsPerAddressPerBlock < 0 {
+		return errors.New("invalid max proofs per address per block")
+	}
+	// ensure max relays per session is not negative
+	if p.MaxRelaysPerSession < 0 {
+		return errors.New("invalid max relays per session")
+	}
+	// verify the chain and multiplier of each relay reward multiplier, and that the chains are unique and sorted
+	for i, m := range p.RelayRewardMultipliers {
+		if err := NetworkIdentifierVerification(m.Chain); err != nil {
+			return err
+		}
+		if m.Multiplier < 0 {
+			return fmt.Errorf("invalid relay reward multiplier for chain %s", m.Chain)
+		}
+		if i == 0 {
+			continue
+		}
+		if prev := p.RelayRewardMultipliers[i-1].Chain; prev == m.Chain {
+			return fmt.Errorf("duplicate relay reward multiplier for chain %s", m.Chain)
+		} else if prev > m.Chain {
+			return errors.New("relay reward multipliers must be sorted by chain")
+		}
+	}
 	return nil
 }
 
@@ -115,11 +168,21 @@ func (p Params) String() string {
   ClaimExpiration            %d
   ReplayAttackBurnMultiplier %d
   BlockByteSize %d
+  MaxClaimsDeletedPerBlock %d
+  MaxProofsPerAddressPerBlock %d
+  MaxRelaysPerSession %d
+  RelayRewardMultipliers %v
+  RejectSelfSignedProofs %t
 `,
 		p.SessionNodeCount,
 		p.ClaimSubmissionWindow,
 		p.SupportedBlockchains,
 		p.ClaimExpiration,
 		p.ReplayAttackBurnMultiplier,
-		p.BlockByteSize)
+		p.BlockByteSize,
+		p.MaxClaimsDeletedPerBlock,
+		p.MaxProofsPerAddressPerBlock,
+		p.MaxRelaysPerSession,
+		p.RelayRewardMultipliers,
+		p.RejectSelfSignedProofs)
 }
@@ -90,3 +90,18 @@ func TestParams_String(t *testing.T) {
 	df := DefaultParams()
 	assert.NotPanics(t, func() { _ = df.String() })
 }
+
+func TestParams_ValidateRelayRewardMultipliers(t *testing.T) {
+	p := DefaultParams()
+	p.RelayRewardMultipliers = []RelayRewardMultiplier{{Chain: "0001", Multiplier: 15000}, {Chain: "0002", Multiplier: 0}}
+	assert.Nil(t, p.Validate())
+	p.RelayRewardMultipliers = []RelayRewardMultiplier{{Chain: "0001", Multiplier: -1}}
+	assert.NotNil(t, p.Validate())
+	p.RelayRewardMultipliers = []RelayRewardMultiplier{{Chain: "not a chain", Multiplier: RelayRewardMultiplierBasis}}
+	assert.NotNil(t, p.Validate())
+	// the chains must be unique and sorted
+	p.RelayRewardMultipliers = []RelayRewardMultiplier{{Chain: "0001", Multiplier: 15000}, {Chain: "0001", Multiplier: 5000}}
+	assert.NotNil(t, p.Validate())
+	p.RelayRewardMultipliers = []RelayRewardMultiplier{{Chain: "0002", Multiplier: 15000}, {Chain: "0001", Multiplier: 5000}}
+	assert.NotNil(t, p.Validate())
+}
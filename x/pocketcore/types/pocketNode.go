@@ -22,6 +22,175 @@ type PocketNode struct {
 	EvidenceStore   *CacheStorage
 	SessionStore    *CacheStorage
 	DoCacheInitOnce sync.Once
+	InFlightClaims  InFlightClaims
+	InFlightProofs  InFlightProofs
+	ClaimBreaker    ClaimBreaker
+	FeesSpent       FeesSpent
+	// the key of the claimer the servicer delegated to on-chain (see MsgDelegateClaimer); if set, it signs the auto claim and proof txs
+	ClaimerKey crypto.PrivateKey
+}
+
+// InFlightClaims tracks the claim-txs a node has broadcast that are not yet confirmed in the world state
+type InFlightClaims struct {
+	l sync.Mutex
+	m map[string]int64 // evidence key -> block height the claim-tx was sent at
+}
+
+// "Pending" - Returns true if the claim was sent less than timeout blocks ago and should not be re-sent yet
+func (ifc *InFlightClaims) Pending(header SessionHeader, evidenceType EvidenceType, height, timeout int64) bool {
+	ifc.l.Lock()
+	defer ifc.l.Unlock()
+	sentAt, found := ifc.m[inFlightKey(header, evidenceType)]
+	return found && height-sentAt < timeout
+}
+
+// "Set" - Marks the claim as sent at height
+func (ifc *InFlightClaims) Set(header SessionHeader, evidenceType EvidenceType, height int64) {
+	ifc.l.Lock()
+	defer ifc.l.Unlock()
+	if ifc.m == nil {
+		ifc.m = make(map[string]int64)
+	}
+	ifc.m[inFlightKey(header, evidenceType)] = height
+}
+
+// "Delete" - Stops tracking the claim (confirmed or abandoned)
+func (ifc *InFlightClaims) Delete(header SessionHeader, evidenceType EvidenceType) {
+	ifc.l.Lock()
+	defer ifc.l.Unlock()
+	delete(ifc.m, inFlightKey(header, evidenceType))
+}
+
+// InFlightProofs tracks the proof-txs a node has broadcast whose claims are still held in the world state. A proven
+// claim is deleted, so a claim still held after a later block means its proof-tx was rejected (or dropped)
+type InFlightProofs struct {
+	l sync.Mutex
+	m map[string]InFlightProof // evidence key -> the latest proof-tx sent for the claim
+}
+
+// InFlightProof is the latest proof-tx a node has broadcast for a claim
+type InFlightProof struct {
+	SessionHeader    SessionHeader
+	EvidenceType     EvidenceType
+	TxHash           string // empty if the broadcast returned no response
+	Height           int64  // block height the proof-tx was sent at
+	ExpirationHeight int64  // expiration height of the claim
+	Attempts         int    // proof-txs sent for the claim
+}
+
+// "Get" - Returns the latest proof-tx sent for the claim, if any
+func (ifp *InFlightProofs) Get(header SessionHeader, evidenceType EvidenceType) (proof InFlightProof, found bool) {
+	ifp.l.Lock()
+	defer ifp.l.Unlock()
+	proof, found = ifp.m[inFlightKey(header, evidenceType)]
+	return
+}
+
+// "Set" - Records a proof-tx sent for the claim at height and returns the number of proof-txs sent for it
+func (ifp *InFlightProofs) Set(claim MsgClaim, txHash string, height int64) (attempts int) {
+	ifp.l.Lock()
+	defer ifp.l.Unlock()
+	if ifp.m == nil {
+		ifp.m = make(map[string]InFlightProof)
+	}
+	key := inFlightKey(claim.SessionHeader, claim.EvidenceType)
+	attempts = ifp.m[key].Attempts + 1
+	ifp.m[key] = InFlightProof{
+		SessionHeader:    claim.SessionHeader,
+		EvidenceType:     claim.EvidenceType,
+		TxHash:           txHash,
+		Height:           height,
+		ExpirationHeight: claim.ExpirationHeight,
+		Attempts:         attempts,
+	}
+	return
+}
+
+// "Delete" - Stops tracking the proof-txs of the claim (proven or abandoned)
+func (ifp *InFlightProofs) Delete(header SessionHeader, evidenceType EvidenceType) {
+	ifp.l.Lock()
+	defer ifp.l.Unlock()
+	delete(ifp.m, inFlightKey(header, evidenceType))
+}
+
+// "All" - Returns the tracked proof-txs
+func (ifp *InFlightProofs) All() (proofs []InFlightProof) {
+	ifp.l.Lock()
+	defer ifp.l.Unlock()
+	for _, proof := range ifp.m {
+		proofs = append(proofs, proof)
+	}
+	return
+}
+
+// FeesSpent accumulates the fees of the auto claim and proof txs a node has sent since it started, so operators can weigh
+// them against its rewards
+type FeesSpent struct {
+	l     sync.Mutex
+	coins sdk.Coins
+}
+
+// "Add" - Adds the fees of a sent tx
+func (fs *FeesSpent) Add(fees sdk.Coins) {
+	fs.l.Lock()
+	defer fs.l.Unlock()
+	fs.coins = fs.coins.Add(fees)
+}
+
+// "Total" - Returns the fees of the txs sent so far
+func (fs *FeesSpent) Total() sdk.Coins {
+	fs.l.Lock()
+	defer fs.l.Unlock()
+	if fs.coins == nil {
+		return sdk.NewCoins()
+	}
+	return fs.coins
+}
+
+// ClaimBreaker pauses the auto claims of a node after consecutive failed claim-txs (e.g. without the balance for the
+// fees), so a failing node does not retry them every cycle
+type ClaimBreaker struct {
+	l         sync.Mutex
+	failures  int   // consecutive failed claim-txs
+	openUntil int64 // block height the auto claims are paused until
+}
+
+// "Paused" - Returns true if the auto claims are paused at height
+func (cb *ClaimBreaker) Paused(height int64) bool {
+	cb.l.Lock()
+	defer cb.l.Unlock()
+	return height < cb.openUntil
+}
+
+// "Failure" - Counts a failed claim-tx at height; at threshold consecutive failures (0 never pauses) the auto claims are
+// paused for cooldown blocks and tripped is returned. The count is kept until a success, so a failed probe after the
+// cooldown pauses them again
+func (cb *ClaimBreaker) Failure(height int64, threshold int, cooldown int64) (tripped bool) {
+	cb.l.Lock()
+	defer cb.l.Unlock()
+	cb.failures++
+	if threshold <= 0 || cb.failures < threshold {
+		return false
+	}
+	cb.openUntil = height + cooldown
+	return true
+}
+
+// "Success" - Resets the consecutive failures after a claim-tx was sent
+func (cb *ClaimBreaker) Success() {
+	cb.l.Lock()
+	defer cb.l.Unlock()
+	cb.failures = 0
+	cb.openUntil = 0
+}
+
+func inFlightKey(header SessionHeader, evidenceType EvidenceType) string {
+	return fmt.Sprintf("%s/%d", header.HashString(), evidenceType)
+}
+
+// "IsLocked" - Returns true if the node has no private key loaded, so it can't sign its claims and proofs
+func (n *PocketNode) IsLocked() bool {
+	return n == nil || n.PrivateKey == nil
 }
 
 func (n *PocketNode) GetAddress() sdk.Address {
@@ -77,3 +77,81 @@ func TestPocketNodeInitCaches(t *testing.T) {
 		assert.NotNil(t, node.SessionStore)
 	}
 }
+
+func TestInFlightClaims(t *testing.T) {
+	header := SessionHeader{
+		ApplicationPubKey:  getRandomPubKey().RawString(),
+		Chain:              "0001",
+		SessionBlockHeight: 1,
+	}
+	timeout := int64(4)
+	node := PocketNode{}
+	// nothing sent yet
+	assert.False(t, node.InFlightClaims.Pending(header, RelayEvidence, 5, timeout))
+	// the claim-tx is sent at height 5 but is dropped before it lands
+	node.InFlightClaims.Set(header, RelayEvidence, 5)
+	assert.True(t, node.InFlightClaims.Pending(header, RelayEvidence, 5, timeout))
+	assert.True(t, node.InFlightClaims.Pending(header, RelayEvidence, 8, timeout))
+	// other evidence for the same session is unaffected
+	assert.False(t, node.InFlightClaims.Pending(header, ChallengeEvidence, 6, timeout))
+	// after the timeout the claim-tx should be re-sent
+	assert.False(t, node.InFlightClaims.Pending(header, RelayEvidence, 9, timeout))
+	node.InFlightClaims.Set(header, RelayEvidence, 9)
+	assert.True(t, node.InFlightClaims.Pending(header, RelayEvidence, 10, timeout))
+	// once confirmed it is no longer tracked
+	node.InFlightClaims.Delete(header, RelayEvidence)
+	assert.False(t, node.InFlightClaims.Pending(header, RelayEvidence, 10, timeout))
+}
+
+func TestInFlightProofs(t *testing.T) {
+	claim := MsgClaim{
+		SessionHeader: SessionHeader{
+			ApplicationPubKey:  getRandomPubKey().RawString(),
+			Chain:              "0001",
+			SessionBlockHeight: 1,
+		},
+		EvidenceType:     RelayEvidence,
+		ExpirationHeight: 100,
+	}
+	node := PocketNode{}
+	_, found := node.InFlightProofs.Get(claim.SessionHeader, RelayEvidence)
+	assert.False(t, found)
+	assert.Empty(t, node.InFlightProofs.All())
+	// every proof-tx sent for the claim is counted
+	assert.Equal(t, 1, node.InFlightProofs.Set(claim, "a", 5))
+	assert.Equal(t, 2, node.InFlightProofs.Set(claim, "b", 9))
+	sent, found := node.InFlightProofs.Get(claim.SessionHeader, RelayEvidence)
+	assert.True(t, found)
+	assert.Equal(t, InFlightProof{SessionHeader: claim.SessionHeader, EvidenceType: RelayEvidence, TxHash: "b", Height: 9, ExpirationHeight: 100, Attempts: 2}, sent)
+	// other evidence for the same session is unaffected
+	_, found = node.InFlightProofs.Get(claim.SessionHeader, ChallengeEvidence)
+	assert.False(t, found)
+	assert.Len(t, node.InFlightProofs.All(), 1)
+	node.InFlightProofs.Delete(claim.SessionHeader, RelayEvidence)
+	_, found = node.InFlightProofs.Get(claim.SessionHeader, RelayEvidence)
+	assert.False(t, found)
+}
+
+func TestClaimBreaker(t *testing.T) {
+	node := PocketNode{}
+	assert.False(t, node.ClaimBreaker.Paused(1))
+	// a threshold of 0 never pauses
+	for i := 0; i < 5; i++ {
+		assert.False(t, node.ClaimBreaker.Failure(1, 0, 10))
+	}
+	assert.False(t, node.ClaimBreaker.Paused(1))
+	node.ClaimBreaker.Success()
+	// the threshold-th consecutive failure pauses the claims for the cooldown
+	assert.False(t, node.ClaimBreaker.Failure(5, 3, 10))
+	assert.False(t, node.ClaimBreaker.Failure(6, 3, 10))
+	assert.True(t, node.ClaimBreaker.Failure(7, 3, 10))
+	assert.True(t, node.ClaimBreaker.Paused(16))
+	assert.False(t, node.ClaimBreaker.Paused(17))
+	// a failed probe pauses them again
+	assert.True(t, node.ClaimBreaker.Failure(17, 3, 10))
+	assert.True(t, node.ClaimBreaker.Paused(26))
+	// a success resets the failures
+	node.ClaimBreaker.Success()
+	assert.False(t, node.ClaimBreaker.Paused(20))
+	assert.False(t, node.ClaimBreaker.Failure(30, 3, 10))
+}
@@ -7,6 +7,7 @@ import (
 	"github.com/pokt-network/pocket-core/crypto"
 	sdk "github.com/pokt-network/pocket-core/types"
 	"log"
+	"strings"
 )
 
 // "Proof" - An interface representation of an economic proof of work/burn (relay or challenge)
@@ -129,6 +130,25 @@ func (rp RelayProof) ValidateBasic() sdk.Error {
 	return nil
 }
 
+// "ValidateClientKey" - Validates that the client key signing the relay is bound to the application by the token: the
+// token must be issued by the application (the keys are hex, so they are compared in any case) and carry its signature
+// over the client key
+func (rp RelayProof) ValidateClientKey(appPubKey string) sdk.Error {
+	if !strings.EqualFold(rp.Token.ApplicationPublicKey, appPubKey) {
+		return NewInvalidTokenError(ModuleName, fmt.Errorf("the token was issued by %s, not the application of the session %s", rp.Token.ApplicationPublicKey, appPubKey))
+	}
+	if err := rp.Token.ValidateSignature(); err != nil {
+		return NewInvalidTokenError(ModuleName, err)
+	}
+	return nil
+}
+
+// "IsSelfSigned" - Returns true if the relay is signed by the servicer's own key, i.e. the servicer holds the client key
+// of the relays it claims to have served (self dealing); the keys are hex, so they are compared in any case
+func (rp RelayProof) IsSelfSigned() bool {
+	return strings.EqualFold(rp.Token.ClientPublicKey, rp.ServicerPubKey)
+}
+
 // "SessionHeader" - Returns the session header corresponding with the proof
 func (rp RelayProof) SessionHeader() SessionHeader {
 	return SessionHeader{
@@ -4,6 +4,7 @@ import (
 	"encoding/hex"
 	"encoding/json"
 	"fmt"
+	"strings"
 	"testing"
 
 	"github.com/pokt-network/pocket-core/crypto"
@@ -266,6 +267,19 @@ func TestRelayProof_ValidateBasic(t *testing.T) {
 	// invalid signature
 	invalidSig := validProof
 	invalidSig.Signature = "abc"
+	// the client signature covers the whole session header, so the signed proof can't be replayed into another session
+	otherSessionHeight := validProof
+	otherSessionHeight.SessionBlockHeight = 5
+	otherChain := validProof
+	otherChain.Blockchain = hex.EncodeToString([]byte{02})
+	otherAppPrivateKey := GetRandomPrivateKey()
+	otherApp := validProof
+	otherApp.Token.ApplicationPublicKey = otherAppPrivateKey.PublicKey().RawString()
+	otherAppSignature, er := otherAppPrivateKey.Sign(otherApp.Token.Hash())
+	if er != nil {
+		t.Fatalf(er.Error())
+	}
+	otherApp.Token.ApplicationSignature = hex.EncodeToString(otherAppSignature)
 	tests := []struct {
 		name     string
 		proof    Proof
@@ -311,6 +325,21 @@ func TestRelayProof_ValidateBasic(t *testing.T) {
 			proof:    invalidSig,
 			hasError: true,
 		},
+		{
+			name:     "invalid proof, replayed into another session height",
+			proof:    otherSessionHeight,
+			hasError: true,
+		},
+		{
+			name:     "invalid proof, replayed into another chain",
+			proof:    otherChain,
+			hasError: true,
+		},
+		{
+			name:     "invalid proof, replayed into another application's session",
+			proof:    otherApp,
+			hasError: true,
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -355,6 +384,41 @@ func TestRelayProof_SessionHeader(t *testing.T) {
 	assert.Equal(t, validProof.SessionHeader(), sh)
 }
 
+func TestRelayProof_ValidateClientKey(t *testing.T) {
+	appPrivateKey := GetRandomPrivateKey()
+	clientPrivateKey := GetRandomPrivateKey()
+	appPubKey := appPrivateKey.PublicKey().RawString()
+	proof := RelayProof{
+		SessionBlockHeight: 1,
+		ServicerPubKey:     getRandomPubKey().RawString(),
+		Blockchain:         "0001",
+		Token: AAT{
+			Version:              "0.0.1",
+			ApplicationPublicKey: appPubKey,
+			ClientPublicKey:      clientPrivateKey.PublicKey().RawString(),
+		},
+	}
+	appSignature, er := appPrivateKey.Sign(proof.Token.Hash())
+	assert.Nil(t, er)
+	proof.Token.ApplicationSignature = hex.EncodeToString(appSignature)
+	// the client key is bound to the session's application
+	assert.Nil(t, proof.ValidateClientKey(appPubKey))
+	// in any case
+	assert.Nil(t, proof.ValidateClientKey(strings.ToUpper(appPubKey)))
+	// but not to another application
+	err := proof.ValidateClientKey(getRandomPubKey().RawString())
+	assert.NotNil(t, err)
+	assert.Equal(t, sdk.CodeType(CodeInvalidTokenError), err.Code())
+	// a token naming the application, but signed by an arbitrary key (e.g. the client's own), binds nothing
+	forged := proof
+	forgedSignature, er := clientPrivateKey.Sign(forged.Token.Hash())
+	assert.Nil(t, er)
+	forged.Token.ApplicationSignature = hex.EncodeToString(forgedSignature)
+	err = forged.ValidateClientKey(appPubKey)
+	assert.NotNil(t, err)
+	assert.Equal(t, sdk.CodeType(CodeInvalidTokenError), err.Code())
+}
+
 func TestChallengeProofInvalidData_ValidateBasic(t *testing.T) {
 	validChallengeProofIVD, _, _, _, _, _, _ := NewValidChallengeProof(t)
 	// invalid empty reporter
@@ -13,6 +13,28 @@ const (
 	QueryDispatch             = "dispatch"
 	QueryChallenge            = "challenge"
 	QueryParameters           = "parameters"
+	QueryRelaysByChain        = "relaysByChain"
+	QueryEstimateClaimReward  = "estimateClaimReward"
+	QueryRequiredProof        = "requiredProof"
+	QueryProofBacklog         = "proofBacklog"
+	QueryExpiringClaims       = "expiringClaims"
+	QueryProofParameters      = "proofParameters"
+	QueryClaimsInHeightRange  = "claimsInHeightRange"
+	QueryServedChains         = "servedChains"
+	QueryProofByHash          = "proofByHash"
+	QueryClaimTimeline        = "claimTimeline"
+	QueryClaimRoot            = "claimRoot"
+	QueryValidateProof        = "validateProof"
+	QueryVerifiedRelays       = "verifiedRelays"
+	QueryClaimsByChain        = "claimsByChain"
+	QueryProofModuleStats     = "proofModuleStats"
+	QueryMerklePath           = "merklePath"
+	QueryClaimsBySession      = "claimsBySession"
+	QueryClaimExpiration      = "claimExpiration"
+	QueryClaimFeesSpent       = "claimFeesSpent"
+	QueryImmatureClaims       = "immatureClaims"
+	QueryCachedRelaysByChain  = "cachedRelaysByChain"
+	QueryIsClaimant           = "isClaimant"
 )
 
 // "QueryRelayParams" - The parameters needed to submit a relay request
@@ -41,3 +63,221 @@ type QueryReceiptParams struct {
 type QueryReceiptsParams struct {
 	Address sdk.Address `json:"address"`
 }
+
+// "QueryEstimateClaimRewardParams" - The parameters needed to estimate the reward for a claim
+type QueryEstimateClaimRewardParams struct {
+	Address     sdk.Address `json:"address"`
+	Chain       string      `json:"chain,omitempty"` // weights the relays by the chain's relay reward multiplier
+	TotalRelays int64       `json:"total_relays"`
+}
+
+// "QueryRequiredProofParams" - The parameters needed to retrieve the proof required for a claim
+type QueryRequiredProofParams struct {
+	Address sdk.Address   `json:"address"`
+	Header  SessionHeader `json:"header"`
+	Type    string        `json:"type"`
+}
+
+// "RequiredProof" - The leaf index a claim must be proven with and the number of levels its merkle proof must have
+type RequiredProof struct {
+	Index      int64 `json:"index"`
+	LevelCount int   `json:"level_count"`
+}
+
+// "QueryProofBacklogParams" - The parameters needed to retrieve the claim/proof backlog of a node
+type QueryProofBacklogParams struct {
+	Address sdk.Address `json:"address"`
+}
+
+// "ProofBacklog" - The work of a node that is still waiting on a claim or a proof
+type ProofBacklog struct {
+	PendingClaims  int `json:"pending_claims"`  // evidence not claimed yet (only known for the nodes hosted by this process)
+	ImmatureClaims int `json:"immature_claims"` // claims still in their waiting period
+	MatureUnproven int `json:"mature_unproven"` // mature claims awaiting their proof
+}
+
+// "QueryExpiringClaimsParams" - The parameters needed to retrieve the claims of an address that are about to expire
+type QueryExpiringClaimsParams struct {
+	Address sdk.Address `json:"address"`
+	Within  int64       `json:"within"` // the number of sessions from the current height
+}
+
+// "QueryClaimsInHeightRangeParams" - The parameters needed to retrieve the claims of an address between two session heights
+type QueryClaimsInHeightRangeParams struct {
+	Address     sdk.Address `json:"address"`
+	StartHeight int64       `json:"start_height"` // inclusive
+	EndHeight   int64       `json:"end_height"`   // inclusive
+}
+
+// "QueryServedChainsParams" - The parameters needed to retrieve the chains an address holds claims for
+type QueryServedChainsParams struct {
+	Address sdk.Address `json:"address"`
+}
+
+// "QueryProofByHashParams" - The parameters needed to locate a proof by its leaf hash in the evidence of a node
+type QueryProofByHashParams struct {
+	Address sdk.Address   `json:"address"`
+	Header  SessionHeader `json:"header"`
+	Type    string        `json:"type"`
+	Hash    string        `json:"hash"` // hex encoded leaf hash
+}
+
+// "ProofLocation" - Whether a proof is held in the evidence of a node and its index there
+type ProofLocation struct {
+	Index int  `json:"index"`
+	Found bool `json:"found"`
+}
+
+// "QueryClaimTimelineParams" - The parameters needed to compute when a session's claim can be submitted and proven
+type QueryClaimTimelineParams struct {
+	Address sdk.Address   `json:"address"`
+	Header  SessionHeader `json:"header"`
+	Type    string        `json:"type"`
+}
+
+// "QueryClaimRootParams" - The parameters needed to query the merkle root of a stored claim
+type QueryClaimRootParams struct {
+	Address sdk.Address   `json:"address"`
+	Header  SessionHeader `json:"header"`
+	Type    string        `json:"type"`
+}
+
+// "QueryValidateProofParams" - The parameters needed to validate a proof against the stored claim of its session
+type QueryValidateProofParams struct {
+	Proof MsgProof `json:"proof"`
+}
+
+// "ProofValidation" - Whether a proof would validate against the stored claim, and if not the error it would fail with
+type ProofValidation struct {
+	Valid     bool              `json:"valid"`
+	Servicer  sdk.Address       `json:"servicer,omitempty"`
+	Code      sdk.CodeType      `json:"code,omitempty"`
+	Codespace sdk.CodespaceType `json:"codespace,omitempty"`
+	Error     string            `json:"error,omitempty"`
+}
+
+// "ClaimTimeline" - When the claim of a session can be submitted, proven and when it expires
+type ClaimTimeline struct {
+	ClaimableNow     bool  `json:"claimable_now"`      // the session is over, not claimed yet and the claim is not mature
+	ProvableAtHeight int64 `json:"provable_at_height"` // the first height the claim is mature at
+	ExpiresAtHeight  int64 `json:"expires_at_height"`  // the height the claim is deleted at if it is not proven
+}
+
+// "ProofParams" - The parameters governing the claim and proof lifecycle, read at a single height
+type ProofParams struct {
+	BlocksPerSession            int64 `json:"blocks_per_session"`               // from the pos module
+	ClaimSubmissionWindow       int64 `json:"proof_waiting_period"`             // sessions a claim waits before it can be proven
+	ClaimExpiration             int64 `json:"claim_expiration"`                 // sessions a claim lives before it expires
+	MinimumNumberOfProofs       int64 `json:"minimum_number_of_proofs"`         // relays required for a claim
+	MaxClaimsDeletedPerBlock    int64 `json:"max_claims_deleted_per_block"`     // 0 is unlimited
+	MaxProofsPerAddressPerBlock int64 `json:"max_proofs_per_address_per_block"` // 0 is unlimited
+	MaxRelaysPerSession         int64 `json:"max_relays_per_session"`           // 0 is unlimited
+}
+
+// "QueryVerifiedRelaysParams" - The parameters needed to query the relays verified for a servicer as of a height
+type QueryVerifiedRelaysParams struct {
+	Address sdk.Address `json:"address"`
+	Height  int64       `json:"height"`
+}
+
+// "QueryClaimsByChainParams" - The parameters needed to retrieve a page of the claims for a chain across all addresses
+type QueryClaimsByChainParams struct {
+	Chain string `json:"chain"`
+	Page  int    `json:"page"`  // 1-indexed
+	Limit int    `json:"limit"` // 0 is DefaultClaimsPageLimit
+}
+
+// "DefaultClaimsPageLimit" - The page size of a claims query without a limit
+const DefaultClaimsPageLimit = 100
+
+// "ClaimsPage" - A page of claims
+type ClaimsPage struct {
+	Result []MsgClaim `json:"result"`
+	Total  int        `json:"total_pages"`
+	Page   int        `json:"page"`
+}
+
+// "ProofModuleStats" - The aggregate statistics of the proof module at a height
+type ProofModuleStats struct {
+	Height              int64 `json:"height"`
+	TotalClaims         int64 `json:"total_claims"`          // relay and challenge claims held in the state
+	TotalRelays         int64 `json:"total_relays"`          // relays of the relay claims held in the state
+	TotalVerifiedRelays int64 `json:"total_verified_relays"` // relays proven and rewarded since VRELS was activated
+	DistinctServicers   int64 `json:"distinct_servicers"`    // servicers holding claims or verified relays
+	DistinctChains      int64 `json:"distinct_chains"`       // chains of the claims held in the state
+}
+
+// "QueryMerklePathParams" - The parameters needed to retrieve the merkle path of a leaf of a node's stored claim
+type QueryMerklePathParams struct {
+	Address sdk.Address   `json:"address"`
+	Header  SessionHeader `json:"header"`
+	Type    string        `json:"type"`
+	Index   int64         `json:"index"` // the leaf index in the sorted merkle tree
+}
+
+// "MerklePath" - The hash ranges a client combines, in order, from the leaf's target to the stored claim's root
+type MerklePath struct {
+	Index      int64       `json:"index"`       // the leaf index; its parity at each level is the side of the node
+	Target     HashRange   `json:"target"`      // the hash range of the leaf
+	Path       []HashRange `json:"path"`        // the siblings, from the leaf level up
+	Root       HashRange   `json:"root"`        // the root of the stored claim
+	SortedPair bool        `json:"sorted_pair"` // siblings are sorted before hashing (see IsSortedPairMerkle)
+}
+
+// "QueryClaimsBySessionParams" - The parameters needed to retrieve the claims of an address grouped by session
+type QueryClaimsBySessionParams struct {
+	Address sdk.Address `json:"address"`
+}
+
+// "SessionClaims" - The claims of an address for the sessions that began at a height
+type SessionClaims struct {
+	SessionBlockHeight int64      `json:"session_block_height"`
+	Claims             []MsgClaim `json:"claims"`
+}
+
+// "QueryClaimExpirationParams" - The parameters needed to recompute the expiration height of a stored claim
+type QueryClaimExpirationParams struct {
+	Address sdk.Address   `json:"address"`
+	Header  SessionHeader `json:"header"`
+	Type    string        `json:"type"`
+}
+
+// "ClaimExpiration" - The expiration height stored with a claim and the one it would have under the current params
+type ClaimExpiration struct {
+	StoredExpirationHeight  int64 `json:"stored_expiration_height"`  // fixed when the claim was set; the claim is deleted at it
+	CurrentExpirationHeight int64 `json:"current_expiration_height"` // recomputed with the current ClaimExpiration and BlocksPerSession
+}
+
+// "QueryClaimFeesSpentParams" - The parameters needed to retrieve the fees a node has spent on claim and proof txs
+type QueryClaimFeesSpentParams struct {
+	Address sdk.Address `json:"address"`
+}
+
+// "QueryImmatureClaimsParams" - The parameters needed to retrieve the claims of an address still in their waiting period
+type QueryImmatureClaimsParams struct {
+	Address sdk.Address `json:"address"`
+}
+
+// "ImmatureClaim" - A claim still in its waiting period and the number of blocks until it is mature
+type ImmatureClaim struct {
+	Claim            MsgClaim `json:"claim"`
+	BlocksToMaturity int64    `json:"blocks_to_maturity"`
+}
+
+// "QueryCachedRelaysByChainParams" - The parameters needed to retrieve the relays per chain held in a node's evidence cache
+type QueryCachedRelaysByChainParams struct {
+	Address sdk.Address `json:"address"`
+}
+
+// "QueryIsClaimantParams" - The parameters needed to check whether an address is the claimant of a session
+type QueryIsClaimantParams struct {
+	Address sdk.Address   `json:"address"`
+	Header  SessionHeader `json:"header"`
+}
+
+// "Claimant" - Whether an address has a relay claim of a session, and whether the claim is mature so its proof can be
+// verified; a verified proof deletes the claim, so query a height before the proof to resolve a proven session
+type Claimant struct {
+	IsClaimant bool `json:"is_claimant"`
+	Mature     bool `json:"mature"`
+}
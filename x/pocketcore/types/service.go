@@ -67,12 +67,12 @@ func (r *Relay) Validate(ctx sdk.Ctx, posKeeper PosKeeper, appsKeeper AppsKeeper
 		SessionBlockHeight: r.Proof.SessionBlockHeight,
 	}
 	// validate unique relay
-	evidence, totalRelays := GetTotalProofs(header, RelayEvidence, maxPossibleRelays, node.EvidenceStore)
-	if node.EvidenceStore.IsSealed(evidence) {
+	sealed, unique, totalRelays := checkProof(header, RelayEvidence, r.Proof, maxPossibleRelays, node.EvidenceStore)
+	if sealed {
 		return sdk.ZeroInt(), NewSealedEvidenceError(ModuleName)
 	}
 	// get evidence key by proof
-	if !IsUniqueProof(r.Proof, evidence) {
+	if !unique {
 		return sdk.ZeroInt(), NewDuplicateProofError(ModuleName)
 	}
 	// validate not over service
@@ -222,11 +222,12 @@ func TestRelay_HandleProof(t *testing.T) {
 	}
 	validRelay.Proof.RequestHash = validRelay.RequestHashString()
 	validRelay.Proof.Store(sdk.NewInt(100000), GlobalEvidenceCache)
-	res := GetProof(SessionHeader{
+	res, found := GetProof(SessionHeader{
 		ApplicationPubKey:  appPubKey,
 		Chain:              ethereum,
 		SessionBlockHeight: 1,
 	}, RelayEvidence, 0, GlobalEvidenceCache)
+	assert.True(t, found)
 	assert.True(t, reflect.DeepEqual(validRelay.Proof, res))
 }
 
@@ -356,6 +357,10 @@ func (m MockPosKeeper) RewardForRelays(ctx sdk.Ctx, relays sdk.BigInt, address s
 	panic("implement me")
 }
 
+func (m MockPosKeeper) EstimateRewardForRelays(ctx sdk.Ctx, relays sdk.BigInt, address sdk.Address) sdk.BigInt {
+	panic("implement me")
+}
+
 func (m MockPosKeeper) GetStakedTokens(ctx sdk.Ctx) sdk.BigInt {
 	panic("implement me")
 }
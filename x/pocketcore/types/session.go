@@ -4,10 +4,12 @@ import (
 	"encoding/hex"
 	"encoding/json"
 	"fmt"
+	"github.com/pokt-network/pocket-core/codec"
 	sdk "github.com/pokt-network/pocket-core/types"
 	appexported "github.com/pokt-network/pocket-core/x/apps/exported"
 	"github.com/pokt-network/pocket-core/x/nodes/exported"
 	"log"
+	"strings"
 )
 
 // "Session" - The relationship between an application and the pocket network
@@ -251,6 +253,23 @@ func (sh SessionHeader) HashString() string {
 	return hex.EncodeToString(sh.Hash())
 }
 
+// "Canonical" - The session header in its canonical form, that headers naming the same session are compared in
+// The fields are encoded in their declared order, so only the app public key has more than one representation: it is hex
+// and decodes the same in any case. It is lower cased for sessions that started at or after the CanonicalHeaderKey
+// activation height, so headers naming the same application, chain and session are always equal; older sessions keep
+// the header (and key) their claims were stored under
+func (sh SessionHeader) Canonical() SessionHeader {
+	if ModuleCdc.IsAfterNamedFeatureActivationHeight(sh.SessionBlockHeight, codec.CanonicalHeaderKey) {
+		sh.ApplicationPubKey = strings.ToLower(sh.ApplicationPubKey)
+	}
+	return sh
+}
+
+// "KeyHash" - The hash of the canonical session header, that the claim and evidence keys are derived from
+func (sh SessionHeader) KeyHash() []byte {
+	return sh.Canonical().Hash()
+}
+
 // "Bytes" - The bytes representation of the session header
 func (sh SessionHeader) Bytes() []byte {
 	res, err := json.Marshal(sh)
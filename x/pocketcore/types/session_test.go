@@ -2,8 +2,11 @@ package types
 
 import (
 	"encoding/hex"
-	"github.com/stretchr/testify/assert"
+	"strings"
 	"testing"
+
+	"github.com/pokt-network/pocket-core/codec"
+	"github.com/stretchr/testify/assert"
 )
 
 func TestNewSessionKey(t *testing.T) {
@@ -248,3 +251,45 @@ func TestSessionKey_Validate(t *testing.T) {
 //	assert.Nil(t, sessionNodes.Validate(5))
 //	assert.NotNil(t, SessionNodes(make([]exported.ValidatorI, 5)).Validate(5))
 //}
+
+func TestSessionHeader_KeyHash(t *testing.T) {
+	codec.UpgradeFeatureMap[codec.CanonicalHeaderKey] = 10
+	t.Cleanup(func() {
+		delete(codec.UpgradeFeatureMap, codec.CanonicalHeaderKey)
+	})
+	addr := getRandomValidatorAddress()
+	appPubKey := getRandomPubKey().RawString()
+	keys := func(header SessionHeader) (claimKey, evidenceKey []byte) {
+		claimKey, err := KeyForClaim(nil, addr, header, RelayEvidence)
+		assert.Nil(t, err)
+		evidenceKey, err = KeyForEvidence(header, RelayEvidence)
+		assert.Nil(t, err)
+		return
+	}
+	for _, sessionHeight := range []int64{1, 11} {
+		lower := SessionHeader{ApplicationPubKey: appPubKey, Chain: "0001", SessionBlockHeight: sessionHeight}
+		upper := SessionHeader{ApplicationPubKey: strings.ToUpper(appPubKey), Chain: "0001", SessionBlockHeight: sessionHeight}
+		assert.Nil(t, upper.ValidateHeader())
+		// the keys of a header are stable, and a lower case header (as generated by the protocol) keeps its key
+		claimKey, evidenceKey := keys(lower)
+		claimKey2, evidenceKey2 := keys(SessionHeader{SessionBlockHeight: sessionHeight, Chain: "0001", ApplicationPubKey: appPubKey})
+		assert.Equal(t, claimKey, claimKey2)
+		assert.Equal(t, evidenceKey, evidenceKey2)
+		assert.Equal(t, lower.Hash(), lower.KeyHash())
+		upperClaimKey, upperEvidenceKey := keys(upper)
+		if sessionHeight < 10 {
+			// sessions before the activation keep the keys they were stored under
+			assert.NotEqual(t, claimKey, upperClaimKey)
+			assert.NotEqual(t, evidenceKey, upperEvidenceKey)
+			continue
+		}
+		// the same application, chain and session share a key whatever the casing of the app public key
+		assert.Equal(t, claimKey, upperClaimKey)
+		assert.Equal(t, evidenceKey, upperEvidenceKey)
+		// a different chain or session does not
+		otherChain, _ := keys(SessionHeader{ApplicationPubKey: appPubKey, Chain: "0002", SessionBlockHeight: sessionHeight})
+		otherSession, _ := keys(SessionHeader{ApplicationPubKey: appPubKey, Chain: "0001", SessionBlockHeight: sessionHeight + 4})
+		assert.NotEqual(t, claimKey, otherChain)
+		assert.NotEqual(t, claimKey, otherSession)
+	}
+}